@@ -2,7 +2,11 @@ package main
 
 import (
 	"db-mcp/mcp"
+	"encoding/json"
+	"flag"
+	"fmt"
 	"log"
+	"os"
 
 	_ "github.com/denisenkom/go-mssqldb"
 	_ "github.com/go-sql-driver/mysql"
@@ -12,6 +16,15 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "verify_audit_log" {
+		verifyAuditLog(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "snapshot" {
+		snapshotSchema(os.Args[2:])
+		return
+	}
+
 	// Define MCP Server
 	mcpServer, err := mcp.NewMcpServer()
 	if err != nil {
@@ -26,3 +39,56 @@ func main() {
 		return
 	}
 }
+
+// verifyAuditLog checks the hash chain of an audit log and exits with status 1 when it was tampered with
+func verifyAuditLog(args []string) {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "usage: db-mcp verify_audit_log <path>")
+		os.Exit(2)
+	}
+
+	result, err := mcp.VerifyAuditLog(args[0])
+	if err != nil {
+		log.Fatalf("Error verifying audit log: %v", err)
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		log.Fatalf("Error serializing result: %v", err)
+	}
+	fmt.Println(string(jsonData))
+
+	if !result.Valid() {
+		os.Exit(1)
+	}
+}
+
+// snapshotSchema saves a schema snapshot, for use from cron; the output reports
+// whether the snapshot file changed
+func snapshotSchema(args []string) {
+	flags := flag.NewFlagSet("snapshot", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: db-mcp snapshot [-datasource name] [-schema name] [-name name] [-timestamp]")
+		flags.PrintDefaults()
+	}
+	datasource := flags.String("datasource", "", "declared datasource (default: the DB_CONNECTION_STRING database)")
+	schema := flags.String("schema", "", "schema name (default: the default schema of the datasource)")
+	name := flags.String("name", "", "snapshot name (default: <datasource>_<schema>)")
+	timestamp := flags.Bool("timestamp", false, "append the UTC time to the snapshot name")
+	flags.Parse(args)
+	if flags.NArg() > 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	info, written, err := mcp.SnapshotSchema(*datasource, *schema, *name, *timestamp)
+	if err != nil {
+		log.Fatalf("Error taking snapshot: %v", err)
+	}
+
+	jsonData, err := json.MarshalIndent(map[string]interface{}{"snapshot": info, "written": written}, "", "  ")
+	if err != nil {
+		log.Fatalf("Error serializing result: %v", err)
+	}
+	fmt.Println(string(jsonData))
+}
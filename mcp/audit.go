@@ -0,0 +1,531 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// auditGenesisHash is the previous hash of the first entry of an audit log
+var auditGenesisHash = strings.Repeat("0", 64)
+
+// auditHashField separates the hashed body of an audit line from its hash
+const auditHashField = `,"hash":"`
+
+// AuditEntry is one tool call in the audit log
+type AuditEntry struct {
+	Seq           uint64           `json:"seq"`
+	Timestamp     time.Time        `json:"timestamp"`
+	SessionID     string           `json:"session_id,omitempty"`
+	ClientName    string           `json:"client_name,omitempty"`
+	ClientVersion string           `json:"client_version,omitempty"`
+	Datasource    string           `json:"datasource,omitempty"`
+	Driver        string           `json:"driver,omitempty"`
+	Tool          string           `json:"tool"`
+	Statements    []AuditStatement `json:"statements,omitempty"`
+	RowCount      *int             `json:"row_count,omitempty"`
+	DurationMs    int64            `json:"duration_ms"`
+	Outcome       string           `json:"outcome"`
+	Error         string           `json:"error,omitempty"`
+	PrevHash      string           `json:"prev_hash"`
+	// Hash is the HMAC-SHA256 of the line up to this field, written last
+	Hash string `json:"hash,omitempty"`
+}
+
+// AuditStatement is a SQL statement run by a tool call
+type AuditStatement struct {
+	SQL    string        `json:"sql"`
+	Params []interface{} `json:"params,omitempty"`
+}
+
+// AuditLog writes hash-chained JSON lines, rotating the file when it grows too large.
+// Rotated files are named <path>.1 (newest) to <path>.<max_files>. Entry hashes are
+// keyed, so that the chain cannot be rebuilt after an edit without the key.
+type AuditLog struct {
+	mu       sync.Mutex
+	path     string
+	key      []byte
+	maxSize  int64
+	maxFiles int
+	file     *os.File
+	size     int64
+	seq      uint64
+	lastHash string
+}
+
+// OpenAuditLog opens the audit log, continuing the hash chain of its last entry
+func OpenAuditLog(config *AuditConfig) (*AuditLog, error) {
+	key := config.hashKey()
+	if len(key) == 0 {
+		return nil, ErrAuditKeyMissing
+	}
+	a := &AuditLog{
+		path:     config.Path,
+		key:      key,
+		maxSize:  int64(config.MaxSizeMB) * 1024 * 1024,
+		maxFiles: config.MaxFiles,
+		lastHash: auditGenesisHash,
+	}
+	if a.maxSize <= 0 {
+		a.maxSize = DefaultAuditMaxSizeMB * 1024 * 1024
+	}
+	if a.maxFiles <= 0 {
+		a.maxFiles = DefaultAuditMaxFiles
+	}
+
+	// Resume the chain from the current file, or from the newest rotated one
+	for _, path := range []string{a.path, a.path + ".1"} {
+		last, err := lastAuditEntry(path, a.key)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrOpeningAuditLog, err)
+		}
+		if last != nil {
+			a.seq, a.lastHash = last.Seq, last.Hash
+			break
+		}
+	}
+
+	if err := a.open(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (a *AuditLog) open() error {
+	file, err := os.OpenFile(a.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrOpeningAuditLog, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("%w: %v", ErrOpeningAuditLog, err)
+	}
+	a.file, a.size = file, info.Size()
+	return nil
+}
+
+// lastAuditEntry returns the last valid entry of a file, nil when the file is missing or empty
+func lastAuditEntry(path string, key []byte) (*AuditEntry, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var last *AuditEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), MaxAuditLineSize)
+	for scanner.Scan() {
+		entry, err := parseAuditLine(scanner.Text(), key)
+		if err != nil {
+			log.Printf("Audit log: skipping invalid line in %s: %v\n", path, err)
+			continue
+		}
+		last = entry
+	}
+	return last, scanner.Err()
+}
+
+// Write chains, serializes and appends an entry
+func (a *AuditLog) Write(entry *AuditEntry) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	entry.Seq = a.seq + 1
+	entry.PrevHash = a.lastHash
+	entry.Hash = ""
+
+	body, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	hash := auditHash(a.key, body)
+	line := string(body[:len(body)-1]) + auditHashField + hash + "\"}\n"
+
+	if a.size > 0 && a.size+int64(len(line)) > a.maxSize {
+		if err := a.rotate(); err != nil {
+			return err
+		}
+	}
+
+	if _, err := a.file.WriteString(line); err != nil {
+		return err
+	}
+	a.size += int64(len(line))
+	a.seq, a.lastHash = entry.Seq, hash
+	entry.Hash = hash
+	return nil
+}
+
+// rotate shifts <path>.N to <path>.N+1, dropping the oldest, and starts a new file.
+// The chain continues across files; the last entry dropped is kept in <path>.anchor.
+func (a *AuditLog) rotate() error {
+	// Record the end of the chain dropped with the oldest file
+	oldest := fmt.Sprintf("%s.%d", a.path, a.maxFiles)
+	last, err := lastAuditEntry(oldest, a.key)
+	if err != nil {
+		return err
+	}
+	if last != nil {
+		if err := writeAuditAnchor(a.path, a.key, last); err != nil {
+			return err
+		}
+	}
+	if err := a.file.Close(); err != nil {
+		return err
+	}
+	os.Remove(oldest)
+	for i := a.maxFiles - 1; i >= 1; i-- {
+		os.Rename(fmt.Sprintf("%s.%d", a.path, i), fmt.Sprintf("%s.%d", a.path, i+1))
+	}
+	if err := os.Rename(a.path, a.path+".1"); err != nil {
+		return err
+	}
+	return a.open()
+}
+
+// Close closes the audit log file
+func (a *AuditLog) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.file.Close()
+}
+
+// auditAnchor is the last entry of the rotated files dropped from an audit log, which
+// the first entry kept must follow
+type auditAnchor struct {
+	Seq  uint64 `json:"seq"`
+	Hash string `json:"hash"`
+}
+
+// writeAuditAnchor replaces the anchor of an audit log with a signed copy of entry
+func writeAuditAnchor(path string, key []byte, entry *AuditEntry) error {
+	body, err := json.Marshal(auditAnchor{Seq: entry.Seq, Hash: entry.Hash})
+	if err != nil {
+		return err
+	}
+	line := string(body[:len(body)-1]) + auditHashField + auditHash(key, body) + "\"}\n"
+	tmp := path + AuditAnchorSuffix + ".tmp"
+	if err := os.WriteFile(tmp, []byte(line), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path+AuditAnchorSuffix)
+}
+
+// readAuditAnchor returns the anchor of an audit log, nil when no file was dropped
+func readAuditAnchor(path string, key []byte) (*auditAnchor, error) {
+	data, err := os.ReadFile(path + AuditAnchorSuffix)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	line := strings.TrimSpace(string(data))
+	idx := strings.LastIndex(line, auditHashField)
+	if idx < 0 || !strings.HasSuffix(line, "\"}") {
+		return nil, ErrAuditEntryUnsigned
+	}
+	body := line[:idx] + "}"
+	if !hmac.Equal([]byte(auditHash(key, []byte(body))), []byte(line[idx+len(auditHashField):len(line)-2])) {
+		return nil, ErrAuditEntryModified
+	}
+	var anchor auditAnchor
+	if err := json.Unmarshal([]byte(body), &anchor); err != nil {
+		return nil, err
+	}
+	return &anchor, nil
+}
+
+// auditHash returns the hex HMAC-SHA256 of an entry body
+func auditHash(key, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// parseAuditLine parses a line and checks its hash. On a hash mismatch the entry is
+// still returned along with ErrAuditEntryModified.
+func parseAuditLine(line string, key []byte) (*AuditEntry, error) {
+	idx := strings.LastIndex(line, auditHashField)
+	if idx < 0 || !strings.HasSuffix(line, "\"}") {
+		return nil, ErrAuditEntryUnsigned
+	}
+	body := line[:idx] + "}"
+	hash := line[idx+len(auditHashField) : len(line)-2]
+
+	var entry AuditEntry
+	if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		return nil, err
+	}
+	if !hmac.Equal([]byte(auditHash(key, []byte(body))), []byte(hash)) || entry.Hash != hash {
+		return &entry, ErrAuditEntryModified
+	}
+	return &entry, nil
+}
+
+// AuditVerification is the result of checking an audit log and its rotated files
+type AuditVerification struct {
+	Files    []string `json:"files"`
+	Entries  int      `json:"entries"`
+	FirstSeq uint64   `json:"first_seq"`
+	LastSeq  uint64   `json:"last_seq"`
+	LastHash string   `json:"last_hash"`
+	Problems []string `json:"problems"`
+}
+
+// Valid reports whether no problems were found
+func (v *AuditVerification) Valid() bool {
+	return len(v.Problems) == 0
+}
+
+// VerifyAuditLog checks the hash chain of an audit log with the hash key of the
+// DB_MCP_CONFIG file, or DB_MCP_AUDIT_KEY
+func VerifyAuditLog(path string) (*AuditVerification, error) {
+	config, err := LoadConfig(os.Getenv("DB_MCP_CONFIG"))
+	if err != nil {
+		return nil, err
+	}
+	audit := config.Audit
+	if audit == nil {
+		audit = &AuditConfig{}
+	}
+	key := audit.hashKey()
+	if len(key) == 0 {
+		return nil, ErrAuditKeyMissing
+	}
+	return verifyAuditLog(path, key)
+}
+
+// verifyAuditLog checks the hash chain of an audit log from its oldest rotated file
+// to the current one, reporting edited entries, gaps and broken links.
+// When older files were rotated out, the chain is checked from the anchor they left;
+// a chain starting after seq 1 without one is reported as missing entries.
+// Entries cut from the end of the current file leave no trace in the chain: only
+// comparing LastSeq with a copy kept elsewhere can detect them.
+func verifyAuditLog(path string, key []byte) (*AuditVerification, error) {
+	// Rotated files, oldest (highest number) first
+	matches, err := filepath.Glob(path + ".*")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOpeningAuditLog, err)
+	}
+	numbers := make(map[string]int)
+	var files []string
+	for _, match := range matches {
+		if n, err := strconv.Atoi(strings.TrimPrefix(match, path+".")); err == nil && n > 0 {
+			numbers[match] = n
+			files = append(files, match)
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return numbers[files[i]] > numbers[files[j]] })
+	if _, err := os.Stat(path); err == nil {
+		files = append(files, path)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrOpeningAuditLog, path)
+	}
+
+	result := &AuditVerification{Files: files}
+	var prev *AuditEntry
+	// Rotation may have dropped the start of the chain: the first entry kept follows the anchor
+	anchor, err := readAuditAnchor(path, key)
+	if err != nil {
+		result.Problems = append(result.Problems, fmt.Sprintf("%s: %v", path+AuditAnchorSuffix, err))
+	} else if anchor != nil {
+		prev = &AuditEntry{Seq: anchor.Seq, Hash: anchor.Hash}
+	}
+	for _, name := range files {
+		if err := verifyAuditFile(name, key, result, &prev); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrOpeningAuditLog, err)
+		}
+	}
+	if prev != nil {
+		result.LastSeq, result.LastHash = prev.Seq, prev.Hash
+	}
+	return result, nil
+}
+
+func verifyAuditFile(name string, key []byte, result *AuditVerification, prev **AuditEntry) error {
+	file, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), MaxAuditLineSize)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		location := fmt.Sprintf("%s:%d", name, lineNo)
+		entry, err := parseAuditLine(scanner.Text(), key)
+		if err != nil {
+			result.Problems = append(result.Problems, fmt.Sprintf("%s: %v", location, err))
+			if entry == nil {
+				continue
+			}
+		}
+
+		if result.Entries == 0 {
+			result.FirstSeq = entry.Seq
+		}
+		switch {
+		case *prev == nil && entry.Seq != 1:
+			result.Problems = append(result.Problems,
+				fmt.Sprintf("%s: entries 1 to %d are missing and no rotation dropped them", location, entry.Seq-1))
+		case *prev == nil:
+			if entry.PrevHash != auditGenesisHash {
+				result.Problems = append(result.Problems, fmt.Sprintf("%s: first entry does not start the chain", location))
+			}
+		case entry.Seq != (*prev).Seq+1:
+			result.Problems = append(result.Problems,
+				fmt.Sprintf("%s: gap in sequence, expected %d, found %d", location, (*prev).Seq+1, entry.Seq))
+		case entry.PrevHash != (*prev).Hash:
+			result.Problems = append(result.Problems,
+				fmt.Sprintf("%s: broken chain, previous hash does not match entry %d", location, (*prev).Seq))
+		}
+
+		result.Entries++
+		*prev = entry
+	}
+	return scanner.Err()
+}
+
+type callRecordKey struct{}
+
+// callRecord collects what a tool call does with the database, for the audit log and quotas
+type callRecord struct {
+	mu         sync.Mutex
+	statements []AuditStatement
+	rowCount   *int
+}
+
+// withCallRecord returns the record of the current tool call, adding one to the context if needed
+func withCallRecord(ctx context.Context) (context.Context, *callRecord) {
+	if record, ok := ctx.Value(callRecordKey{}).(*callRecord); ok {
+		return ctx, record
+	}
+	record := &callRecord{}
+	return context.WithValue(ctx, callRecordKey{}, record), record
+}
+
+// auditStatement records a SQL statement run by the current tool call
+func auditStatement(ctx context.Context, query string, params []interface{}) {
+	if record, ok := ctx.Value(callRecordKey{}).(*callRecord); ok {
+		record.mu.Lock()
+		record.statements = append(record.statements, AuditStatement{SQL: query, Params: params})
+		record.mu.Unlock()
+	}
+}
+
+// auditRowCount records the number of rows returned by the current tool call
+func auditRowCount(ctx context.Context, count int) {
+	if record, ok := ctx.Value(callRecordKey{}).(*callRecord); ok {
+		record.mu.Lock()
+		record.rowCount = &count
+		record.mu.Unlock()
+	}
+}
+
+// auditMiddleware writes an audit entry for every tool call
+func (s *DbMCPServer) auditMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if s.audit == nil {
+			return next(ctx, request)
+		}
+
+		ctx, record := withCallRecord(ctx)
+		start := time.Now()
+		result, err := next(ctx, request)
+
+		entry := &AuditEntry{
+			Timestamp:  start.UTC(),
+			Datasource: s.datasource,
+			Tool:       request.Params.Name,
+			RowCount:   record.rowCount,
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+		if s.queryBuilder != nil {
+			entry.Driver = string(s.queryBuilder.GetDriver())
+		}
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			entry.SessionID = session.SessionID()
+			if withInfo, ok := session.(server.SessionWithClientInfo); ok {
+				info := withInfo.GetClientInfo()
+				entry.ClientName, entry.ClientVersion = info.Name, info.Version
+			}
+		}
+
+		masker := s.masker()
+		redact := s.config != nil && s.config.Audit != nil && s.config.Audit.RedactParams
+		for _, st := range record.statements {
+			entry.Statements = append(entry.Statements, AuditStatement{SQL: st.SQL, Params: redactAuditParams(st.Params, masker, redact)})
+		}
+
+		entry.Outcome, entry.Error = callOutcome(result, err)
+
+		if werr := s.audit.Write(entry); werr != nil {
+			log.Printf("Audit log: could not write entry for %s: %v\n", entry.Tool, werr)
+		}
+		return result, err
+	}
+}
+
+// callOutcome returns "success" or "error" and the error text of a tool call
+func callOutcome(result *mcp.CallToolResult, err error) (string, string) {
+	switch {
+	case err != nil:
+		return "error", err.Error()
+	case result != nil && result.IsError:
+		for _, content := range result.Content {
+			if text, ok := content.(mcp.TextContent); ok {
+				return "error", text.Text
+			}
+		}
+		return "error", ""
+	}
+	return "success", ""
+}
+
+// redactAuditParams hides bound parameters: all of them when redact is set,
+// otherwise the parts matched by the masking detectors of the datasource
+func redactAuditParams(params []interface{}, masker *Masker, redact bool) []interface{} {
+	if len(params) == 0 || (!redact && masker == nil) {
+		return params
+	}
+
+	redacted := make([]interface{}, len(params))
+	for i, p := range params {
+		switch {
+		case p == nil:
+			redacted[i] = nil
+		case redact:
+			redacted[i] = MaskRedactedValue
+		default:
+			redacted[i] = p
+			if text, ok := p.(string); ok {
+				if value, changed := masker.detect(text); changed {
+					redacted[i] = value
+				}
+			}
+		}
+	}
+	return redacted
+}
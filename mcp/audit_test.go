@@ -0,0 +1,111 @@
+package mcp
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAuditLogKeyedChain(t *testing.T) {
+	t.Setenv("DB_MCP_AUDIT_KEY", "")
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	if _, err := OpenAuditLog(&AuditConfig{Path: path}); !errors.Is(err, ErrAuditKeyMissing) {
+		t.Fatalf("OpenAuditLog without key = %v, want %v", err, ErrAuditKeyMissing)
+	}
+
+	audit, err := OpenAuditLog(&AuditConfig{Path: path, HashKey: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tool := range []string{"list_tables", "execute_query", "list_tables"} {
+		if err := audit.Write(&AuditEntry{Tool: tool, Outcome: "success"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	audit.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edited := filepath.Join(t.TempDir(), "edited.jsonl")
+	if err := os.WriteFile(edited, []byte(strings.Replace(string(data), "execute_query", "list_views", 1)), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		path  string
+		key   string
+		valid bool
+	}{
+		{"intact", path, "secret", true},
+		{"other key", path, "guess", false},
+		{"edited entry", edited, "secret", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := verifyAuditLog(tt.path, []byte(tt.key))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Valid() != tt.valid {
+				t.Fatalf("verifyAuditLog(%s) problems = %v, want valid %v", tt.name, result.Problems, tt.valid)
+			}
+		})
+	}
+}
+
+func TestVerifyAuditLogRotation(t *testing.T) {
+	key := []byte("secret")
+	write := func(t *testing.T, path string, entries int) {
+		audit, err := OpenAuditLog(&AuditConfig{Path: path, HashKey: string(key), MaxFiles: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		audit.maxSize = 1 // one entry per file
+		for i := 0; i < entries; i++ {
+			if err := audit.Write(&AuditEntry{Tool: "list_tables", Outcome: "success"}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		audit.Close()
+	}
+
+	tests := []struct {
+		name     string
+		entries  int
+		remove   []string
+		valid    bool
+		firstSeq uint64
+	}{
+		{"no rotation dropped", 3, nil, true, 1},
+		{"rotation dropped entries", 5, nil, true, 3},
+		{"oldest file deleted", 3, []string{".2"}, false, 2},
+		{"anchor deleted", 5, []string{".anchor"}, false, 3},
+		{"anchor and oldest file deleted", 5, []string{".anchor", ".2"}, false, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "audit.jsonl")
+			write(t, path, tt.entries)
+			for _, suffix := range tt.remove {
+				if err := os.Remove(path + suffix); err != nil {
+					t.Fatal(err)
+				}
+			}
+			result, err := verifyAuditLog(path, key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Valid() != tt.valid || result.FirstSeq != tt.firstSeq {
+				t.Fatalf("verifyAuditLog() first seq %d, problems %v; want first seq %d, valid %v",
+					result.FirstSeq, result.Problems, tt.firstSeq, tt.valid)
+			}
+		})
+	}
+}
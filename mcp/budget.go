@@ -0,0 +1,145 @@
+package mcp
+
+import (
+	"encoding/json"
+	"unicode/utf8"
+)
+
+// TruncatedCell describes a cell value cut to the cell budget
+type TruncatedCell struct {
+	Row           int    `json:"row"`
+	Column        string `json:"column"`
+	Bytes         int    `json:"bytes"`
+	ReturnedBytes int    `json:"returned_bytes"`
+}
+
+// ResultBudget limits the size of the rows of a response: long text cells are cut
+// to MaxCellBytes and rows stop being added once MaxBytes would be exceeded
+type ResultBudget struct {
+	MaxBytes     int
+	MaxCellBytes int
+	// limit names the argument behind MaxBytes, reported as the truncation reason
+	limit     string
+	used      int
+	exhausted bool
+	truncated []TruncatedCell
+}
+
+// resultBudgetProperties adds the result budget arguments to a tool input schema
+func resultBudgetProperties(properties map[string]interface{}) map[string]interface{} {
+	properties["max_bytes"] = map[string]interface{}{
+		"type":        "number",
+		"description": "Maximum size of the returned rows in bytes (default: 1048576, maximum: 16777216)",
+	}
+	properties["max_tokens"] = map[string]interface{}{
+		"type":        "number",
+		"description": "Approximate maximum size of the returned rows in tokens (about 4 bytes each, optional)",
+	}
+	properties["max_cell_bytes"] = map[string]interface{}{
+		"type":        "number",
+		"description": "Text values longer than this are truncated (default: 4096, maximum: 1048576)",
+	}
+	return properties
+}
+
+// newResultBudget reads the max_bytes, max_tokens and max_cell_bytes arguments
+func newResultBudget(args map[string]interface{}) *ResultBudget {
+	b := &ResultBudget{
+		MaxBytes:     getIntArg(args, "max_bytes", DefaultMaxResponseBytes),
+		MaxCellBytes: getIntArg(args, "max_cell_bytes", DefaultMaxCellBytes),
+		limit:        "max_bytes",
+	}
+	if b.MaxBytes <= 0 || b.MaxBytes > MaxResponseBytes {
+		b.MaxBytes = MaxResponseBytes
+	}
+	if tokens := getIntArg(args, "max_tokens", 0); tokens > 0 && tokens*BytesPerToken < b.MaxBytes {
+		b.MaxBytes = tokens * BytesPerToken
+		b.limit = "max_tokens"
+	}
+	if b.MaxCellBytes <= 0 || b.MaxCellBytes > MaxCellBytes {
+		b.MaxCellBytes = MaxCellBytes
+	}
+	if b.MaxCellBytes > b.MaxBytes {
+		b.MaxCellBytes = b.MaxBytes
+	}
+	return b
+}
+
+// Add truncates the long text cells of a row and reports whether the row fits in the
+// remaining budget. Once a row does not fit, the budget is exhausted. The first row is
+// always added, with its text cells cut further until it fits or they are empty, so
+// that a page always moves forward.
+func (b *ResultBudget) Add(columns []string, row map[string]interface{}, index int) bool {
+	if b.exhausted {
+		return false
+	}
+
+	texts := make(map[string]string)
+	for _, col := range columns {
+		if text, ok := row[col].(string); ok {
+			texts[col] = text
+		}
+	}
+
+	limit := b.MaxCellBytes
+	for {
+		cells := cutCells(columns, row, texts, limit, index)
+		encoded, err := json.Marshal(row)
+		if err != nil {
+			b.exhausted = true
+			return false
+		}
+		size := len(encoded)
+		if b.used+size <= b.MaxBytes || (b.used == 0 && limit == 0) {
+			b.used += size
+			b.truncated = append(b.truncated, cells...)
+			return true
+		}
+		if b.used > 0 {
+			b.exhausted = true
+			return false
+		}
+		limit /= 2
+	}
+}
+
+// cutCells sets the text cells of a row to their original value cut to limit bytes at a
+// character boundary, and returns the cells it cut
+func cutCells(columns []string, row map[string]interface{}, texts map[string]string, limit, index int) []TruncatedCell {
+	var cells []TruncatedCell
+	for _, col := range columns {
+		text, ok := texts[col]
+		if !ok {
+			continue
+		}
+		if len(text) <= limit {
+			row[col] = text
+			continue
+		}
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+		row[col] = text[:cut] + TruncationMarker
+		cells = append(cells, TruncatedCell{Row: index, Column: col, Bytes: len(text), ReturnedBytes: cut})
+	}
+	return cells
+}
+
+// Reason returns the argument whose limit stopped the rows, empty if none did
+func (b *ResultBudget) Reason() string {
+	if b.exhausted {
+		return b.limit
+	}
+	return ""
+}
+
+// Describe adds the truncation details to a response
+func (b *ResultBudget) Describe(response map[string]interface{}) {
+	if len(b.truncated) > 0 {
+		response["truncated_cells"] = b.truncated
+	}
+	if b.exhausted {
+		response["truncated_reason"] = b.limit
+	}
+}
@@ -0,0 +1,55 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResultBudgetAdd(t *testing.T) {
+	columns := []string{"id", "note"}
+	row := func(note string) map[string]interface{} {
+		return map[string]interface{}{"id": 1, "note": note}
+	}
+
+	tests := []struct {
+		name      string
+		maxBytes  int
+		rows      []string
+		added     int
+		truncated []int
+		exhausted bool
+	}{
+		{"fits", 1000, []string{"a", "b"}, 2, nil, false},
+		{"long cell", 1000, []string{strings.Repeat("x", 100)}, 1, []int{0}, false},
+		{"budget ends page", 45, []string{"a", "b", "c"}, 2, nil, true},
+		{"first row over budget", 30, []string{strings.Repeat("x", 50), "b"}, 1, []int{0}, true},
+		{"dropped row cells not recorded", 80, []string{"a", strings.Repeat("x", 100)}, 1, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			budget := &ResultBudget{MaxBytes: tt.maxBytes, MaxCellBytes: 64, limit: "max_bytes"}
+			added := 0
+			for i, note := range tt.rows {
+				if !budget.Add(columns, row(note), i) {
+					break
+				}
+				added++
+			}
+			if added != tt.added {
+				t.Fatalf("added %d rows, want %d", added, tt.added)
+			}
+			if len(budget.truncated) != len(tt.truncated) {
+				t.Fatalf("truncated cells = %+v, want rows %v", budget.truncated, tt.truncated)
+			}
+			for i, cell := range budget.truncated {
+				if cell.Row != tt.truncated[i] {
+					t.Fatalf("truncated cells = %+v, want rows %v", budget.truncated, tt.truncated)
+				}
+			}
+			if (budget.Reason() != "") != tt.exhausted {
+				t.Fatalf("Reason() = %q, want exhausted %v", budget.Reason(), tt.exhausted)
+			}
+		})
+	}
+}
@@ -0,0 +1,415 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SchemaCatalog is the metadata of a schema compared by diff_schema and stored in
+// snapshots, with Hash and TakenAt set only in snapshots
+type SchemaCatalog struct {
+	Version    int            `json:"version"`
+	Hash       string         `json:"hash,omitempty"`
+	TakenAt    string         `json:"taken_at,omitempty"`
+	Datasource string         `json:"datasource,omitempty"`
+	Driver     DriverType     `json:"driver"`
+	Schema     string         `json:"schema"`
+	Tables     []CatalogTable `json:"tables"`
+	Views      []CatalogCode  `json:"views"`
+	Procedures []CatalogCode  `json:"procedures"`
+	Functions  []CatalogCode  `json:"functions"`
+}
+
+// CatalogTable is a table of a schema catalog
+type CatalogTable struct {
+	Name        string              `json:"name"`
+	Columns     []CatalogColumn     `json:"columns"`
+	PrimaryKey  []string            `json:"primary_key,omitempty"`
+	Indexes     []CatalogIndex      `json:"indexes,omitempty"`
+	ForeignKeys []CatalogForeignKey `json:"foreign_keys,omitempty"`
+}
+
+// CatalogColumn is a column of a catalog table
+type CatalogColumn struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Nullable bool   `json:"nullable"`
+	Default  string `json:"default,omitempty"`
+}
+
+// CatalogIndex is an index of a catalog table, other than the one backing the primary key
+type CatalogIndex struct {
+	Name    string   `json:"name"`
+	Unique  bool     `json:"unique"`
+	Columns []string `json:"columns"`
+}
+
+// CatalogForeignKey is a foreign key of a catalog table
+type CatalogForeignKey struct {
+	Name       string   `json:"name"`
+	Columns    []string `json:"columns"`
+	RefSchema  string   `json:"referenced_schema,omitempty"`
+	RefTable   string   `json:"referenced_table"`
+	RefColumns []string `json:"referenced_columns"`
+	OnDelete   string   `json:"on_delete,omitempty"`
+	OnUpdate   string   `json:"on_update,omitempty"`
+}
+
+// CatalogCode is a view, procedure or function of a schema catalog with its source
+type CatalogCode struct {
+	Name       string `json:"name"`
+	Definition string `json:"definition"`
+}
+
+// table returns the table named name, or nil
+func (c *SchemaCatalog) table(name string) *CatalogTable {
+	for i := range c.Tables {
+		if strings.EqualFold(c.Tables[i].Name, name) {
+			return &c.Tables[i]
+		}
+	}
+	return nil
+}
+
+// catalogReader returns a server reading the catalog of a datasource: the active
+// one when name is empty or its own, else a declared datasource connected for
+// the call. close releases that connection.
+func (s *DbMCPServer) catalogReader(ctx context.Context, name string) (*DbMCPServer, func(), error) {
+	if name == "" || name == s.datasource {
+		if err := s.requireConnection(); err != nil {
+			return nil, nil, err
+		}
+		return s, func() {}, nil
+	}
+
+	if s.config.DeclaredDataSource(name) == nil {
+		return nil, nil, fmt.Errorf("%w: '%s'", ErrDataSourceNotDeclared, name)
+	}
+	target, err := s.connectionTarget(ctx, map[string]interface{}{"name": name}, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	db, err := sql.Open(string(target.dsn.Driver), target.resolved)
+	if err != nil {
+		return nil, nil, target.error(ErrConnectionFailed, err)
+	}
+	pingCtx, cancel := context.WithTimeout(ctx, DBPingTimeout)
+	defer cancel()
+	if err := db.PingContext(pingCtx); err != nil {
+		db.Close()
+		return nil, nil, target.error(ErrConnectionTestFailed, err)
+	}
+
+	reader := &DbMCPServer{
+		db:           db,
+		queryBuilder: NewQueryBuilder(string(target.dsn.Driver)),
+		config:       s.config,
+		datasource:   name,
+		profile:      name,
+	}
+	return reader, func() { db.Close() }, nil
+}
+
+// loadCatalog reads the tables, views, procedures and functions of a schema visible
+// under the policy; an empty schema is the default schema of the connection
+func (s *DbMCPServer) loadCatalog(ctx context.Context, schema string) (*SchemaCatalog, error) {
+	if schema == "" {
+		schema = getDefaultSchema(s.queryBuilder.GetDriver())
+	}
+	if schema == "" {
+		current, err := s.currentSchema(ctx)
+		if err != nil {
+			return nil, err
+		}
+		schema = current
+	}
+
+	catalog := &SchemaCatalog{
+		Version:    SchemaCatalogVersion,
+		Datasource: s.datasource,
+		Driver:     s.queryBuilder.GetDriver(),
+		Schema:     schema,
+		Tables:     []CatalogTable{},
+		Views:      []CatalogCode{},
+		Procedures: []CatalogCode{},
+		Functions:  []CatalogCode{},
+	}
+
+	tables, err := s.listSchemaTables(ctx, schema)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrListingTables, err)
+	}
+	for _, name := range tables {
+		table, err := s.catalogTable(ctx, schema, name)
+		if err != nil {
+			return nil, err
+		}
+		if table != nil {
+			catalog.Tables = append(catalog.Tables, *table)
+		}
+	}
+
+	if s.queryBuilder.SupportsViews() {
+		views, err := s.listSchemaViews(ctx, schema)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrListingViews, err)
+		}
+		for _, name := range views {
+			query, args := s.queryBuilder.GetViewDefinitionQuery(schema, name)
+			definition, err := s.readSourceCode(ctx, query, args)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s: %v", ErrRetrievingView, name, err)
+			}
+			if definition == "" {
+				materialized, _, found, err := s.materializedView(ctx, schema, name)
+				if err != nil {
+					return nil, fmt.Errorf("%w: %s: %v", ErrRetrievingView, name, err)
+				}
+				if found {
+					definition = normalizeSource(materialized.String)
+				}
+			}
+			catalog.Views = append(catalog.Views, CatalogCode{Name: name, Definition: definition})
+		}
+	}
+
+	if s.queryBuilder.SupportsStoredProcedures() {
+		query, args := s.queryBuilder.ListProceduresQuery(schema, "", MaxSchemaObjects, 0)
+		if catalog.Procedures, err = s.catalogRoutines(ctx, schema, query, args, s.queryBuilder.GetProcedureCodeQuery); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrListingProcedures, err)
+		}
+	}
+	if s.queryBuilder.SupportsFunctions() {
+		query, args := s.queryBuilder.ListFunctionsQuery(schema, "", "", MaxSchemaObjects, 0)
+		if catalog.Functions, err = s.catalogRoutines(ctx, schema, query, args, s.queryBuilder.GetFunctionCodeQuery); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrListingFunctions, err)
+		}
+	}
+
+	sort.Slice(catalog.Tables, func(i, j int) bool { return catalog.Tables[i].Name < catalog.Tables[j].Name })
+	for _, objects := range [][]CatalogCode{catalog.Views, catalog.Procedures, catalog.Functions} {
+		sort.Slice(objects, func(i, j int) bool { return objects[i].Name < objects[j].Name })
+	}
+	return catalog, nil
+}
+
+// catalogTable reads a table's columns, primary key, indexes and foreign keys,
+// leaving out what the policy hides. It returns nil when every column is hidden.
+func (s *DbMCPServer) catalogTable(ctx context.Context, schema, name string) (*CatalogTable, error) {
+	query, args := s.queryBuilder.GetTableSchemaFullQuery(schema, name)
+	auditStatement(ctx, query, args)
+	columns, err := s.fetchSchemaColumns(ctx, query, args)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrRetrievingColumns, name, err)
+	}
+	columns = s.visibleColumnMaps(ctx, schema, name, columns, "name")
+	if len(columns) == 0 {
+		return nil, nil
+	}
+
+	table := &CatalogTable{Name: name}
+	for _, col := range columns {
+		column := CatalogColumn{}
+		column.Name, _ = col["name"].(string)
+		column.Nullable, _ = col["nullable"].(bool)
+		column.Default, _ = col["default_value"].(string)
+		column.Default = strings.TrimSpace(column.Default)
+		column.Type = catalogColumnType(col)
+		if primary, _ := col["is_primary_key"].(bool); primary {
+			table.PrimaryKey = append(table.PrimaryKey, column.Name)
+			column.Nullable = false
+		}
+		table.Columns = append(table.Columns, column)
+	}
+
+	visible := func(columns []string) bool {
+		for _, col := range columns {
+			if !containsFold(table.columnNames(), col) {
+				return false
+			}
+		}
+		return true
+	}
+
+	indexes, err := s.getIndexes(ctx, schema, name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrRetrievingColumns, name, err)
+	}
+	for _, index := range indexes {
+		// The primary key's own index and SQLite's constraint indexes come with the table
+		if strings.HasPrefix(index.Name, "sqlite_autoindex_") ||
+			(index.Unique && len(table.PrimaryKey) > 0 && isUniqueKey([][]string{table.PrimaryKey}, index.Columns)) {
+			continue
+		}
+		if visible(index.Columns) {
+			table.Indexes = append(table.Indexes, CatalogIndex{Name: index.Name, Unique: index.Unique, Columns: index.Columns})
+		}
+	}
+	sort.Slice(table.Indexes, func(i, j int) bool { return table.Indexes[i].Name < table.Indexes[j].Name })
+
+	foreignKeys, err := s.getForeignKeys(ctx, schema, name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrRetrievingForeignKeys, name, err)
+	}
+	policy := s.policy()
+	for _, fk := range foreignKeys {
+		refSchema := fk.RefSchema
+		if refSchema == "" {
+			refSchema = schema
+		}
+		if !visible(fk.Columns) || !policy.TableAllowed(refSchema, fk.RefTable) {
+			continue
+		}
+		if strings.EqualFold(fk.RefSchema, schema) {
+			fk.RefSchema = ""
+		}
+		table.ForeignKeys = append(table.ForeignKeys, CatalogForeignKey{
+			Name:       fk.Name,
+			Columns:    fk.Columns,
+			RefSchema:  fk.RefSchema,
+			RefTable:   fk.RefTable,
+			RefColumns: fk.RefColumns,
+			OnDelete:   referentialAction(fk.OnDelete),
+			OnUpdate:   referentialAction(fk.OnUpdate),
+		})
+	}
+	sort.Slice(table.ForeignKeys, func(i, j int) bool { return table.ForeignKeys[i].Name < table.ForeignKeys[j].Name })
+	return table, nil
+}
+
+// referentialAction returns an ON DELETE/ON UPDATE action for the catalog, leaving
+// out the default NO ACTION so that catalogs of every driver compare alike
+func referentialAction(action string) string {
+	action = strings.ToUpper(strings.TrimSpace(action))
+	if action == "NO ACTION" {
+		return ""
+	}
+	return action
+}
+
+// columnNames returns the names of the columns of the table
+func (t *CatalogTable) columnNames() []string {
+	names := make([]string, len(t.Columns))
+	for i, col := range t.Columns {
+		names[i] = col.Name
+	}
+	return names
+}
+
+// catalogRoutines lists the procedures or functions of a schema allowed by the
+// policy, with their source
+func (s *DbMCPServer) catalogRoutines(ctx context.Context, schema, query string, args []interface{},
+	codeQuery func(schema, name string) (string, []interface{})) ([]CatalogCode, error) {
+	auditStatement(ctx, query, args)
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		rows.Close()
+		return nil, err
+	}
+
+	policy := s.policy()
+	var names []string
+	for rows.Next() {
+		// Schema and name come first; the other columns differ between procedures and functions
+		values := make([]interface{}, len(columns))
+		var routineSchema, routineName string
+		var packageName sql.NullString
+		values[0], values[1] = &routineSchema, &routineName
+		for i := 2; i < len(values); i++ {
+			values[i] = new(interface{})
+			if strings.EqualFold(columns[i], "package_name") {
+				values[i] = &packageName
+			}
+		}
+		if err := rows.Scan(values...); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		// Oracle package members are part of their package's source, not routines of their own
+		if packageName.Valid {
+			continue
+		}
+		if policy.TableAllowed(routineSchema, routineName) && !containsFold(names, routineName) {
+			names = append(names, routineName)
+		}
+	}
+	err = rows.Err()
+	rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	routines := []CatalogCode{}
+	for _, name := range names {
+		query, args := codeQuery(schema, name)
+		definition, err := s.readSourceCode(ctx, query, args)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		routines = append(routines, CatalogCode{Name: name, Definition: definition})
+	}
+	return routines, nil
+}
+
+// readSourceCode returns the source of a view or routine, joining the lines Oracle
+// returns one row at a time
+func (s *DbMCPServer) readSourceCode(ctx context.Context, query string, args []interface{}) (string, error) {
+	auditStatement(ctx, query, args)
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	var lines []string
+	for rows.Next() {
+		var line sql.NullString
+		if err := rows.Scan(&line); err != nil {
+			return "", err
+		}
+		lines = append(lines, line.String)
+		if !s.queryBuilder.IsOracle() {
+			break
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+	return normalizeSource(strings.Join(lines, "")), nil
+}
+
+// catalogColumnType returns the type of a column of get_table_schema_full in lower
+// case, with the length of character and binary types and the precision of decimals
+func catalogColumnType(col map[string]interface{}) string {
+	dataType, _ := col["type"].(string)
+	dataType = strings.ToLower(strings.TrimSpace(dataType))
+	if strings.Contains(dataType, "(") {
+		return dataType
+	}
+
+	switch dataType {
+	case "decimal", "numeric", "number":
+		if precision, ok := col["precision"].(int64); ok && precision > 0 {
+			scale, _ := col["scale"].(int64)
+			return fmt.Sprintf("%s(%d,%d)", dataType, precision, scale)
+		}
+		return dataType
+	}
+	if strings.Contains(dataType, "char") || strings.Contains(dataType, "binary") || dataType == "raw" {
+		if length, ok := col["max_length"].(int64); ok && length != 0 {
+			if length < 0 {
+				return dataType + "(max)"
+			}
+			return fmt.Sprintf("%s(%d)", dataType, length)
+		}
+	}
+	return dataType
+}
@@ -0,0 +1,377 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Config is the optional server configuration file, read from DB_MCP_CONFIG
+type Config struct {
+	// Defaults apply to every datasource unless overridden
+	Defaults DataSourceConfig `json:"defaults"`
+	// Datasources holds per-datasource settings, keyed by declared datasource name,
+	// "default" for the environment connection and "ad_hoc" for every ad-hoc connection
+	Datasources map[string]*DataSourceConfig `json:"datasources"`
+	// Audit enables the audit log of tool calls
+	Audit *AuditConfig `json:"audit,omitempty"`
+	// Secrets lists the password references accepted in connection strings, none by default
+	Secrets *SecretsConfig `json:"secrets,omitempty"`
+	// Connections restricts the connection strings given to the datasource tools
+	Connections *ConnectionsConfig `json:"connections,omitempty"`
+	// History configures the per-session query history
+	History *HistoryConfig `json:"history,omitempty"`
+	// SavedQueries exposes the .sql files of a directory as tools
+	SavedQueries *SavedQueriesConfig `json:"saved_queries,omitempty"`
+	// Snapshots holds the schema snapshots compared by diff_schema
+	Snapshots *SnapshotsConfig `json:"snapshots,omitempty"`
+
+	// maskingKey is the hash key used when none is configured
+	maskingKey []byte
+	// policies caches the policy of each datasource, "" for undeclared ones; built by LoadConfig
+	policies map[string]*Policy
+	// maskers caches the masker of each datasource like policies
+	maskers map[string]*Masker
+}
+
+// DataSourceConfig holds the settings of a datasource. A datasource with a connection
+// string is declared: configure_datasource connects to it by name.
+type DataSourceConfig struct {
+	Driver           string `json:"driver,omitempty"`
+	ConnectionString string `json:"connection_string,omitempty"`
+
+	QueryGuard *QueryGuardConfig `json:"query_guard,omitempty"`
+	Policy     *PolicyConfig     `json:"policy,omitempty"`
+	Masking    *MaskingConfig    `json:"masking,omitempty"`
+	Limits     *LimitsConfig     `json:"limits,omitempty"`
+}
+
+// QueryGuardConfig holds the plan-based limits checked before execute_query runs a query.
+// A zero limit is not checked.
+type QueryGuardConfig struct {
+	Enabled          bool    `json:"enabled"`
+	MaxEstimatedCost float64 `json:"max_estimated_cost"`
+	// MaxEstimatedRows limits the rows the query is estimated to return
+	MaxEstimatedRows float64 `json:"max_estimated_rows"`
+	// MaxScannedRows limits the rows estimated for any operator of the plan, such as a scan
+	MaxScannedRows float64 `json:"max_scanned_rows,omitempty"`
+	// FailOpen runs queries whose plan cannot be estimated instead of rejecting them
+	FailOpen bool `json:"fail_open,omitempty"`
+}
+
+// PolicyConfig holds glob rules over "schema.table.column" deciding which objects are visible.
+// Deny rules always win; when allow rules are present, anything they do not match is hidden.
+type PolicyConfig struct {
+	Allow []string `json:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty"`
+}
+
+// MaskingConfig holds the rules masking sensitive values in result rows
+type MaskingConfig struct {
+	// HashKey is the HMAC key of the hash strategy (falls back to DB_MCP_MASKING_KEY)
+	HashKey   string                  `json:"hash_key,omitempty"`
+	Columns   []MaskingColumnConfig   `json:"columns,omitempty"`
+	Detectors []MaskingDetectorConfig `json:"detectors,omitempty"`
+}
+
+// MaskingColumnConfig masks every value of the matching columns.
+// Match is a glob over "column", "table.column" or "schema.table.column".
+type MaskingColumnConfig struct {
+	Match    string `json:"match"`
+	Strategy string `json:"strategy"`
+}
+
+// MaskingDetectorConfig masks the parts of text values matching a pattern.
+// Name is a built-in detector (email, phone, card, ssn) unless Pattern is set.
+type MaskingDetectorConfig struct {
+	Name     string `json:"name"`
+	Pattern  string `json:"pattern,omitempty"`
+	Luhn     bool   `json:"luhn,omitempty"`
+	Strategy string `json:"strategy"`
+}
+
+// LimitsConfig holds the rate limits, concurrency limit and daily quotas of a datasource.
+// A zero value is not limited; a burst of 0 allows one minute's worth of calls at once.
+type LimitsConfig struct {
+	SessionRequestsPerMinute    float64 `json:"session_requests_per_minute,omitempty"`
+	SessionBurst                int     `json:"session_burst,omitempty"`
+	DatasourceRequestsPerMinute float64 `json:"datasource_requests_per_minute,omitempty"`
+	DatasourceBurst             int     `json:"datasource_burst,omitempty"`
+	MaxConcurrentQueries        int     `json:"max_concurrent_queries,omitempty"`
+	// Daily quotas reset at midnight UTC
+	DailyMaxRows         int64   `json:"daily_max_rows,omitempty"`
+	DailyMaxQuerySeconds float64 `json:"daily_max_query_seconds,omitempty"`
+}
+
+// AuditConfig holds the settings of the audit log
+type AuditConfig struct {
+	Path      string `json:"path"`
+	MaxSizeMB int    `json:"max_size_mb,omitempty"`
+	// MaxFiles is the number of rotated files kept
+	MaxFiles int `json:"max_files,omitempty"`
+	// RedactParams replaces every bound parameter instead of masking detected values
+	RedactParams bool `json:"redact_params,omitempty"`
+	// HashKey is the HMAC key of the entry hashes (falls back to DB_MCP_AUDIT_KEY)
+	HashKey string `json:"hash_key,omitempty"`
+}
+
+// hashKey returns the configured HMAC key of the audit log, then DB_MCP_AUDIT_KEY
+func (a *AuditConfig) hashKey() []byte {
+	if a.HashKey != "" {
+		return []byte(a.HashKey)
+	}
+	return []byte(os.Getenv("DB_MCP_AUDIT_KEY"))
+}
+
+// SecretsConfig holds glob patterns of the environment variables (env:NAME) and files
+// (file:/path) connection string passwords may reference. An empty list allows none.
+type SecretsConfig struct {
+	Env   []string `json:"env,omitempty"`
+	Files []string `json:"files,omitempty"`
+}
+
+// ConnectionsConfig restricts ad-hoc connection strings, those given to configure_datasource
+// and test_connection rather than declared in the configuration file
+type ConnectionsConfig struct {
+	// AllowAdHoc false only allows the declared datasources (default: true)
+	AllowAdHoc *bool `json:"allow_ad_hoc,omitempty"`
+	// AllowedHosts are hostname glob patterns, AllowedCIDRs the networks a host may resolve to
+	AllowedHosts []string `json:"allowed_hosts,omitempty"`
+	AllowedCIDRs []string `json:"allowed_cidrs,omitempty"`
+	// AllowedPorts are ports ("5432") or ranges ("1433-1434")
+	AllowedPorts []string `json:"allowed_ports,omitempty"`
+	// SQLiteRoots are the directories SQLite databases may be opened from
+	SQLiteRoots []string `json:"sqlite_roots,omitempty"`
+}
+
+// HistoryConfig holds the settings of the query history, kept in memory unless a path is set
+type HistoryConfig struct {
+	Disabled bool `json:"disabled,omitempty"`
+	// MaxEntries is the number of entries kept per session
+	MaxEntries int `json:"max_entries,omitempty"`
+	// Path is a SQLite file persisting the history across restarts
+	Path string `json:"path,omitempty"`
+}
+
+// SavedQueriesConfig holds the directory of the saved queries, checked for changes
+// every ReloadIntervalSeconds (default: 2)
+type SavedQueriesConfig struct {
+	Dir                   string `json:"dir"`
+	ReloadIntervalSeconds int    `json:"reload_interval_seconds,omitempty"`
+}
+
+// SnapshotsConfig holds the directory of the schema snapshot files
+type SnapshotsConfig struct {
+	Dir string `json:"dir"`
+}
+
+// LoadConfig reads the configuration file at path. An empty path returns an empty configuration.
+func LoadConfig(path string) (*Config, error) {
+	config := &Config{Datasources: make(map[string]*DataSourceConfig)}
+	if path == "" {
+		config.maskingKey = defaultMaskingKey()
+		return config, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrReadingConfig, err)
+	}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+	}
+	if config.Datasources == nil {
+		config.Datasources = make(map[string]*DataSourceConfig)
+	}
+	config.maskingKey = defaultMaskingKey()
+
+	if config.Audit != nil && config.Audit.Path == "" {
+		return nil, fmt.Errorf("%w: audit path is required", ErrInvalidConfig)
+	}
+	if config.SavedQueries != nil && config.SavedQueries.Dir == "" {
+		return nil, fmt.Errorf("%w: saved_queries dir is required", ErrInvalidConfig)
+	}
+	if config.Snapshots != nil && config.Snapshots.Dir == "" {
+		return nil, fmt.Errorf("%w: snapshots dir is required", ErrInvalidConfig)
+	}
+	if config.Connections != nil {
+		if err := config.Connections.validate(); err != nil {
+			return nil, fmt.Errorf("%w: connections: %v", ErrInvalidConfig, err)
+		}
+	}
+	if err := config.Defaults.validate(); err != nil {
+		return nil, fmt.Errorf("%w: defaults: %v", ErrInvalidConfig, err)
+	}
+	for name, ds := range config.Datasources {
+		if name == "" {
+			return nil, fmt.Errorf("%w: datasource name is empty", ErrInvalidConfig)
+		}
+		if ds == nil {
+			return nil, fmt.Errorf("%w: datasource %s is empty", ErrInvalidConfig, name)
+		}
+		if err := ds.validate(); err != nil {
+			return nil, fmt.Errorf("%w: datasource %s: %v", ErrInvalidConfig, name, err)
+		}
+		if name == AdHocDataSourceName && ds.ConnectionString != "" {
+			return nil, fmt.Errorf("%w: datasource %s holds the settings of ad-hoc connections and cannot be declared", ErrInvalidConfig, name)
+		}
+	}
+
+	config.policies = map[string]*Policy{"": config.buildPolicy("")}
+	config.maskers = map[string]*Masker{"": config.buildMasker("")}
+	for name := range config.Datasources {
+		config.policies[name] = config.buildPolicy(name)
+		config.maskers[name] = config.buildMasker(name)
+	}
+	return config, nil
+}
+
+// validate checks the settings of a datasource
+func (d *DataSourceConfig) validate() error {
+	if d.ConnectionString != "" && normalizeDriver(d.Driver) == "" {
+		return fmt.Errorf("%w: '%s'", ErrInvalidDriver, d.Driver)
+	}
+	if d.Policy != nil {
+		if _, err := NewPolicy(d.Policy.Allow, d.Policy.Deny); err != nil {
+			return err
+		}
+	}
+	if d.Masking != nil {
+		if _, err := NewMasker(d.Masking, nil); err != nil {
+			return err
+		}
+	}
+	if l := d.Limits; l != nil {
+		if l.SessionRequestsPerMinute < 0 || l.DatasourceRequestsPerMinute < 0 || l.SessionBurst < 0 || l.DatasourceBurst < 0 ||
+			l.MaxConcurrentQueries < 0 || l.DailyMaxRows < 0 || l.DailyMaxQuerySeconds < 0 {
+			return fmt.Errorf("limits cannot be negative")
+		}
+	}
+	return nil
+}
+
+// reservedName reports whether an ad-hoc connection cannot be named name: the
+// environment connection and the configured datasources keep their names
+func (c *Config) reservedName(name string) bool {
+	if strings.EqualFold(name, DefaultDataSourceName) {
+		return true
+	}
+	if c == nil {
+		return false
+	}
+	for configured := range c.Datasources {
+		if strings.EqualFold(configured, name) && !strings.EqualFold(configured, AdHocDataSourceName) {
+			return true
+		}
+	}
+	return false
+}
+
+// DeclaredDataSource returns the datasource declared with a connection string under a name
+func (c *Config) DeclaredDataSource(name string) *DataSourceConfig {
+	if c == nil {
+		return nil
+	}
+	if ds, ok := c.Datasources[name]; ok && ds.ConnectionString != "" {
+		return ds
+	}
+	return nil
+}
+
+// QueryGuard returns the query guard settings of a datasource, falling back to the defaults
+func (c *Config) QueryGuard(datasource string) *QueryGuardConfig {
+	if c == nil {
+		return nil
+	}
+	if ds, ok := c.Datasources[datasource]; ok && ds.QueryGuard != nil {
+		return ds.QueryGuard
+	}
+	return c.Defaults.QueryGuard
+}
+
+// Limits returns the limits of a datasource, falling back to the defaults
+func (c *Config) Limits(datasource string) *LimitsConfig {
+	if c == nil {
+		return nil
+	}
+	if ds, ok := c.Datasources[datasource]; ok && ds.Limits != nil {
+		return ds.Limits
+	}
+	return c.Defaults.Limits
+}
+
+// Policy returns the access policy of a datasource. Deny rules of the defaults and
+// the datasource are combined; the datasource allow rules replace the default ones.
+func (c *Config) Policy(datasource string) *Policy {
+	if c == nil {
+		return nil
+	}
+	if c.policies != nil {
+		if _, ok := c.Datasources[datasource]; !ok {
+			datasource = ""
+		}
+		return c.policies[datasource]
+	}
+	return c.buildPolicy(datasource)
+}
+
+// buildPolicy merges the default and datasource rules into a policy
+func (c *Config) buildPolicy(datasource string) *Policy {
+	var allow, deny []string
+	if c.Defaults.Policy != nil {
+		allow = c.Defaults.Policy.Allow
+		deny = append(deny, c.Defaults.Policy.Deny...)
+	}
+	if ds, ok := c.Datasources[datasource]; ok && ds.Policy != nil {
+		if len(ds.Policy.Allow) > 0 {
+			allow = ds.Policy.Allow
+		}
+		deny = append(deny, ds.Policy.Deny...)
+	}
+
+	// Rules are validated by LoadConfig
+	policy, _ := NewPolicy(allow, deny)
+	return policy
+}
+
+// Masker returns the masking stage of a datasource, nil when no masking is configured.
+// The rules of the datasource come before the default ones.
+func (c *Config) Masker(datasource string) *Masker {
+	if c == nil {
+		return nil
+	}
+	if c.maskers != nil {
+		if _, ok := c.Datasources[datasource]; !ok {
+			datasource = ""
+		}
+		return c.maskers[datasource]
+	}
+	return c.buildMasker(datasource)
+}
+
+// buildMasker merges the datasource and default masking rules, nil when there are none
+func (c *Config) buildMasker(datasource string) *Masker {
+	merged := &MaskingConfig{}
+	var configs []*MaskingConfig
+	if ds, ok := c.Datasources[datasource]; ok && ds.Masking != nil {
+		configs = append(configs, ds.Masking)
+	}
+	if c.Defaults.Masking != nil {
+		configs = append(configs, c.Defaults.Masking)
+	}
+	for _, m := range configs {
+		if merged.HashKey == "" {
+			merged.HashKey = m.HashKey
+		}
+		merged.Columns = append(merged.Columns, m.Columns...)
+		merged.Detectors = append(merged.Detectors, m.Detectors...)
+	}
+	if len(merged.Columns) == 0 && len(merged.Detectors) == 0 {
+		return nil
+	}
+
+	// Rules are validated by LoadConfig
+	masker, _ := NewMasker(merged, c.maskingKey)
+	return masker
+}
@@ -0,0 +1,97 @@
+package mcp
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfigBuildsPolicies(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	data := `{
+		"defaults": {"policy": {"deny": ["payroll"]}},
+		"datasources": {"crm": {"policy": {"allow": ["public"], "deny": ["*.users.password"]}}}
+	}`
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		datasource string
+		object     []string
+		allowed    bool
+	}{
+		{"default deny", "other", []string{"payroll", "salaries"}, false},
+		{"default allow", "other", []string{"public", "users"}, true},
+		{"datasource deny", "crm", []string{"public", "users", "password"}, false},
+		{"datasource allow", "crm", []string{"sales", "orders"}, false},
+		{"datasource inherits deny", "crm", []string{"payroll", "salaries"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := config.Policy(tt.datasource)
+			if policy != config.Policy(tt.datasource) {
+				t.Fatalf("Policy(%q) is rebuilt on each call", tt.datasource)
+			}
+			var allowed bool
+			if len(tt.object) == 3 {
+				allowed = policy.ColumnAllowed(tt.object[0], tt.object[1], tt.object[2])
+			} else {
+				allowed = policy.TableAllowed(tt.object[0], tt.object[1])
+			}
+			if allowed != tt.allowed {
+				t.Fatalf("Policy(%q) allows %v = %v, want %v", tt.datasource, tt.object, allowed, tt.allowed)
+			}
+		})
+	}
+}
+
+func TestLoadConfigBuildsMaskers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	data := `{
+		"defaults": {"masking": {"columns": [{"match": "ssn", "strategy": "redact"}]}},
+		"datasources": {
+			"crm": {"masking": {"columns": [{"match": "email", "strategy": "redact"}]}},
+			"plain": {}
+		}
+	}`
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		datasource string
+		masked     []string
+	}{
+		{"undeclared", "other", []string{"ssn"}},
+		{"without masking", "plain", []string{"ssn"}},
+		{"datasource rules", "crm", []string{"email", "ssn"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			masker := config.Masker(tt.datasource)
+			if masker != config.Masker(tt.datasource) {
+				t.Fatalf("Masker(%q) is rebuilt on each call", tt.datasource)
+			}
+			columns := []string{"email", "name", "ssn"}
+			row := map[string]interface{}{"email": "a@b.c", "name": "n", "ssn": "1"}
+			masked := masker.MaskRows(columns, nil, []map[string]interface{}{row})
+			if strings.Join(masked, ",") != strings.Join(tt.masked, ",") {
+				t.Fatalf("Masker(%q) masked %v, want %v", tt.datasource, masked, tt.masked)
+			}
+		})
+	}
+}
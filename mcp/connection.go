@@ -11,7 +11,8 @@ import (
 // Returns nil connection if DB_CONNECTION_STRING is not set (allows dynamic configuration later).
 // If connection string is set but connection fails, logs a warning and returns nil connection
 // (server starts without connection, allowing user to reconfigure via tools).
-func newDbConnection() (*sql.DB, string, error) {
+// The password may be an env: or file: secret reference.
+func newDbConnection(secrets *SecretsConfig) (*sql.DB, string, error) {
 	// Get database driver type (default to sqlserver for backward compatibility)
 	driver := os.Getenv("DB_DRIVER")
 	if driver == "" {
@@ -26,10 +27,18 @@ func newDbConnection() (*sql.DB, string, error) {
 		return nil, driver, nil
 	}
 
-	db, err := sql.Open(driver, connString)
+	dsn := ParseDSN(DriverType(normalizeDriver(driver)), connString)
+	resolved, passwords, err := dsn.ResolveSecrets(secrets)
+	if err != nil {
+		log.Printf("Warning: Could not resolve the connection string: %v. Server starting without database connection. Use configure_datasource to connect.", err)
+		return nil, driver, nil
+	}
+
+	db, err := sql.Open(driver, resolved)
 	if err != nil {
 		// Log warning but don't fail - allow server to start
-		log.Printf("Warning: Could not open database connection: %v. Server starting without database connection. Use configure_datasource to connect.", err)
+		log.Printf("Warning: Could not open database connection: %s. Server starting without database connection. Use configure_datasource to connect.",
+			RedactSecrets(err.Error(), dsn, resolved, passwords))
 		return nil, driver, nil
 	}
 
@@ -44,7 +53,8 @@ func newDbConnection() (*sql.DB, string, error) {
 
 	if err = db.PingContext(ctx); err != nil {
 		// Log warning but don't fail - allow server to start
-		log.Printf("Warning: Could not connect to database: %v. Server starting without database connection. Use configure_datasource to connect.", err)
+		log.Printf("Warning: Could not connect to database: %s. Server starting without database connection. Use configure_datasource to connect.",
+			RedactSecrets(err.Error(), dsn, resolved, passwords))
 		db.Close()
 		return nil, driver, nil
 	}
@@ -0,0 +1,275 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// defaultPorts are the ports a driver connects to when the connection string has none
+var defaultPorts = map[DriverType]string{
+	DriverSQLServer:   "1433",
+	DriverPostgresSQL: "5432",
+	DriverMySQL:       "3306",
+	DriverOracle:      "1521",
+}
+
+// connectionTarget is a connection requested through the datasource tools
+type connectionTarget struct {
+	name      string
+	driver    string // as given
+	dsn       *DSN
+	resolved  string // connection string with secret references resolved
+	passwords []string
+	declared  bool // taken from the configuration file
+}
+
+// connectionTarget reads the driver, connection_string and name arguments. Without a
+// connection string, the datasource declared under that name in the configuration is used.
+// Ad-hoc connection strings are checked against the connection policy, and cannot take
+// the name of a configured datasource, whose settings and history they would inherit.
+func (s *DbMCPServer) connectionTarget(ctx context.Context, args map[string]interface{}, defaultName string) (*connectionTarget, error) {
+	target := &connectionTarget{name: defaultName}
+	name, _ := getStringArg(args, "name")
+	if name != "" {
+		target.name = name
+	}
+	connString, _ := getStringArg(args, "connection_string")
+	target.driver, _ = getStringArg(args, "driver")
+
+	if connString == "" {
+		declared := s.config.DeclaredDataSource(target.name)
+		if declared == nil {
+			return nil, ErrConnectionStringRequired
+		}
+		target.driver, connString, target.declared = declared.Driver, declared.ConnectionString, true
+	} else {
+		if !s.config.Connections.adHocAllowed() {
+			return nil, ErrAdHocDataSourceDisabled
+		}
+		if name == "" {
+			target.name = AdHocDataSourceName
+		} else if s.config.reservedName(name) {
+			return nil, fmt.Errorf("%w: '%s'", ErrDataSourceNameReserved, name)
+		}
+	}
+
+	if target.driver == "" {
+		return nil, ErrDriverRequired
+	}
+	normalizedDriver := normalizeDriver(target.driver)
+	if normalizedDriver == "" {
+		return nil, fmt.Errorf("%w: '%s'. Supported drivers: sqlserver, postgres, mysql, sqlite, oracle", ErrInvalidDriver, target.driver)
+	}
+
+	target.dsn = ParseDSN(DriverType(normalizedDriver), connString)
+	if !target.declared {
+		if err := s.config.Connections.Check(ctx, target.dsn); err != nil {
+			return nil, err
+		}
+	}
+
+	// Resolve secret references; driver errors are returned with the secrets redacted
+	resolved, passwords, err := target.dsn.ResolveSecrets(s.config.Secrets)
+	if err != nil {
+		return nil, err
+	}
+	target.resolved, target.passwords = resolved, passwords
+	return target, nil
+}
+
+// profile returns the configuration entry whose settings apply to the target: its own
+// for declared datasources, the ad_hoc entry for every ad-hoc connection
+func (t *connectionTarget) profile() string {
+	if t.declared {
+		return t.name
+	}
+	return AdHocDataSourceName
+}
+
+// error wraps a driver error of the target with its secrets redacted
+func (t *connectionTarget) error(sentinel, err error) error {
+	return connectionError(sentinel, err, t.dsn, t.resolved, t.passwords)
+}
+
+// adHocAllowed reports whether connection strings may be given to the datasource tools
+func (c *ConnectionsConfig) adHocAllowed() bool {
+	return c == nil || c.AllowAdHoc == nil || *c.AllowAdHoc
+}
+
+// validate checks the CIDRs and port ranges of the connection policy
+func (c *ConnectionsConfig) validate() error {
+	for _, cidr := range c.AllowedCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return fmt.Errorf("invalid CIDR %q", cidr)
+		}
+	}
+	for _, ports := range c.AllowedPorts {
+		if _, _, err := parsePortRange(ports); err != nil {
+			return err
+		}
+	}
+	for _, host := range c.AllowedHosts {
+		if _, err := path.Match(host, ""); err != nil {
+			return fmt.Errorf("invalid host pattern %q", host)
+		}
+	}
+	return nil
+}
+
+// parsePortRange reads "5432" or "1433-1434"
+func parsePortRange(ports string) (int, int, error) {
+	from, to, isRange := strings.Cut(strings.TrimSpace(ports), "-")
+	low, err := strconv.Atoi(strings.TrimSpace(from))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid port %q", ports)
+	}
+	high := low
+	if isRange {
+		if high, err = strconv.Atoi(strings.TrimSpace(to)); err != nil || high < low {
+			return 0, 0, fmt.Errorf("invalid port range %q", ports)
+		}
+	}
+	return low, high, nil
+}
+
+// Check verifies the host, port or SQLite file of a connection string against the
+// allow-lists. Empty lists do not restrict anything.
+func (c *ConnectionsConfig) Check(ctx context.Context, dsn *DSN) error {
+	if c == nil {
+		return nil
+	}
+	if dsn.Driver == DriverSQLite {
+		if dsn.invalidPath && len(c.SQLiteRoots) > 0 {
+			return fmt.Errorf("%w: %s", ErrPathNotAllowed, dsn.Redacted())
+		}
+		return c.checkSQLitePath(dsn.Database)
+	}
+	if dsn.ambiguousHost && (len(c.AllowedHosts) > 0 || len(c.AllowedCIDRs) > 0 || len(c.AllowedPorts) > 0) {
+		return fmt.Errorf("%w: the connection string sets several hosts or ports in its parameters", ErrHostNotAllowed)
+	}
+	if err := c.checkHost(ctx, dsn.Host); err != nil {
+		return err
+	}
+	return c.checkPort(dsn.Driver, dsn.Port)
+}
+
+// checkHost accepts a host matching a hostname pattern, or whose addresses all fall in
+// the allowed CIDRs. Hostnames are resolved here; the driver resolves them again.
+func (c *ConnectionsConfig) checkHost(ctx context.Context, host string) error {
+	if len(c.AllowedHosts) == 0 && len(c.AllowedCIDRs) == 0 {
+		return nil
+	}
+	host = strings.Trim(strings.ToLower(host), "[]")
+	if host == "" {
+		host = "localhost"
+	}
+
+	for _, pattern := range c.AllowedHosts {
+		if ok, _ := path.Match(strings.ToLower(pattern), host); ok {
+			return nil
+		}
+	}
+	if len(c.AllowedCIDRs) == 0 {
+		return fmt.Errorf("%w: %s", ErrHostNotAllowed, host)
+	}
+
+	var ips []net.IP
+	if ip := net.ParseIP(host); ip != nil {
+		ips = []net.IP{ip}
+	} else {
+		addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return fmt.Errorf("%w: %s could not be resolved", ErrHostNotAllowed, host)
+		}
+		for _, addr := range addrs {
+			ips = append(ips, addr.IP)
+		}
+	}
+	for _, ip := range ips {
+		if c.ipAllowed(ip) {
+			continue
+		}
+		if ip.String() == host {
+			return fmt.Errorf("%w: %s", ErrHostNotAllowed, host)
+		}
+		return fmt.Errorf("%w: %s (%s)", ErrHostNotAllowed, host, ip)
+	}
+	if len(ips) == 0 {
+		return fmt.Errorf("%w: %s", ErrHostNotAllowed, host)
+	}
+	return nil
+}
+
+func (c *ConnectionsConfig) ipAllowed(ip net.IP) bool {
+	for _, cidr := range c.AllowedCIDRs {
+		// CIDRs are validated by LoadConfig
+		if _, network, err := net.ParseCIDR(cidr); err == nil && network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *ConnectionsConfig) checkPort(driver DriverType, port string) error {
+	if len(c.AllowedPorts) == 0 {
+		return nil
+	}
+	if port == "" {
+		port = defaultPorts[driver]
+	}
+	number, err := strconv.Atoi(port)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrPortNotAllowed, port)
+	}
+	for _, ports := range c.AllowedPorts {
+		if low, high, err := parsePortRange(ports); err == nil && number >= low && number <= high {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %d", ErrPortNotAllowed, number)
+}
+
+// checkSQLitePath accepts in-memory databases and files under a root directory,
+// following symbolic links
+func (c *ConnectionsConfig) checkSQLitePath(file string) error {
+	if len(c.SQLiteRoots) == 0 || file == ":memory:" || file == "" {
+		return nil
+	}
+
+	resolved, err := resolvePath(file)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrPathNotAllowed, file)
+	}
+	for _, root := range c.SQLiteRoots {
+		resolvedRoot, err := resolvePath(root)
+		if err != nil {
+			continue
+		}
+		if rel, err := filepath.Rel(resolvedRoot, resolved); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrPathNotAllowed, file)
+}
+
+// resolvePath returns the absolute path of a file with symbolic links resolved,
+// resolving its directory when the file does not exist yet
+func resolvePath(file string) (string, error) {
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		return resolved, nil
+	}
+	dir, err := filepath.EvalSymlinks(filepath.Dir(abs))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, filepath.Base(abs)), nil
+}
@@ -0,0 +1,88 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConnectionsCheckURLHostParams(t *testing.T) {
+	connections := &ConnectionsConfig{
+		AllowedHosts: []string{"allowed.example"},
+		AllowedPorts: []string{"1433", "5432"},
+	}
+
+	tests := []struct {
+		name   string
+		driver DriverType
+		dsn    string
+		want   error
+	}{
+		{"postgres authority", DriverPostgresSQL, "postgres://u:p@allowed.example/db?sslmode=disable", nil},
+		{"postgres host parameter", DriverPostgresSQL, "postgres://u:p@allowed.example/db?host=metadata.internal&port=80", ErrHostNotAllowed},
+		{"postgres port parameter", DriverPostgresSQL, "postgres://u:p@allowed.example/db?port=22", ErrPortNotAllowed},
+		{"postgres host and hostaddr", DriverPostgresSQL, "postgres://u:p@allowed.example/db?host=allowed.example&hostaddr=10.0.0.1", ErrHostNotAllowed},
+		{"sqlserver server parameter", DriverSQLServer, "sqlserver://allowed.example?server=10.0.0.5&port=22", ErrHostNotAllowed},
+		{"sqlserver server parameter case", DriverSQLServer, "sqlserver://allowed.example?Server=10.0.0.5", ErrHostNotAllowed},
+		{"sqlserver allowed server parameter", DriverSQLServer, "sqlserver://other?server=allowed.example,1433", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := connections.Check(context.Background(), ParseDSN(tt.driver, tt.dsn))
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Check(%q) = %v, want nil", tt.dsn, err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Check(%q) = %v, want %v", tt.dsn, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestConnectionsCheckSQLitePath(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "allowed")
+	if err := os.MkdirAll(filepath.Join(root, "sub"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	connections := &ConnectionsConfig{SQLiteRoots: []string{root}}
+
+	tests := []struct {
+		name string
+		dsn  string
+		want error
+	}{
+		{"plain path", filepath.Join(root, "a.db"), nil},
+		{"uri path", "file:" + filepath.Join(root, "a.db") + "?mode=ro", nil},
+		{"uri escaped name", "file:" + root + "/sub/a%20b.db", nil},
+		{"uri localhost", "file://localhost" + root + "/a.db", nil},
+		{"memory", ":memory:", nil},
+		{"outside", filepath.Join(dir, "other.db"), ErrPathNotAllowed},
+		{"dot dot", "file:" + root + "/../other.db", ErrPathNotAllowed},
+		{"escaped dot dot", "file:" + root + "/%2e%2e/%2e%2e/etc/x.db", ErrPathNotAllowed},
+		{"escaped slash", "file:" + root + "/sub%2f..%2f..%2fother.db", ErrPathNotAllowed},
+		{"remote authority", "file://host" + root + "/a.db", ErrPathNotAllowed},
+		{"bad escape", "file:" + root + "/%zz.db", ErrPathNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := connections.Check(context.Background(), ParseDSN(DriverSQLite, tt.dsn))
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Check(%q) = %v, want nil", tt.dsn, err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Check(%q) = %v, want %v", tt.dsn, err, tt.want)
+			}
+		})
+	}
+}
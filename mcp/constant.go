@@ -20,6 +20,98 @@ const (
 	MaxCharFunctionCount = 10
 )
 
+// Filter constants
+const (
+	MaxFilterDepth      = 10
+	MaxFilterConditions = 100
+	MaxFilterInValues   = 1000 // Oracle limits IN lists to 1000 expressions
+)
+
+// Aggregation constants
+const (
+	MaxGroupByColumns = 10
+	MaxAggregates     = 20
+)
+
+// Query builder constants
+const (
+	MaxJoins = 10
+)
+
+// Masking constants
+const (
+	MaskRedact  = "redact"
+	MaskPartial = "partial"
+	MaskHash    = "hash"
+	MaskNull    = "null"
+
+	MaskRedactedValue = "[REDACTED]"
+	MaskPartialKeep   = 4  // trailing characters kept by the partial strategy
+	MaskHashBytes     = 16 // HMAC bytes kept (hex encoded) by the hash strategy
+)
+
+// Audit log constants
+const (
+	DefaultAuditMaxSizeMB = 100
+	DefaultAuditMaxFiles  = 10
+	MaxAuditLineSize      = 4 * 1024 * 1024
+	AuditAnchorSuffix     = ".anchor" // last entry dropped by rotation
+)
+
+// Limits constants
+const (
+	ConcurrencyWaitTimeout = 2 * time.Second // wait for a query slot before rejecting a call
+)
+
+// Secret constants
+const (
+	RedactedSecret          = "****"
+	MinRedactedSecretLength = 3 // shorter passwords are not searched for in messages
+)
+
+// Query history constants
+const (
+	DefaultHistoryMaxEntries = 100
+	DefaultHistoryListLimit  = 20
+)
+
+// Saved query constants
+const (
+	SavedQueryToolPrefix      = "saved_"
+	DefaultSavedQueriesReload = 2 * time.Second
+)
+
+// Schema constants
+const (
+	MaxSchemaObjects = 1000 // tables or views read for a whole schema
+)
+
+// ER diagram constants
+const (
+	ERFormatMermaid  = "mermaid"
+	ERFormatPlantUML = "plantuml"
+	ERFormatDOT      = "dot"
+
+	DefaultERDiagramDepth = 1
+	MaxERDiagramDepth     = 5
+)
+
+// Schema diff constants
+const (
+	SchemaCatalogVersion = 1
+	DiffContextLines     = 3    // unchanged lines around the changes of a unified diff
+	MaxDiffLines         = 5000 // longer sources are compared as a whole
+)
+
+// Dependency constants
+const (
+	DependencyFormatJSON    = "json"
+	DependencyFormatMermaid = "mermaid"
+	DefaultDependencyDepth  = 5
+	MaxDependencyDepth      = 10
+	MaxDependencyNodes      = 500 // larger trees are cut, with truncated set
+)
+
 // Pagination constants
 const (
 	DefaultPage     = 1
@@ -28,12 +120,29 @@ const (
 	MaxRowsPageSize = 1000
 )
 
+// Result budget constants
+const (
+	DefaultMaxResponseBytes = 1024 * 1024
+	MaxResponseBytes        = 16 * 1024 * 1024
+	DefaultMaxCellBytes     = 4096
+	MaxCellBytes            = 1024 * 1024
+	BytesPerToken           = 4   // rough bytes per token of JSON text
+	TruncationMarker        = "…" // appended to truncated cell values
+)
+
 // Query timeout constants
 const (
 	DefaultQueryTimeout = 30 * time.Second
 	ShortQueryTimeout   = 10 * time.Second
 )
 
+// DefaultDataSourceName is the name of the environment connection
+const DefaultDataSourceName = "default"
+
+// AdHocDataSourceName is the configuration entry whose settings apply to every ad-hoc
+// connection, and the name of those connections when none is given
+const AdHocDataSourceName = "ad_hoc"
+
 // Drivers
 const (
 	DriverSQLServer   DriverType = "sqlserver"
@@ -0,0 +1,168 @@
+package mcp
+
+import (
+	"strings"
+)
+
+// sqlItem is a word, quoted identifier or parenthesized group at the top level of
+// a piece of SQL; text holds the item as written, a group with its parentheses
+type sqlItem struct {
+	text   string
+	group  bool
+	quoted bool
+	// ident is the unquoted name of a quoted identifier
+	ident string
+}
+
+// is reports whether the item is the given keyword
+func (i sqlItem) is(keyword string) bool {
+	return !i.group && !i.quoted && strings.EqualFold(i.text, keyword)
+}
+
+// name returns the identifier of a word or quoted identifier item
+func (i sqlItem) name() string {
+	if i.quoted {
+		return i.ident
+	}
+	return i.text
+}
+
+// sqliteTableParts splits the body of a CREATE TABLE statement, or any parenthesized
+// list, into its comma separated definitions
+func sqliteTableParts(createSQL string) []string {
+	tokens, err := tokenizeSQL(createSQL)
+	if err != nil {
+		return nil
+	}
+	src := []rune(createSQL)
+	var parts []string
+	depth, start := 0, -1
+	for _, token := range tokens {
+		switch {
+		case token.is("("):
+			if depth == 0 {
+				start = token.end
+			}
+			depth++
+		case token.is(")") && depth > 0:
+			depth--
+			if depth == 0 {
+				return append(parts, strings.TrimSpace(string(src[start:token.start])))
+			}
+		case token.is(",") && depth == 1:
+			parts = append(parts, strings.TrimSpace(string(src[start:token.start])))
+			start = token.end
+		}
+	}
+	return parts
+}
+
+// sqlItems splits a piece of SQL into its top level words, quoted identifiers and
+// parenthesized groups. A word runs over adjacent words, literals, dots and signs, so
+// string literals and qualified names are kept within words.
+func sqlItems(sql string) []sqlItem {
+	tokens, err := tokenizeSQL(sql)
+	if err != nil {
+		return nil
+	}
+	src := []rune(sql)
+	var items []sqlItem
+	depth, groupStart := 0, 0
+	wordStart, wordEnd := -1, -1
+	endWord := func() {
+		if wordStart >= 0 {
+			items = append(items, sqlItem{text: string(src[wordStart:wordEnd])})
+			wordStart = -1
+		}
+	}
+	for _, token := range tokens {
+		switch {
+		case token.is("("):
+			if depth == 0 {
+				endWord()
+				groupStart = token.start
+			}
+			depth++
+		case token.is(")") && depth > 0:
+			depth--
+			if depth == 0 {
+				items = append(items, sqlItem{text: string(src[groupStart:token.end]), group: true})
+			}
+		case depth > 0:
+		case token.kind == sqlTokenQuoted:
+			endWord()
+			items = append(items, sqlItem{text: string(src[token.start:token.end]), quoted: true, ident: token.text})
+		case token.kind != sqlTokenSymbol || token.is(".") || token.is("-") || token.is("+"):
+			if wordStart < 0 || wordEnd != token.start {
+				endWord()
+				wordStart = token.start
+			}
+			wordEnd = token.end
+		default:
+			endWord()
+		}
+	}
+	endWord()
+	return items
+}
+
+// parseSQLiteConstraints returns the check and unique constraints declared in a
+// CREATE TABLE statement, on its columns or the table
+func parseSQLiteConstraints(createSQL string) ([]CheckConstraint, []UniqueConstraint) {
+	checks := []CheckConstraint{}
+	uniques := []UniqueConstraint{}
+
+	for _, part := range sqliteTableParts(createSQL) {
+		items := sqlItems(part)
+		if len(items) == 0 {
+			continue
+		}
+
+		// A column definition starts with the column name
+		column := ""
+		first := items[0]
+		if !first.is("CONSTRAINT") && !first.is("CHECK") && !first.is("UNIQUE") && !first.is("PRIMARY") && !first.is("FOREIGN") {
+			column = first.name()
+			items = items[1:]
+		}
+
+		name := ""
+		for i := 0; i < len(items); i++ {
+			item := items[i]
+			switch {
+			case item.is("CONSTRAINT") && i+1 < len(items):
+				name = items[i+1].name()
+				i++
+			case item.is("CHECK") && i+1 < len(items) && items[i+1].group:
+				checks = append(checks, CheckConstraint{Name: name, Column: column, Definition: items[i+1].text})
+				name = ""
+				i++
+			case item.is("UNIQUE"):
+				if column != "" {
+					uniques = append(uniques, UniqueConstraint{Name: name, Columns: []string{column}})
+				} else if i+1 < len(items) && items[i+1].group {
+					uniques = append(uniques, UniqueConstraint{Name: name, Columns: sqliteKeyColumns(items[i+1].text)})
+					i++
+				}
+				name = ""
+			case item.is("PRIMARY") || item.is("FOREIGN") || item.is("REFERENCES") || item.is("DEFAULT") ||
+				item.is("NOT") || item.is("NULL") || item.is("COLLATE") || item.is("GENERATED"):
+				// Other constraints end the pending constraint name
+				name = ""
+			}
+		}
+	}
+	return checks, uniques
+}
+
+// sqliteKeyColumns returns the column names of a parenthesized key column list,
+// without their COLLATE and sort order clauses
+func sqliteKeyColumns(group string) []string {
+	var columns []string
+	for _, part := range sqliteTableParts(group) {
+		if items := sqlItems(part); len(items) > 0 {
+			columns = append(columns, items[0].name())
+		}
+	}
+	return columns
+}
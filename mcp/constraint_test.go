@@ -0,0 +1,79 @@
+package mcp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSQLiteConstraints(t *testing.T) {
+	tests := []struct {
+		name    string
+		sql     string
+		checks  []CheckConstraint
+		uniques []UniqueConstraint
+	}{
+		{
+			name:    "column constraints",
+			sql:     "CREATE TABLE t (id INTEGER PRIMARY KEY, email TEXT NOT NULL UNIQUE, age INTEGER CHECK (age >= 0))",
+			checks:  []CheckConstraint{{Column: "age", Definition: "(age >= 0)"}},
+			uniques: []UniqueConstraint{{Columns: []string{"email"}}},
+		},
+		{
+			name: "named table constraints",
+			sql: `CREATE TABLE t (a INT, b INT,
+				CONSTRAINT ab_positive CHECK (a > 0 AND b > 0),
+				CONSTRAINT ab_unique UNIQUE (a, b DESC))`,
+			checks:  []CheckConstraint{{Name: "ab_positive", Definition: "(a > 0 AND b > 0)"}},
+			uniques: []UniqueConstraint{{Name: "ab_unique", Columns: []string{"a", "b"}}},
+		},
+		{
+			name:    "nested parentheses in check",
+			sql:     "CREATE TABLE t (price REAL, qty INT, CHECK ((price * (qty + 1)) < (100)), UNIQUE (price))",
+			checks:  []CheckConstraint{{Definition: "((price * (qty + 1)) < (100))"}},
+			uniques: []UniqueConstraint{{Columns: []string{"price"}}},
+		},
+		{
+			name: "quoted names",
+			sql: "CREATE TABLE \"order items\" (\"item, id\" INT UNIQUE, [total)] REAL CHECK ([total)] > 0), " +
+				"`say \"\"hi` TEXT, CONSTRAINT \"odd \"\"name\" UNIQUE (\"item, id\", `say \"\"hi`))",
+			checks: []CheckConstraint{{Column: "total)", Definition: "([total)] > 0)"}},
+			uniques: []UniqueConstraint{
+				{Columns: []string{"item, id"}},
+				{Name: `odd "name`, Columns: []string{"item, id", `say ""hi`}},
+			},
+		},
+		{
+			name:    "string literals",
+			sql:     "CREATE TABLE t (code TEXT DEFAULT 'a,b)' CHECK (code <> 'x''(y'), kind TEXT CHECK (kind IN ('a', 'b')))",
+			checks:  []CheckConstraint{{Column: "code", Definition: "(code <> 'x''(y')"}, {Column: "kind", Definition: "(kind IN ('a', 'b'))"}},
+			uniques: []UniqueConstraint{},
+		},
+		{
+			name: "comments",
+			sql: `CREATE TABLE t ( -- columns (a, b
+				a INT /* UNIQUE, ( */ CHECK (a > 0), -- CHECK (a < 0)
+				b INT UNIQUE -- trailing )
+			)`,
+			checks:  []CheckConstraint{{Column: "a", Definition: "(a > 0)"}},
+			uniques: []UniqueConstraint{{Columns: []string{"b"}}},
+		},
+		{
+			name:    "unterminated",
+			sql:     "CREATE TABLE t (a INT CHECK (a > 0), b TEXT DEFAULT 'x)",
+			checks:  []CheckConstraint{},
+			uniques: []UniqueConstraint{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checks, uniques := parseSQLiteConstraints(tt.sql)
+			if !reflect.DeepEqual(checks, tt.checks) {
+				t.Errorf("checks = %+v, want %+v", checks, tt.checks)
+			}
+			if !reflect.DeepEqual(uniques, tt.uniques) {
+				t.Errorf("uniques = %+v, want %+v", uniques, tt.uniques)
+			}
+		})
+	}
+}
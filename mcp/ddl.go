@@ -0,0 +1,178 @@
+package mcp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DDLObject is the DDL of a table or view returned by generate_ddl
+type DDLObject struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`   // table or view
+	Source string `json:"source"` // native (written by the database) or reconstructed from the catalog
+	// references are the tables or views of the same schema the object depends on
+	references []string
+	// statements is the DDL of a native object
+	statements []string
+	// table is the catalog metadata of a reconstructed table
+	table *ddlTable
+}
+
+// ddlTable holds what a CREATE TABLE statement is reconstructed from
+type ddlTable struct {
+	schema      string
+	name        string
+	columns     []ddlColumn
+	constraints []ddlConstraint // PRIMARY KEY, UNIQUE and CHECK
+	foreignKeys []ddlConstraint
+	indexes     []string // complete CREATE INDEX statements
+	comment     string
+}
+
+// ddlColumn is a column of a reconstructed table
+type ddlColumn struct {
+	Name     string
+	Type     string // empty for SQL Server computed columns
+	Nullable bool
+	Default  string
+	// Generated is the identity or computed column clause
+	Generated string
+	Comment   string
+}
+
+// ddlConstraint is a table constraint; Definition follows CONSTRAINT name
+type ddlConstraint struct {
+	Name       string
+	Type       string
+	Definition string
+	RefSchema  string
+	RefTable   string
+}
+
+// ddlConstraintOrder sorts primary keys first, then unique, check and foreign key constraints
+var ddlConstraintOrder = map[string]int{"PRIMARY KEY": 0, "UNIQUE": 1, "CHECK": 2, "FOREIGN KEY": 3}
+
+// orderDDLObjects sorts objects so that each comes after the objects it references,
+// keeping the given order otherwise. Objects in a reference cycle are appended in the
+// given order.
+func orderDDLObjects(objects []*DDLObject) []*DDLObject {
+	index := make(map[string]int, len(objects))
+	for i, object := range objects {
+		index[strings.ToLower(object.Name)] = i
+	}
+
+	done := make([]bool, len(objects))
+	var ordered []*DDLObject
+	for progress := true; progress; {
+		progress = false
+		for i, object := range objects {
+			if done[i] {
+				continue
+			}
+			ready := true
+			for _, ref := range object.references {
+				if j, ok := index[strings.ToLower(ref)]; ok && j != i && !done[j] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				done[i], progress = true, true
+				ordered = append(ordered, object)
+				break // restart to keep the given order among ready objects
+			}
+		}
+	}
+
+	for i, object := range objects {
+		if !done[i] {
+			ordered = append(ordered, object)
+		}
+	}
+	return ordered
+}
+
+// renderTable returns the statements creating a reconstructed table. Foreign keys to
+// tables that are not created yet are returned separately, as ALTER TABLE statements
+// to run once every table exists.
+func renderTable(qb *QueryBuilder, table *ddlTable, created func(schema, table string) bool) ([]string, []string) {
+	name := qb.QualifyTable(table.schema, table.name)
+
+	var lines []string
+	for _, col := range table.columns {
+		line := qb.QuoteIdentifier(col.Name)
+		if col.Type != "" {
+			line += " " + col.Type
+		}
+		if col.Generated != "" {
+			line += " " + col.Generated
+		}
+		if col.Default != "" {
+			line += " DEFAULT " + col.Default
+		}
+		if !col.Nullable && col.Type != "" {
+			line += " NOT NULL"
+		}
+		lines = append(lines, line)
+	}
+
+	constraints := append([]ddlConstraint(nil), table.constraints...)
+	sort.SliceStable(constraints, func(i, j int) bool {
+		return ddlConstraintOrder[constraints[i].Type] < ddlConstraintOrder[constraints[j].Type]
+	})
+	for _, c := range constraints {
+		lines = append(lines, fmt.Sprintf("CONSTRAINT %s %s", qb.QuoteIdentifier(c.Name), c.Definition))
+	}
+
+	var deferred []string
+	for _, fk := range table.foreignKeys {
+		if created(fk.RefSchema, fk.RefTable) {
+			lines = append(lines, fmt.Sprintf("CONSTRAINT %s %s", qb.QuoteIdentifier(fk.Name), fk.Definition))
+			continue
+		}
+		deferred = append(deferred, fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s %s;", name, qb.QuoteIdentifier(fk.Name), fk.Definition))
+	}
+
+	statements := []string{fmt.Sprintf("CREATE TABLE %s (\n    %s\n);", name, strings.Join(lines, ",\n    "))}
+	for _, index := range table.indexes {
+		statements = append(statements, strings.TrimRight(index, "; \n")+";")
+	}
+	if table.comment != "" {
+		statements = append(statements, commentStatement(qb, table.schema, table.name, "", table.comment))
+	}
+	for _, col := range table.columns {
+		if col.Comment != "" {
+			statements = append(statements, commentStatement(qb, table.schema, table.name, col.Name, col.Comment))
+		}
+	}
+	return statements, deferred
+}
+
+// commentStatement returns the statement setting the comment of a table, or of one of
+// its columns when column is set
+func commentStatement(qb *QueryBuilder, schema, table, column, comment string) string {
+	quoted := strings.ReplaceAll(comment, "'", "''")
+	if qb.IsSQLServer() {
+		statement := fmt.Sprintf("EXEC sys.sp_addextendedproperty @name = N'MS_Description', @value = N'%s', "+
+			"@level0type = N'SCHEMA', @level0name = N'%s', @level1type = N'TABLE', @level1name = N'%s'",
+			quoted, strings.ReplaceAll(schema, "'", "''"), strings.ReplaceAll(table, "'", "''"))
+		if column != "" {
+			statement += fmt.Sprintf(", @level2type = N'COLUMN', @level2name = N'%s'", strings.ReplaceAll(column, "'", "''"))
+		}
+		return statement + ";"
+	}
+	if column != "" {
+		return fmt.Sprintf("COMMENT ON COLUMN %s.%s IS '%s';", qb.QualifyTable(schema, table), qb.QuoteIdentifier(column), quoted)
+	}
+	return fmt.Sprintf("COMMENT ON TABLE %s IS '%s';", qb.QualifyTable(schema, table), quoted)
+}
+
+// terminateStatement ends a native DDL statement with a semicolon
+func terminateStatement(statement string) string {
+	statement = strings.TrimSpace(statement)
+	if statement == "" || strings.HasSuffix(statement, ";") {
+		return statement
+	}
+	return statement + ";"
+}
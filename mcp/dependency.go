@@ -0,0 +1,231 @@
+package mcp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DependencyObject is a database object of the dependency graph
+type DependencyObject struct {
+	Schema string `json:"schema,omitempty"`
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+}
+
+// key identifies the object in the graph, ignoring case
+func (o DependencyObject) key() string {
+	return strings.ToLower(o.Schema) + "." + strings.ToLower(o.Name)
+}
+
+// DependencyNode is an object of a dependency tree with the objects it leads to.
+// Cycle marks an object already on the path from the root, which is not expanded again.
+type DependencyNode struct {
+	DependencyObject
+	Depth    int               `json:"depth"`
+	Cycle    bool              `json:"cycle,omitempty"`
+	Children []*DependencyNode `json:"children,omitempty"`
+}
+
+// DependencyGraph holds the dependencies between the objects of a database
+type DependencyGraph struct {
+	objects map[string]*DependencyObject
+	uses    map[string][]string
+	usedBy  map[string][]string
+}
+
+func newDependencyGraph() *DependencyGraph {
+	return &DependencyGraph{
+		objects: make(map[string]*DependencyObject),
+		uses:    make(map[string][]string),
+		usedBy:  make(map[string][]string),
+	}
+}
+
+// add records that object depends on referenced
+func (g *DependencyGraph) add(object, referenced DependencyObject) {
+	from, to := g.object(object), g.object(referenced)
+	if from == to || containsFold(g.uses[from], to) {
+		return
+	}
+	g.uses[from] = append(g.uses[from], to)
+	g.usedBy[to] = append(g.usedBy[to], from)
+}
+
+// object registers an object and returns its key. An unresolved reference takes
+// the type of the object once it is known.
+func (g *DependencyGraph) object(o DependencyObject) string {
+	key := o.key()
+	if known, ok := g.objects[key]; !ok {
+		g.objects[key] = &o
+	} else if known.Type == "unresolved" && o.Type != "unresolved" {
+		known.Type = o.Type
+	}
+	return key
+}
+
+// find returns the object of the graph named name in schema
+func (g *DependencyGraph) find(schema, name string) *DependencyObject {
+	return g.objects[DependencyObject{Schema: schema, Name: name}.key()]
+}
+
+// Tree returns the objects the root depends on, or with dependents the objects
+// depending on it, transitively up to depth levels. Only objects of the given
+// types (all when empty) accepted by visible are followed. It also returns the
+// number of nodes and whether the tree was cut at MaxDependencyNodes.
+func (g *DependencyGraph) Tree(root *DependencyObject, dependents bool, depth int, types []string,
+	visible func(DependencyObject) bool) (*DependencyNode, int, bool) {
+	edges := g.uses
+	if dependents {
+		edges = g.usedBy
+	}
+
+	count, truncated := 1, false
+	onPath := map[string]bool{}
+	var expand func(node *DependencyNode)
+	expand = func(node *DependencyNode) {
+		key := node.key()
+		if node.Depth >= depth {
+			return
+		}
+		onPath[key] = true
+		defer delete(onPath, key)
+
+		next := append([]string(nil), edges[key]...)
+		sort.Strings(next)
+		for _, childKey := range next {
+			child := *g.objects[childKey]
+			if (len(types) > 0 && !containsFold(types, child.Type)) || !visible(child) {
+				continue
+			}
+			if count >= MaxDependencyNodes {
+				truncated = true
+				return
+			}
+			count++
+			childNode := &DependencyNode{DependencyObject: child, Depth: node.Depth + 1}
+			if onPath[childKey] {
+				childNode.Cycle = true
+			} else {
+				expand(childNode)
+			}
+			node.Children = append(node.Children, childNode)
+		}
+	}
+
+	tree := &DependencyNode{DependencyObject: *root}
+	expand(tree)
+	return tree, count, truncated
+}
+
+// Mermaid renders the tree as a Mermaid flowchart whose arrows point from each
+// object to the objects it depends on
+func (n *DependencyNode) Mermaid(dependents bool) string {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+
+	ids := map[string]string{}
+	seen := map[string]bool{}
+	id := func(node *DependencyNode) string {
+		key := node.key()
+		if ids[key] == "" {
+			ids[key] = fmt.Sprintf("n%d", len(ids))
+			label := node.Name
+			if node.Schema != "" {
+				label = node.Schema + "." + node.Name
+			}
+			label = strings.ReplaceAll(label, `"`, "#quot;")
+			fmt.Fprintf(&b, "    %s[\"%s<br/><i>%s</i>\"]\n", ids[key], label, node.Type)
+		}
+		return ids[key]
+	}
+
+	var walk func(node *DependencyNode)
+	walk = func(node *DependencyNode) {
+		parent := id(node)
+		for _, child := range node.Children {
+			from, to := parent, id(child)
+			if dependents {
+				from, to = to, from
+			}
+			if edge := from + "-->" + to; !seen[edge] {
+				seen[edge] = true
+				fmt.Fprintf(&b, "    %s --> %s\n", from, to)
+			}
+			walk(child)
+		}
+	}
+	walk(n)
+	return b.String()
+}
+
+// parseSQLiteDependencies builds the graph of a SQLite database from the SQL of
+// its views and triggers, given as rows of type, name, table and sql
+func parseSQLiteDependencies(objects [][4]string) *DependencyGraph {
+	graph := newDependencyGraph()
+	types := make(map[string]string)
+	names := make(map[string]string)
+	for _, o := range objects {
+		types[strings.ToLower(o[1])] = o[0]
+		names[strings.ToLower(o[1])] = o[1]
+	}
+	reference := func(object DependencyObject, name string) {
+		lower := strings.ToLower(name)
+		if typ := types[lower]; (typ == "table" || typ == "view") && lower != strings.ToLower(object.Name) {
+			graph.add(object, DependencyObject{Schema: "main", Name: names[lower], Type: typ})
+		}
+	}
+
+	for _, o := range objects {
+		typ, name, table, definition := o[0], o[1], o[2], o[3]
+		object := DependencyObject{Schema: "main", Name: name, Type: typ}
+		graph.object(object)
+		tokens, err := tokenizeSQL(definition)
+		if err != nil {
+			continue
+		}
+
+		switch typ {
+		case "view":
+			if refs, err := ParseSQLReferences(definition); err == nil {
+				for _, ref := range refs.Tables {
+					if !refs.IsDerived(ref.Name) {
+						reference(object, ref.Name)
+					}
+				}
+			} else {
+				referencedTables(tokens, func(name string) { reference(object, name) })
+			}
+		case "trigger":
+			reference(object, table)
+			referencedTables(tokens, func(name string) { reference(object, name) })
+		}
+	}
+	return graph
+}
+
+// referencedTables calls found with the names following FROM, JOIN, INTO and UPDATE,
+// and the names listed after a FROM, in the tokens of a statement
+func referencedTables(tokens []sqlToken, found func(name string)) {
+	inFrom := false
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		switch {
+		case token.is("FROM"):
+			inFrom = true
+		case token.kind == sqlTokenWord && sqlClauses[strings.ToUpper(token.text)], token.is(";"), token.is("("), token.is(")"):
+			inFrom = false
+		}
+		if i == 0 || !token.isIdentifier() {
+			continue
+		}
+		prev := tokens[i-1]
+		if prev.is("FROM") || prev.is("JOIN") || prev.is("INTO") || prev.is("UPDATE") || (inFrom && prev.is(",")) {
+			// A schema qualified name is read up to its last part
+			for i+2 < len(tokens) && tokens[i+1].is(".") && tokens[i+2].isIdentifier() {
+				i += 2
+			}
+			found(tokens[i].text)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package mcp
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // Dialect defines the interface for database-specific SQL generation
 type Dialect interface {
@@ -20,12 +23,35 @@ type Dialect interface {
 	// LikeOperator returns LIKE or ILIKE depending on case sensitivity
 	LikeOperator(caseSensitive bool) string
 
+	// LikeExpression returns a pattern match of column against placeholder
+	// with the requested case sensitivity
+	LikeExpression(column, placeholder string, caseSensitive bool) string
+
+	// LikePattern converts a LIKE pattern (% and _ wildcards, a backslash escaping
+	// the next character) into the value to bind for LikeExpression
+	LikePattern(pattern string, caseSensitive bool) string
+
+	// OrderByExpression returns an ORDER BY term for column.
+	// nulls is "", "first" or "last"; dialects without NULLS FIRST/LAST emulate it
+	OrderByExpression(column string, descending bool, nulls string) string
+
+	// IsLargeObjectType reports whether a column type is a LOB (BLOB, CLOB, large text, ...)
+	// maxLength is the character maximum length reported by the catalog (-1 for MAX types)
+	IsLargeObjectType(dataType string, maxLength int64) bool
+
+	// DateTrunc truncates a date/time column to the start of its bucket.
+	// unit is "day", "week" (weeks start on Monday) or "month"
+	DateTrunc(column, unit string) string
+
 	// ConcatOperator returns the concatenation expression for the given parts
 	ConcatOperator(parts ...string) string
 
 	// CurrentDatabase returns the SQL expression for current database name
 	CurrentDatabase() string
 
+	// CurrentSchemaQuery returns the SQL query for the current (default) schema name
+	CurrentSchemaQuery() string
+
 	// SystemSchemas returns the list of system schemas to exclude
 	SystemSchemas() []string
 
@@ -50,8 +76,17 @@ type Dialect interface {
 	// TriggerMetadata returns SQL components for trigger metadata queries
 	TriggerMetadata() TriggerMetadataSQL
 
+	// SequenceMetadata returns SQL components for sequence metadata queries
+	SequenceMetadata() SequenceMetadataSQL
+
+	// TypeMetadata returns SQL components for user-defined type metadata queries
+	TypeMetadata() TypeMetadataSQL
+
 	// DatabaseInfo returns SQL for database information queries
 	DatabaseInfo() DatabaseInfoSQL
+
+	// DependencyMetadata returns SQL for object dependency queries
+	DependencyMetadata() DependencyMetadataSQL
 }
 
 // DialectFeature represents a database feature
@@ -70,7 +105,7 @@ const (
 type TableMetadataSQL struct {
 	// ListTables base query (without filters)
 	ListTables string
-	// Columns to select: schema, name, type
+	// Columns to select: schema, name, type, description
 	ListTablesColumns string
 	// Filter for schema
 	SchemaFilter string
@@ -99,11 +134,29 @@ type TableMetadataSQL struct {
 
 	// GetForeignKeys query
 	GetForeignKeys string
+
+	// GetCheckConstraints query: constraint_name, column_name (for a single column
+	// or domain check), definition and domain_name (for domain checks)
+	GetCheckConstraints string
+
+	// GetUniqueConstraints query: constraint_name, column_name, in column order
+	GetUniqueConstraints string
+
+	// GetDefaultConstraints query for named default constraints: constraint_name,
+	// column_name and definition
+	GetDefaultConstraints string
+
+	// GetExclusionConstraints query: constraint_name and definition
+	GetExclusionConstraints string
+
+	// GetTableComment query: the description of the table
+	GetTableComment string
 }
 
 // ProcedureMetadataSQL contains SQL templates for procedure operations
 type ProcedureMetadataSQL struct {
-	// ListProcedures base query
+	// ListProcedures base query: routine_schema, routine_name, created, last_altered,
+	// package_name and specific_name, one row per overload
 	ListProcedures string
 	// SchemaFilter
 	SchemaFilter string
@@ -113,11 +166,25 @@ type ProcedureMetadataSQL struct {
 	OrderBy string
 	// GetCode query
 	GetCode string
+	// GetOverloadCode query: the code of one overload by schema, name and specific_name,
+	// "" when the database has no overloading
+	GetOverloadCode string
+	// GetPackageCode query: the body of the package a routine belongs to, "" when the
+	// database has no packages
+	GetPackageCode string
+	// GetArguments query: routine_name, package_name, specific_name, position,
+	// argument_name, mode, data_type, default_value, has_default (YES/NO) and return_type,
+	// one row per argument of each overload in position order. Position 0 is the return
+	// value; an overload without arguments has one row with a NULL data_type.
+	GetArguments string
+	// ListArguments query: the GetArguments rows of every procedure of a schema
+	ListArguments string
 }
 
 // FunctionMetadataSQL contains SQL templates for function operations
 type FunctionMetadataSQL struct {
-	// ListFunctions base query
+	// ListFunctions base query: routine_schema, routine_name, function_type, created,
+	// last_altered, package_name and specific_name, one row per overload
 	ListFunctions string
 	// TypeFilter for scalar/table functions
 	TypeFilterScalar string
@@ -131,11 +198,22 @@ type FunctionMetadataSQL struct {
 	OrderBy string
 	// GetCode query
 	GetCode string
+	// GetOverloadCode query: the code of one overload by schema, name and specific_name,
+	// "" when the database has no overloading
+	GetOverloadCode string
+	// GetPackageCode query: the body of the package a routine belongs to, "" when the
+	// database has no packages
+	GetPackageCode string
+	// GetArguments query: same columns as ProcedureMetadataSQL.GetArguments
+	GetArguments string
+	// ListArguments query: the GetArguments rows of every function of a schema
+	ListArguments string
 }
 
 // ViewMetadataSQL contains SQL templates for view operations
 type ViewMetadataSQL struct {
-	// ListViews base query
+	// ListViews base query: view_schema, view_name, created, last_altered,
+	// is_materialized (YES/NO) and last_refresh
 	ListViews string
 	// SchemaFilter
 	SchemaFilter string
@@ -145,6 +223,39 @@ type ViewMetadataSQL struct {
 	OrderBy string
 	// GetDefinition query
 	GetDefinition string
+	// GetMaterializedView query: definition and last_refresh of a materialized
+	// (or indexed) view, "" when the database has none
+	GetMaterializedView string
+}
+
+// SequenceMetadataSQL contains SQL templates for sequence operations
+type SequenceMetadataSQL struct {
+	// ListSequences base query: sequence_schema, sequence_name, data_type, start_value,
+	// min_value, max_value, increment, is_cycle (YES/NO), cache_size and last_value
+	ListSequences string
+	// SchemaFilter
+	SchemaFilter string
+	// NameFilter
+	NameFilter string
+	// OrderBy
+	OrderBy string
+}
+
+// TypeMetadataSQL contains SQL templates for user-defined type operations
+type TypeMetadataSQL struct {
+	// ListTypes base query: type_schema, type_name, kind, base_type, description and,
+	// for types that belong to a column (MySQL ENUM and SET), table_name
+	ListTypes string
+	// SchemaFilter
+	SchemaFilter string
+	// NameFilter
+	NameFilter string
+	// OrderBy
+	OrderBy string
+	// GetValues query: the labels of an enum type, in order
+	GetValues string
+	// GetAttributes query: name and type of the attributes of a composite, object or table type
+	GetAttributes string
 }
 
 // TriggerMetadataSQL contains SQL templates for trigger operations
@@ -179,6 +290,16 @@ type DatabaseInfoSQL struct {
 	SearchObjects string
 }
 
+// DependencyMetadataSQL contains SQL for object dependency queries
+type DependencyMetadataSQL struct {
+	// ListDependencies returns every dependency between the objects of the database:
+	// schema_name, object_name, object_type, referenced_schema, referenced_name, referenced_type
+	ListDependencies string
+	// ListObjectSQL returns the type, name, table and SQL of the objects whose
+	// dependencies are parsed from their definition instead
+	ListObjectSQL string
+}
+
 // BaseDialect provides common functionality for all dialects
 type BaseDialect struct {
 	driver DriverType
@@ -199,6 +320,89 @@ func (d *BaseDialect) LikeOperator(caseSensitive bool) string {
 	return "LIKE"
 }
 
+// LikeExpression default implementation
+// Case-insensitive matching lowers both sides so it does not depend on the column collation
+func (d *BaseDialect) LikeExpression(column, placeholder string, caseSensitive bool) string {
+	if caseSensitive {
+		return fmt.Sprintf("%s LIKE %s ESCAPE '\\'", column, placeholder)
+	}
+	return fmt.Sprintf("LOWER(%s) LIKE LOWER(%s) ESCAPE '\\'", column, placeholder)
+}
+
+// LikePattern default implementation keeps the escapes of % and _ only
+func (d *BaseDialect) LikePattern(pattern string, caseSensitive bool) string {
+	return rewriteLikeEscapes(pattern, "%_")
+}
+
+// rewriteLikeEscapes keeps the backslash before the characters of specials and
+// backslashes, drops it before other characters and doubles a trailing one, since
+// Oracle rejects an escape character followed by anything else
+func rewriteLikeEscapes(pattern, specials string) string {
+	var sb strings.Builder
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '\\' {
+			sb.WriteRune(runes[i])
+			continue
+		}
+		if i+1 == len(runes) {
+			sb.WriteString(`\\`)
+			break
+		}
+		i++
+		if runes[i] == '\\' || strings.ContainsRune(specials, runes[i]) {
+			sb.WriteRune('\\')
+		}
+		sb.WriteRune(runes[i])
+	}
+	return sb.String()
+}
+
+// escapeLikeValue escapes a literal for a LIKE pattern: backslashes, the % and _
+// wildcards and [, which starts a character class on SQL Server
+func escapeLikeValue(value string) string {
+	return likeValueEscaper.Replace(value)
+}
+
+var likeValueEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`, `[`, `\[`)
+
+// OrderByExpression default implementation emulates NULLS FIRST/LAST with a CASE term
+func (d *BaseDialect) OrderByExpression(column string, descending bool, nulls string) string {
+	direction := "ASC"
+	if descending {
+		direction = "DESC"
+	}
+	switch nulls {
+	case "first":
+		return fmt.Sprintf("CASE WHEN %s IS NULL THEN 0 ELSE 1 END, %s %s", column, column, direction)
+	case "last":
+		return fmt.Sprintf("CASE WHEN %s IS NULL THEN 1 ELSE 0 END, %s %s", column, column, direction)
+	default:
+		return fmt.Sprintf("%s %s", column, direction)
+	}
+}
+
+// nativeOrderByExpression renders an ORDER BY term using NULLS FIRST/LAST
+func nativeOrderByExpression(column string, descending bool, nulls string) string {
+	direction := "ASC"
+	if descending {
+		direction = "DESC"
+	}
+	switch nulls {
+	case "first":
+		return fmt.Sprintf("%s %s NULLS FIRST", column, direction)
+	case "last":
+		return fmt.Sprintf("%s %s NULLS LAST", column, direction)
+	default:
+		return fmt.Sprintf("%s %s", column, direction)
+	}
+}
+
+// IsLargeObjectType default implementation (no LOB types)
+func (d *BaseDialect) IsLargeObjectType(dataType string, maxLength int64) bool {
+	return false
+}
+
 // SupportsFeature default implementation (all features supported except specifics)
 func (d *BaseDialect) SupportsFeature(feature DialectFeature) bool {
 	return true
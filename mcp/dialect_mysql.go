@@ -37,6 +37,36 @@ func (d *MySQLDialect) PaginationClause(limit, offset int, orderBy string) strin
 	return pagination
 }
 
+// LikeExpression uses LIKE BINARY for case-sensitive matching
+// (default MySQL collations are case-insensitive); the backslash is the default escape character
+func (d *MySQLDialect) LikeExpression(column, placeholder string, caseSensitive bool) string {
+	if caseSensitive {
+		return fmt.Sprintf("%s LIKE BINARY %s", column, placeholder)
+	}
+	return fmt.Sprintf("LOWER(%s) LIKE LOWER(%s)", column, placeholder)
+}
+
+// IsLargeObjectType returns true for BLOB and TEXT columns (except TINY variants)
+func (d *MySQLDialect) IsLargeObjectType(dataType string, maxLength int64) bool {
+	switch strings.ToLower(dataType) {
+	case "blob", "mediumblob", "longblob", "text", "mediumtext", "longtext":
+		return true
+	}
+	return false
+}
+
+// DateTrunc uses DATE arithmetic (weeks start on Monday)
+func (d *MySQLDialect) DateTrunc(column, unit string) string {
+	switch unit {
+	case "week":
+		return fmt.Sprintf("DATE(DATE_SUB(%s, INTERVAL WEEKDAY(%s) DAY))", column, column)
+	case "month":
+		return fmt.Sprintf("DATE(DATE_SUB(%s, INTERVAL DAYOFMONTH(%s) - 1 DAY))", column, column)
+	default:
+		return fmt.Sprintf("DATE(%s)", column)
+	}
+}
+
 // ConcatOperator returns CONCAT function
 func (d *MySQLDialect) ConcatOperator(parts ...string) string {
 	return "CONCAT(" + strings.Join(parts, ", ") + ")"
@@ -47,6 +77,11 @@ func (d *MySQLDialect) CurrentDatabase() string {
 	return "DATABASE()"
 }
 
+// CurrentSchemaQuery returns DATABASE() (schemas are databases in MySQL)
+func (d *MySQLDialect) CurrentSchemaQuery() string {
+	return "SELECT DATABASE()"
+}
+
 // SystemSchemas returns MySQL system schemas
 func (d *MySQLDialect) SystemSchemas() []string {
 	return []string{"mysql", "information_schema", "performance_schema", "sys"}
@@ -59,12 +94,13 @@ func (d *MySQLDialect) TableMetadata() TableMetadataSQL {
 			SELECT
 				TABLE_SCHEMA,
 				TABLE_NAME,
-				TABLE_TYPE
+				TABLE_TYPE,
+				NULLIF(TABLE_COMMENT, '') AS description
 			FROM INFORMATION_SCHEMA.TABLES
 			WHERE TABLE_TYPE = 'BASE TABLE'
 				AND TABLE_SCHEMA NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')`,
-		SchemaFilter: " AND TABLE_SCHEMA = ?",
-		NameFilter:   " AND TABLE_NAME LIKE ?",
+		SchemaFilter: " AND TABLE_SCHEMA = %s",
+		NameFilter:   " AND TABLE_NAME LIKE %s",
 		OrderBy:      " ORDER BY TABLE_SCHEMA, TABLE_NAME",
 
 		DescribeTable: `
@@ -73,7 +109,8 @@ func (d *MySQLDialect) TableMetadata() TableMetadataSQL {
 				DATA_TYPE,
 				IS_NULLABLE,
 				COLUMN_DEFAULT,
-				CHARACTER_MAXIMUM_LENGTH
+				CHARACTER_MAXIMUM_LENGTH,
+				NULLIF(COLUMN_COMMENT, '') AS description
 			FROM INFORMATION_SCHEMA.COLUMNS
 			WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
 			ORDER BY ORDINAL_POSITION`,
@@ -100,7 +137,8 @@ func (d *MySQLDialect) TableMetadata() TableMetadataSQL {
 				c.NUMERIC_SCALE,
 				c.IS_NULLABLE,
 				c.COLUMN_DEFAULT,
-				CASE WHEN pk.COLUMN_NAME IS NOT NULL THEN 'YES' ELSE 'NO' END AS IS_PRIMARY_KEY
+				CASE WHEN pk.COLUMN_NAME IS NOT NULL THEN 'YES' ELSE 'NO' END AS IS_PRIMARY_KEY,
+				NULLIF(c.COLUMN_COMMENT, '') AS description
 			FROM INFORMATION_SCHEMA.COLUMNS c
 			LEFT JOIN (
 				SELECT ku.TABLE_SCHEMA, ku.TABLE_NAME, ku.COLUMN_NAME
@@ -144,12 +182,55 @@ func (d *MySQLDialect) TableMetadata() TableMetadataSQL {
 				kcu.COLUMN_NAME AS column_name,
 				kcu.REFERENCED_TABLE_SCHEMA AS referenced_schema,
 				kcu.REFERENCED_TABLE_NAME AS referenced_table,
-				kcu.REFERENCED_COLUMN_NAME AS referenced_column
+				kcu.REFERENCED_COLUMN_NAME AS referenced_column,
+				rc.DELETE_RULE AS on_delete,
+				rc.UPDATE_RULE AS on_update,
+				rc.MATCH_OPTION AS match_option,
+				'NO' AS is_deferrable,
+				'NO' AS initially_deferred
 			FROM INFORMATION_SCHEMA.KEY_COLUMN_USAGE kcu
+			JOIN INFORMATION_SCHEMA.REFERENTIAL_CONSTRAINTS rc
+				ON rc.CONSTRAINT_SCHEMA = kcu.CONSTRAINT_SCHEMA
+				AND rc.CONSTRAINT_NAME = kcu.CONSTRAINT_NAME
+				AND rc.TABLE_NAME = kcu.TABLE_NAME
 			WHERE kcu.TABLE_SCHEMA = ?
 				AND kcu.TABLE_NAME = ?
 				AND kcu.REFERENCED_TABLE_NAME IS NOT NULL
-			ORDER BY kcu.CONSTRAINT_NAME`,
+			ORDER BY kcu.CONSTRAINT_NAME, kcu.ORDINAL_POSITION`,
+
+		GetCheckConstraints: `
+			SELECT
+				cc.CONSTRAINT_NAME AS constraint_name,
+				NULL AS column_name,
+				cc.CHECK_CLAUSE AS definition,
+				NULL AS domain_name
+			FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS tc
+			JOIN INFORMATION_SCHEMA.CHECK_CONSTRAINTS cc
+				ON cc.CONSTRAINT_SCHEMA = tc.CONSTRAINT_SCHEMA
+				AND cc.CONSTRAINT_NAME = tc.CONSTRAINT_NAME
+			WHERE tc.CONSTRAINT_TYPE = 'CHECK'
+				AND tc.TABLE_SCHEMA = ?
+				AND tc.TABLE_NAME = ?
+			ORDER BY cc.CONSTRAINT_NAME`,
+
+		GetUniqueConstraints: `
+			SELECT
+				ku.CONSTRAINT_NAME AS constraint_name,
+				ku.COLUMN_NAME AS column_name
+			FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS tc
+			JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE ku
+				ON tc.CONSTRAINT_NAME = ku.CONSTRAINT_NAME
+				AND tc.TABLE_SCHEMA = ku.TABLE_SCHEMA
+				AND tc.TABLE_NAME = ku.TABLE_NAME
+			WHERE tc.CONSTRAINT_TYPE = 'UNIQUE'
+				AND tc.TABLE_SCHEMA = ?
+				AND tc.TABLE_NAME = ?
+			ORDER BY ku.CONSTRAINT_NAME, ku.ORDINAL_POSITION`,
+
+		GetTableComment: `
+			SELECT NULLIF(TABLE_COMMENT, '')
+			FROM INFORMATION_SCHEMA.TABLES
+			WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?`,
 	}
 }
 
@@ -161,18 +242,23 @@ func (d *MySQLDialect) ProcedureMetadata() ProcedureMetadataSQL {
 				ROUTINE_SCHEMA as routine_schema,
 				ROUTINE_NAME as routine_name,
 				CREATED as created,
-				LAST_ALTERED as last_altered
+				LAST_ALTERED as last_altered,
+				NULL as package_name,
+				SPECIFIC_NAME as specific_name
 			FROM INFORMATION_SCHEMA.ROUTINES
 			WHERE ROUTINE_TYPE = 'PROCEDURE'
 				AND ROUTINE_SCHEMA NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')`,
-		SchemaFilter: " AND ROUTINE_SCHEMA = ?",
-		NameFilter:   " AND ROUTINE_NAME LIKE ?",
+		SchemaFilter: " AND ROUTINE_SCHEMA = %s",
+		NameFilter:   " AND ROUTINE_NAME LIKE %s",
 		OrderBy:      " ORDER BY ROUTINE_SCHEMA, ROUTINE_NAME",
 
 		GetCode: `
 			SELECT ROUTINE_DEFINITION
 			FROM INFORMATION_SCHEMA.ROUTINES
 			WHERE ROUTINE_SCHEMA = ? AND ROUTINE_NAME = ? AND ROUTINE_TYPE = 'PROCEDURE'`,
+
+		GetArguments:  mysqlRoutineArguments("PROCEDURE", " AND r.ROUTINE_NAME = ?"),
+		ListArguments: mysqlRoutineArguments("PROCEDURE", ""),
 	}
 }
 
@@ -185,24 +271,51 @@ func (d *MySQLDialect) FunctionMetadata() FunctionMetadataSQL {
 				ROUTINE_NAME as routine_name,
 				'FUNCTION' as function_type,
 				CREATED as created,
-				LAST_ALTERED as last_altered
+				LAST_ALTERED as last_altered,
+				NULL as package_name,
+				SPECIFIC_NAME as specific_name
 			FROM INFORMATION_SCHEMA.ROUTINES
 			WHERE ROUTINE_TYPE = 'FUNCTION'
 				AND ROUTINE_SCHEMA NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')`,
 		TypeFilterScalar: "",
 		TypeFilterTable:  "",
 		TypeFilterAll:    "",
-		SchemaFilter:     " AND ROUTINE_SCHEMA = ?",
-		NameFilter:       " AND ROUTINE_NAME LIKE ?",
+		SchemaFilter:     " AND ROUTINE_SCHEMA = %s",
+		NameFilter:       " AND ROUTINE_NAME LIKE %s",
 		OrderBy:          " ORDER BY ROUTINE_SCHEMA, ROUTINE_NAME",
 
 		GetCode: `
 			SELECT ROUTINE_DEFINITION
 			FROM INFORMATION_SCHEMA.ROUTINES
 			WHERE ROUTINE_SCHEMA = ? AND ROUTINE_NAME = ? AND ROUTINE_TYPE = 'FUNCTION'`,
+
+		GetArguments:  mysqlRoutineArguments("FUNCTION", " AND r.ROUTINE_NAME = ?"),
+		ListArguments: mysqlRoutineArguments("FUNCTION", ""),
 	}
 }
 
+// mysqlRoutineArguments returns the parameters of the functions or procedures of a
+// schema, of one routine with nameFilter; the return value of a function is its parameter 0
+func mysqlRoutineArguments(routineType, nameFilter string) string {
+	return fmt.Sprintf(`
+			SELECT
+				r.ROUTINE_NAME AS routine_name,
+				NULL AS package_name,
+				r.SPECIFIC_NAME AS specific_name,
+				p.ORDINAL_POSITION AS position,
+				p.PARAMETER_NAME AS argument_name,
+				p.PARAMETER_MODE AS mode,
+				p.DTD_IDENTIFIER AS data_type,
+				NULL AS default_value,
+				'NO' AS has_default,
+				NULL AS return_type
+			FROM INFORMATION_SCHEMA.ROUTINES r
+			LEFT JOIN INFORMATION_SCHEMA.PARAMETERS p
+				ON p.SPECIFIC_SCHEMA = r.ROUTINE_SCHEMA AND p.SPECIFIC_NAME = r.SPECIFIC_NAME AND p.ROUTINE_TYPE = r.ROUTINE_TYPE
+			WHERE r.ROUTINE_SCHEMA = ? AND r.ROUTINE_TYPE = '%s'%s
+			ORDER BY r.ROUTINE_NAME, r.SPECIFIC_NAME, p.ORDINAL_POSITION`, routineType, nameFilter)
+}
+
 // ViewMetadata returns MySQL view metadata queries
 func (d *MySQLDialect) ViewMetadata() ViewMetadataSQL {
 	return ViewMetadataSQL{
@@ -211,11 +324,13 @@ func (d *MySQLDialect) ViewMetadata() ViewMetadataSQL {
 				TABLE_SCHEMA as view_schema,
 				TABLE_NAME as view_name,
 				NULL as created,
-				NULL as last_altered
+				NULL as last_altered,
+				'NO' as is_materialized,
+				NULL as last_refresh
 			FROM INFORMATION_SCHEMA.VIEWS
 			WHERE TABLE_SCHEMA NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')`,
-		SchemaFilter: " AND TABLE_SCHEMA = ?",
-		NameFilter:   " AND TABLE_NAME LIKE ?",
+		SchemaFilter: " AND TABLE_SCHEMA = %s",
+		NameFilter:   " AND TABLE_NAME LIKE %s",
 		OrderBy:      " ORDER BY TABLE_SCHEMA, TABLE_NAME",
 
 		GetDefinition: `
@@ -225,6 +340,32 @@ func (d *MySQLDialect) ViewMetadata() ViewMetadataSQL {
 	}
 }
 
+// SequenceMetadata returns empty (MySQL doesn't have sequences)
+func (d *MySQLDialect) SequenceMetadata() SequenceMetadataSQL {
+	return SequenceMetadataSQL{}
+}
+
+// TypeMetadata returns MySQL type metadata queries. MySQL has no user-defined
+// types, so the ENUM and SET columns are listed with their value lists instead.
+func (d *MySQLDialect) TypeMetadata() TypeMetadataSQL {
+	return TypeMetadataSQL{
+		ListTypes: `
+			SELECT
+				TABLE_SCHEMA AS type_schema,
+				COLUMN_NAME AS type_name,
+				DATA_TYPE AS kind,
+				COLUMN_TYPE AS base_type,
+				NULLIF(COLUMN_COMMENT, '') AS description,
+				TABLE_NAME AS table_name
+			FROM INFORMATION_SCHEMA.COLUMNS
+			WHERE DATA_TYPE IN ('enum', 'set')
+				AND TABLE_SCHEMA NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')`,
+		SchemaFilter: " AND TABLE_SCHEMA = %s",
+		NameFilter:   " AND COLUMN_NAME LIKE %s",
+		OrderBy:      " ORDER BY TABLE_SCHEMA, TABLE_NAME, ORDINAL_POSITION",
+	}
+}
+
 // TriggerMetadata returns MySQL trigger metadata queries
 func (d *MySQLDialect) TriggerMetadata() TriggerMetadataSQL {
 	return TriggerMetadataSQL{
@@ -238,9 +379,9 @@ func (d *MySQLDialect) TriggerMetadata() TriggerMetadataSQL {
 				NULL as modify_date
 			FROM INFORMATION_SCHEMA.TRIGGERS
 			WHERE TRIGGER_SCHEMA NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')`,
-		SchemaFilter:   " AND TRIGGER_SCHEMA = ?",
-		TableFilter:    " AND EVENT_OBJECT_TABLE = ?",
-		NameFilter:     " AND TRIGGER_NAME LIKE ?",
+		SchemaFilter:   " AND TRIGGER_SCHEMA = %s",
+		TableFilter:    " AND EVENT_OBJECT_TABLE = %s",
+		NameFilter:     " AND TRIGGER_NAME LIKE %s",
 		DisabledFilter: "", // MySQL doesn't have disabled triggers
 		OrderBy:        " ORDER BY TRIGGER_SCHEMA, EVENT_OBJECT_TABLE, TRIGGER_NAME",
 
@@ -297,3 +438,40 @@ func (d *MySQLDialect) DatabaseInfo() DatabaseInfoSQL {
 			ORDER BY TABLE_SCHEMA, TABLE_NAME`,
 	}
 }
+
+// DependencyMetadata returns MySQL dependency queries. VIEW_TABLE_USAGE and
+// VIEW_ROUTINE_USAGE need MySQL 8.0.13 or later.
+func (d *MySQLDialect) DependencyMetadata() DependencyMetadataSQL {
+	return DependencyMetadataSQL{
+		ListDependencies: `
+			SELECT
+				u.VIEW_SCHEMA AS schema_name,
+				u.VIEW_NAME AS object_name,
+				'view' AS object_type,
+				u.TABLE_SCHEMA AS referenced_schema,
+				u.TABLE_NAME AS referenced_name,
+				CASE WHEN v.TABLE_NAME IS NULL THEN 'table' ELSE 'view' END AS referenced_type
+			FROM INFORMATION_SCHEMA.VIEW_TABLE_USAGE u
+			LEFT JOIN INFORMATION_SCHEMA.VIEWS v
+				ON v.TABLE_SCHEMA = u.TABLE_SCHEMA AND v.TABLE_NAME = u.TABLE_NAME
+			UNION
+			SELECT
+				r.TABLE_SCHEMA,
+				r.TABLE_NAME,
+				'view',
+				r.SPECIFIC_SCHEMA,
+				r.SPECIFIC_NAME,
+				'function'
+			FROM INFORMATION_SCHEMA.VIEW_ROUTINE_USAGE r
+			UNION
+			SELECT
+				t.TRIGGER_SCHEMA,
+				t.TRIGGER_NAME,
+				'trigger',
+				t.EVENT_OBJECT_SCHEMA,
+				t.EVENT_OBJECT_TABLE,
+				'table'
+			FROM INFORMATION_SCHEMA.TRIGGERS t
+			WHERE t.TRIGGER_SCHEMA NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')`,
+	}
+}
@@ -36,6 +36,32 @@ func (d *OracleDialect) PaginationClause(limit, offset int, orderBy string) stri
 	return fmt.Sprintf("ORDER BY %s OFFSET %d ROWS FETCH NEXT %d ROWS ONLY", orderBy, offset, limit)
 }
 
+// OrderByExpression uses native NULLS FIRST/LAST
+func (d *OracleDialect) OrderByExpression(column string, descending bool, nulls string) string {
+	return nativeOrderByExpression(column, descending, nulls)
+}
+
+// IsLargeObjectType returns true for BLOB, CLOB, NCLOB, BFILE, LONG, LONG RAW and XMLTYPE columns
+func (d *OracleDialect) IsLargeObjectType(dataType string, maxLength int64) bool {
+	switch strings.ToUpper(dataType) {
+	case "BLOB", "CLOB", "NCLOB", "BFILE", "LONG", "LONG RAW", "XMLTYPE":
+		return true
+	}
+	return false
+}
+
+// DateTrunc uses TRUNC with DD, IW (ISO week, starts on Monday) or MM
+func (d *OracleDialect) DateTrunc(column, unit string) string {
+	switch unit {
+	case "week":
+		return fmt.Sprintf("TRUNC(%s, 'IW')", column)
+	case "month":
+		return fmt.Sprintf("TRUNC(%s, 'MM')", column)
+	default:
+		return fmt.Sprintf("TRUNC(%s, 'DD')", column)
+	}
+}
+
 // ConcatOperator returns || concatenation
 func (d *OracleDialect) ConcatOperator(parts ...string) string {
 	return strings.Join(parts, " || ")
@@ -46,6 +72,11 @@ func (d *OracleDialect) CurrentDatabase() string {
 	return "SYS_CONTEXT('USERENV', 'DB_NAME')"
 }
 
+// CurrentSchemaQuery returns the session CURRENT_SCHEMA
+func (d *OracleDialect) CurrentSchemaQuery() string {
+	return "SELECT SYS_CONTEXT('USERENV', 'CURRENT_SCHEMA') FROM DUAL"
+}
+
 // SystemSchemas returns Oracle system schemas
 func (d *OracleDialect) SystemSchemas() []string {
 	return []string{"SYS", "SYSTEM", "OUTLN", "XDB", "WMSYS", "CTXSYS", "MDSYS", "OLAPSYS"}
@@ -63,7 +94,9 @@ func (d *OracleDialect) TableMetadata() TableMetadataSQL {
 			SELECT
 				owner as table_schema,
 				table_name,
-				'BASE TABLE' as table_type
+				'BASE TABLE' as table_type,
+				(SELECT tc.comments FROM all_tab_comments tc
+					WHERE tc.owner = all_tables.owner AND tc.table_name = all_tables.table_name) as description
 			FROM all_tables
 			WHERE owner NOT IN ('SYS', 'SYSTEM', 'OUTLN', 'XDB', 'WMSYS', 'CTXSYS', 'MDSYS', 'OLAPSYS')`,
 		SchemaFilter: " AND owner = %s",
@@ -76,7 +109,10 @@ func (d *OracleDialect) TableMetadata() TableMetadataSQL {
 				data_type,
 				nullable as is_nullable,
 				data_default as column_default,
-				data_length as character_maximum_length
+				data_length as character_maximum_length,
+				(SELECT cc.comments FROM all_col_comments cc
+					WHERE cc.owner = all_tab_columns.owner AND cc.table_name = all_tab_columns.table_name
+						AND cc.column_name = all_tab_columns.column_name) as description
 			FROM all_tab_columns
 			WHERE owner = :1 AND table_name = :2
 			ORDER BY column_id`,
@@ -103,7 +139,8 @@ func (d *OracleDialect) TableMetadata() TableMetadataSQL {
 				c.data_scale,
 				c.nullable,
 				c.data_default,
-				CASE WHEN pk.column_name IS NOT NULL THEN 'YES' ELSE 'NO' END AS is_primary_key
+				CASE WHEN pk.column_name IS NOT NULL THEN 'YES' ELSE 'NO' END AS is_primary_key,
+				cc.comments AS description
 			FROM all_tab_columns c
 			LEFT JOIN (
 				SELECT acc.owner, acc.table_name, acc.column_name
@@ -115,6 +152,10 @@ func (d *OracleDialect) TableMetadata() TableMetadataSQL {
 			) pk ON c.owner = pk.owner
 				AND c.table_name = pk.table_name
 				AND c.column_name = pk.column_name
+			LEFT JOIN all_col_comments cc
+				ON cc.owner = c.owner
+				AND cc.table_name = c.table_name
+				AND cc.column_name = c.column_name
 			WHERE c.owner = :1 AND c.table_name = :2
 			ORDER BY c.column_id`,
 
@@ -133,11 +174,11 @@ func (d *OracleDialect) TableMetadata() TableMetadataSQL {
 			SELECT
 				i.index_name,
 				i.index_type,
-				i.uniqueness,
+				CASE WHEN i.uniqueness = 'UNIQUE' THEN 1 ELSE 0 END AS is_unique,
 				ic.column_name
 			FROM all_indexes i
 			JOIN all_ind_columns ic ON i.index_name = ic.index_name AND i.owner = ic.index_owner
-			WHERE i.owner = :1 AND i.table_name = :2
+			WHERE i.table_owner = :1 AND i.table_name = :2
 			ORDER BY i.index_name, ic.column_position`,
 
 		GetForeignKeys: `
@@ -146,7 +187,12 @@ func (d *OracleDialect) TableMetadata() TableMetadataSQL {
 				acc.column_name,
 				ac_ref.owner AS referenced_schema,
 				ac_ref.table_name AS referenced_table,
-				acc_ref.column_name AS referenced_column
+				acc_ref.column_name AS referenced_column,
+				ac.delete_rule AS on_delete,
+				NULL AS on_update,
+				NULL AS match_option,
+				CASE WHEN ac.deferrable = 'DEFERRABLE' THEN 'YES' ELSE 'NO' END AS is_deferrable,
+				CASE WHEN ac.deferred = 'DEFERRED' THEN 'YES' ELSE 'NO' END AS initially_deferred
 			FROM all_constraints ac
 			JOIN all_cons_columns acc
 				ON ac.constraint_name = acc.constraint_name
@@ -157,34 +203,81 @@ func (d *OracleDialect) TableMetadata() TableMetadataSQL {
 			JOIN all_cons_columns acc_ref
 				ON ac_ref.constraint_name = acc_ref.constraint_name
 				AND ac_ref.owner = acc_ref.owner
+				AND acc_ref.position = acc.position
 			WHERE ac.constraint_type = 'R'
 				AND ac.owner = :1
 				AND ac.table_name = :2
+			ORDER BY ac.constraint_name, acc.position`,
+
+		GetCheckConstraints: `
+			SELECT
+				ac.constraint_name,
+				(SELECT MIN(acc.column_name)
+					FROM all_cons_columns acc
+					WHERE acc.owner = ac.owner AND acc.constraint_name = ac.constraint_name
+					HAVING COUNT(*) = 1) AS column_name,
+				ac.search_condition_vc AS definition,
+				NULL AS domain_name
+			FROM all_constraints ac
+			WHERE ac.constraint_type = 'C'
+				AND ac.owner = :1
+				AND ac.table_name = :2
+				AND NOT (ac.generated = 'GENERATED NAME' AND ac.search_condition_vc LIKE '% IS NOT NULL')
 			ORDER BY ac.constraint_name`,
+
+		GetUniqueConstraints: `
+			SELECT
+				ac.constraint_name,
+				acc.column_name
+			FROM all_constraints ac
+			JOIN all_cons_columns acc
+				ON ac.constraint_name = acc.constraint_name
+				AND ac.owner = acc.owner
+			WHERE ac.constraint_type = 'U'
+				AND ac.owner = :1
+				AND ac.table_name = :2
+			ORDER BY ac.constraint_name, acc.position`,
+
+		GetTableComment: `
+			SELECT comments
+			FROM all_tab_comments
+			WHERE owner = :1 AND table_name = :2`,
 	}
 }
 
 // ProcedureMetadata returns Oracle procedure metadata queries
 func (d *OracleDialect) ProcedureMetadata() ProcedureMetadataSQL {
 	return ProcedureMetadataSQL{
+		// Package procedures are told from functions by having no return value (position 0)
 		ListProcedures: `
 			SELECT
-				owner as routine_schema,
-				object_name as routine_name,
-				created,
-				last_ddl_time as last_altered
-			FROM all_procedures
-			WHERE object_type = 'PROCEDURE'
-				AND owner NOT IN ('SYS', 'SYSTEM')`,
-		SchemaFilter: " AND owner = %s",
-		NameFilter:   " AND object_name LIKE %s",
-		OrderBy:      " ORDER BY owner, object_name",
+				p.owner as routine_schema,
+				NVL(p.procedure_name, p.object_name) as routine_name,
+				o.created,
+				o.last_ddl_time as last_altered,
+				CASE WHEN p.object_type = 'PACKAGE' THEN p.object_name END as package_name,
+				TO_CHAR(p.subprogram_id) as specific_name
+			FROM all_procedures p
+			JOIN all_objects o ON o.object_id = p.object_id
+			WHERE p.owner NOT IN ('SYS', 'SYSTEM')
+				AND (p.object_type = 'PROCEDURE'
+					OR (p.object_type = 'PACKAGE' AND p.procedure_name IS NOT NULL AND NOT EXISTS (
+						SELECT 1 FROM all_arguments r
+						WHERE r.object_id = p.object_id AND r.subprogram_id = p.subprogram_id
+							AND r.position = 0 AND r.data_level = 0)))`,
+		SchemaFilter: " AND p.owner = %s",
+		NameFilter:   " AND NVL(p.procedure_name, p.object_name) LIKE %s",
+		OrderBy:      " ORDER BY p.owner, NVL(p.procedure_name, p.object_name), p.object_name, p.subprogram_id",
 
 		GetCode: `
 			SELECT text
 			FROM all_source
 			WHERE owner = :1 AND name = :2 AND type = 'PROCEDURE'
 			ORDER BY line`,
+
+		GetPackageCode: oraclePackageBody,
+		GetArguments:   oracleRoutineArguments("NOT EXISTS", " AND NVL(a.package_name, ' ') = NVL(:2, ' ') AND a.object_name = :3"),
+		ListArguments:  oracleRoutineArguments("NOT EXISTS", ""),
 	}
 }
 
@@ -193,48 +286,157 @@ func (d *OracleDialect) FunctionMetadata() FunctionMetadataSQL {
 	return FunctionMetadataSQL{
 		ListFunctions: `
 			SELECT
-				owner as routine_schema,
-				object_name as routine_name,
+				p.owner as routine_schema,
+				NVL(p.procedure_name, p.object_name) as routine_name,
 				'FUNCTION' as function_type,
-				created,
-				last_ddl_time as last_altered
-			FROM all_objects
-			WHERE object_type = 'FUNCTION'
-				AND owner NOT IN ('SYS', 'SYSTEM')`,
+				o.created,
+				o.last_ddl_time as last_altered,
+				CASE WHEN p.object_type = 'PACKAGE' THEN p.object_name END as package_name,
+				TO_CHAR(p.subprogram_id) as specific_name
+			FROM all_procedures p
+			JOIN all_objects o ON o.object_id = p.object_id
+			WHERE p.owner NOT IN ('SYS', 'SYSTEM')
+				AND (p.object_type = 'FUNCTION'
+					OR (p.object_type = 'PACKAGE' AND p.procedure_name IS NOT NULL AND EXISTS (
+						SELECT 1 FROM all_arguments r
+						WHERE r.object_id = p.object_id AND r.subprogram_id = p.subprogram_id
+							AND r.position = 0 AND r.data_level = 0)))`,
 		TypeFilterScalar: "",
 		TypeFilterTable:  "",
 		TypeFilterAll:    "",
-		SchemaFilter:     " AND owner = %s",
-		NameFilter:       " AND object_name LIKE %s",
-		OrderBy:          " ORDER BY owner, object_name",
+		SchemaFilter:     " AND p.owner = %s",
+		NameFilter:       " AND NVL(p.procedure_name, p.object_name) LIKE %s",
+		OrderBy:          " ORDER BY p.owner, NVL(p.procedure_name, p.object_name), p.object_name, p.subprogram_id",
 
 		GetCode: `
 			SELECT text
 			FROM all_source
 			WHERE owner = :1 AND name = :2 AND type = 'FUNCTION'
 			ORDER BY line`,
+
+		GetPackageCode: oraclePackageBody,
+		GetArguments:   oracleRoutineArguments("EXISTS", " AND NVL(a.package_name, ' ') = NVL(:2, ' ') AND a.object_name = :3"),
+		ListArguments:  oracleRoutineArguments("EXISTS", ""),
 	}
 }
 
+// oraclePackageBody returns the body of a package, line by line
+const oraclePackageBody = `
+			SELECT text
+			FROM all_source
+			WHERE owner = :1 AND name = :2 AND type = 'PACKAGE BODY'
+			ORDER BY line`
+
+// oracleRoutineArguments returns the arguments of the overloads of the standalone routines
+// and package members of an owner; nameFilter narrows them to one by package ("" when
+// standalone) and name. returnValue is EXISTS for functions and NOT EXISTS for procedures.
+func oracleRoutineArguments(returnValue, nameFilter string) string {
+	return fmt.Sprintf(`
+			SELECT
+				a.object_name AS routine_name,
+				a.package_name,
+				TO_CHAR(a.subprogram_id) AS specific_name,
+				a.position,
+				a.argument_name,
+				REPLACE(a.in_out, '/', '') AS mode,
+				CASE
+					WHEN a.type_subname IS NOT NULL THEN a.type_name || '.' || a.type_subname
+					WHEN a.type_name IS NOT NULL THEN a.type_name
+					ELSE a.data_type
+				END AS data_type,
+				NULL AS default_value,
+				CASE WHEN a.defaulted = 'Y' THEN 'YES' ELSE 'NO' END AS has_default,
+				NULL AS return_type
+			FROM all_arguments a
+			WHERE a.owner = :1%s
+				AND a.data_level = 0
+				AND %s (
+					SELECT 1 FROM all_arguments r
+					WHERE r.object_id = a.object_id AND r.subprogram_id = a.subprogram_id
+						AND r.position = 0 AND r.data_level = 0)
+			ORDER BY a.package_name, a.object_name, a.subprogram_id, a.position`, nameFilter, returnValue)
+}
+
 // ViewMetadata returns Oracle view metadata queries
 func (d *OracleDialect) ViewMetadata() ViewMetadataSQL {
 	return ViewMetadataSQL{
 		ListViews: `
-			SELECT
-				owner as view_schema,
-				view_name,
-				NULL as created,
-				NULL as last_altered
-			FROM all_views
-			WHERE owner NOT IN ('SYS', 'SYSTEM')`,
-		SchemaFilter: " AND owner = %s",
+			SELECT * FROM (
+				SELECT
+					owner as view_schema,
+					view_name,
+					CAST(NULL AS DATE) as created,
+					CAST(NULL AS DATE) as last_altered,
+					'NO' as is_materialized,
+					CAST(NULL AS DATE) as last_refresh
+				FROM all_views
+				UNION ALL
+				SELECT owner, mview_name, NULL, NULL, 'YES', last_refresh_date
+				FROM all_mviews
+			) WHERE view_schema NOT IN ('SYS', 'SYSTEM')`,
+		SchemaFilter: " AND view_schema = %s",
 		NameFilter:   " AND view_name LIKE %s",
-		OrderBy:      " ORDER BY owner, view_name",
+		OrderBy:      " ORDER BY view_schema, view_name",
 
 		GetDefinition: `
 			SELECT text
 			FROM all_views
 			WHERE owner = :1 AND view_name = :2`,
+
+		GetMaterializedView: `
+			SELECT query, last_refresh_date
+			FROM all_mviews
+			WHERE owner = :1 AND mview_name = :2`,
+	}
+}
+
+// SequenceMetadata returns Oracle sequence metadata queries.
+// ALL_SEQUENCES keeps no start value; last_number is the next number not cached.
+func (d *OracleDialect) SequenceMetadata() SequenceMetadataSQL {
+	return SequenceMetadataSQL{
+		ListSequences: `
+			SELECT
+				sequence_owner AS sequence_schema,
+				sequence_name,
+				'NUMBER' AS data_type,
+				NULL AS start_value,
+				min_value,
+				max_value,
+				increment_by AS increment,
+				CASE WHEN cycle_flag = 'Y' THEN 'YES' ELSE 'NO' END AS is_cycle,
+				cache_size,
+				last_number AS last_value
+			FROM all_sequences
+			WHERE sequence_owner NOT IN ('SYS', 'SYSTEM', 'OUTLN', 'XDB', 'WMSYS', 'CTXSYS', 'MDSYS', 'OLAPSYS')`,
+		SchemaFilter: " AND sequence_owner = %s",
+		NameFilter:   " AND sequence_name LIKE %s",
+		OrderBy:      " ORDER BY sequence_owner, sequence_name",
+	}
+}
+
+// TypeMetadata returns Oracle type metadata queries: object and collection types
+func (d *OracleDialect) TypeMetadata() TypeMetadataSQL {
+	return TypeMetadataSQL{
+		ListTypes: `
+			SELECT
+				t.owner AS type_schema,
+				t.type_name,
+				LOWER(t.typecode) AS kind,
+				CASE WHEN ct.coll_type IS NOT NULL THEN ct.coll_type || ' OF ' || ct.elem_type_name END AS base_type,
+				NULL AS description,
+				NULL AS table_name
+			FROM all_types t
+			LEFT JOIN all_coll_types ct ON ct.owner = t.owner AND ct.type_name = t.type_name
+			WHERE t.owner NOT IN ('SYS', 'SYSTEM', 'OUTLN', 'XDB', 'WMSYS', 'CTXSYS', 'MDSYS', 'OLAPSYS')`,
+		SchemaFilter: " AND t.owner = %s",
+		NameFilter:   " AND t.type_name LIKE %s",
+		OrderBy:      " ORDER BY t.owner, t.type_name",
+
+		GetAttributes: `
+			SELECT attr_name, attr_type_name
+			FROM all_type_attrs
+			WHERE owner = :1 AND type_name = :2
+			ORDER BY attr_no`,
 	}
 }
 
@@ -302,3 +504,23 @@ func (d *OracleDialect) DatabaseInfo() DatabaseInfoSQL {
 			ORDER BY owner, object_name`,
 	}
 }
+
+// DependencyMetadata returns Oracle dependency queries. A package and its body
+// are reported under the package name.
+func (d *OracleDialect) DependencyMetadata() DependencyMetadataSQL {
+	return DependencyMetadataSQL{
+		ListDependencies: `
+			SELECT DISTINCT
+				owner AS schema_name,
+				name AS object_name,
+				LOWER(REPLACE(type, ' BODY', '')) AS object_type,
+				referenced_owner AS referenced_schema,
+				referenced_name,
+				LOWER(REPLACE(referenced_type, ' BODY', '')) AS referenced_type
+			FROM all_dependencies
+			WHERE owner NOT IN ('SYS', 'SYSTEM', 'OUTLN', 'XDB', 'WMSYS', 'CTXSYS', 'MDSYS', 'OLAPSYS')
+			  AND referenced_owner NOT IN ('SYS', 'SYSTEM', 'PUBLIC', 'OUTLN', 'XDB', 'WMSYS', 'CTXSYS', 'MDSYS', 'OLAPSYS')
+			  AND referenced_link_name IS NULL
+			  AND NOT (owner = referenced_owner AND name = referenced_name)`,
+	}
+}
@@ -45,6 +45,31 @@ func (d *PostgresDialect) LikeOperator(caseSensitive bool) string {
 	return "LIKE"
 }
 
+// LikeExpression returns ILIKE for case-insensitive matching and LIKE otherwise;
+// the backslash is the default escape character
+func (d *PostgresDialect) LikeExpression(column, placeholder string, caseSensitive bool) string {
+	return fmt.Sprintf("%s %s %s", column, d.LikeOperator(caseSensitive), placeholder)
+}
+
+// OrderByExpression uses native NULLS FIRST/LAST
+func (d *PostgresDialect) OrderByExpression(column string, descending bool, nulls string) string {
+	return nativeOrderByExpression(column, descending, nulls)
+}
+
+// IsLargeObjectType returns true for bytea and xml columns
+func (d *PostgresDialect) IsLargeObjectType(dataType string, maxLength int64) bool {
+	switch strings.ToLower(dataType) {
+	case "bytea", "xml":
+		return true
+	}
+	return false
+}
+
+// DateTrunc uses DATE_TRUNC (weeks start on Monday)
+func (d *PostgresDialect) DateTrunc(column, unit string) string {
+	return fmt.Sprintf("DATE_TRUNC('%s', %s)", unit, column)
+}
+
 // ConcatOperator returns || concatenation
 func (d *PostgresDialect) ConcatOperator(parts ...string) string {
 	return strings.Join(parts, " || ")
@@ -55,6 +80,11 @@ func (d *PostgresDialect) CurrentDatabase() string {
 	return "current_database()"
 }
 
+// CurrentSchemaQuery returns current_schema()
+func (d *PostgresDialect) CurrentSchemaQuery() string {
+	return "SELECT current_schema()"
+}
+
 // SystemSchemas returns PostgreSQL system schemas
 func (d *PostgresDialect) SystemSchemas() []string {
 	return []string{"pg_catalog", "information_schema", "pg_toast"}
@@ -77,7 +107,8 @@ func (d *PostgresDialect) TableMetadata() TableMetadataSQL {
 			SELECT
 				table_schema,
 				table_name,
-				table_type
+				table_type,
+				obj_description((quote_ident(table_schema) || '.' || quote_ident(table_name))::regclass, 'pg_class') AS description
 			FROM information_schema.tables
 			WHERE table_type = 'BASE TABLE'
 				AND table_schema NOT IN ('pg_catalog', 'information_schema')`,
@@ -91,7 +122,9 @@ func (d *PostgresDialect) TableMetadata() TableMetadataSQL {
 				data_type,
 				is_nullable,
 				column_default,
-				character_maximum_length
+				character_maximum_length,
+				col_description((quote_ident(table_schema) || '.' || quote_ident(table_name))::regclass,
+					ordinal_position::int) AS description
 			FROM information_schema.columns
 			WHERE table_schema = $1 AND table_name = $2
 			ORDER BY ordinal_position`,
@@ -118,7 +151,9 @@ func (d *PostgresDialect) TableMetadata() TableMetadataSQL {
 				c.numeric_scale,
 				c.is_nullable,
 				c.column_default,
-				CASE WHEN pk.column_name IS NOT NULL THEN 'YES' ELSE 'NO' END AS is_primary_key
+				CASE WHEN pk.column_name IS NOT NULL THEN 'YES' ELSE 'NO' END AS is_primary_key,
+				col_description((quote_ident(c.table_schema) || '.' || quote_ident(c.table_name))::regclass,
+					c.ordinal_position::int) AS description
 			FROM information_schema.columns c
 			LEFT JOIN (
 				SELECT ku.table_schema, ku.table_name, ku.column_name
@@ -148,38 +183,100 @@ func (d *PostgresDialect) TableMetadata() TableMetadataSQL {
 
 		GetIndexes: `
 			SELECT
-				i.indexname AS index_name,
-				a.amname AS index_type,
+				ic.relname AS index_name,
+				am.amname AS index_type,
 				ix.indisunique AS is_unique,
-				a2.attname AS column_name
-			FROM pg_indexes i
-			JOIN pg_class c ON i.indexname = c.relname
-			JOIN pg_index ix ON c.oid = ix.indexrelid
-			JOIN pg_class t ON ix.indrelid = t.oid
-			JOIN pg_namespace n ON t.relnamespace = n.oid
-			JOIN pg_am a ON c.relam = a.oid
-			JOIN pg_attribute a2 ON a2.attrelid = t.oid AND a2.attnum = ANY(ix.indkey)
+				pg_get_indexdef(ix.indexrelid, k.position, true) AS column_name
+			FROM pg_index ix
+			JOIN pg_class ic ON ic.oid = ix.indexrelid
+			JOIN pg_class t ON t.oid = ix.indrelid
+			JOIN pg_namespace n ON n.oid = t.relnamespace
+			JOIN pg_am am ON am.oid = ic.relam
+			CROSS JOIN LATERAL generate_series(1, ix.indnkeyatts) AS k(position)
 			WHERE n.nspname = $1 AND t.relname = $2
-			ORDER BY i.indexname`,
+			ORDER BY ic.relname, k.position`,
 
 		GetForeignKeys: `
 			SELECT
-				tc.constraint_name,
-				kcu.column_name,
-				ccu.table_schema AS referenced_schema,
-				ccu.table_name AS referenced_table,
-				ccu.column_name AS referenced_column
-			FROM information_schema.table_constraints tc
-			JOIN information_schema.key_column_usage kcu
-				ON tc.constraint_name = kcu.constraint_name
-				AND tc.table_schema = kcu.table_schema
-			JOIN information_schema.constraint_column_usage ccu
-				ON ccu.constraint_name = tc.constraint_name
-				AND ccu.table_schema = tc.table_schema
-			WHERE tc.constraint_type = 'FOREIGN KEY'
-				AND tc.table_schema = $1
-				AND tc.table_name = $2
-			ORDER BY tc.constraint_name`,
+				c.conname AS constraint_name,
+				a.attname AS column_name,
+				rn.nspname AS referenced_schema,
+				rt.relname AS referenced_table,
+				ra.attname AS referenced_column,
+				CASE c.confdeltype WHEN 'a' THEN 'NO ACTION' WHEN 'r' THEN 'RESTRICT' WHEN 'c' THEN 'CASCADE'
+					WHEN 'n' THEN 'SET NULL' WHEN 'd' THEN 'SET DEFAULT' END AS on_delete,
+				CASE c.confupdtype WHEN 'a' THEN 'NO ACTION' WHEN 'r' THEN 'RESTRICT' WHEN 'c' THEN 'CASCADE'
+					WHEN 'n' THEN 'SET NULL' WHEN 'd' THEN 'SET DEFAULT' END AS on_update,
+				CASE c.confmatchtype WHEN 'f' THEN 'FULL' WHEN 'p' THEN 'PARTIAL' ELSE 'SIMPLE' END AS match_option,
+				CASE WHEN c.condeferrable THEN 'YES' ELSE 'NO' END AS is_deferrable,
+				CASE WHEN c.condeferred THEN 'YES' ELSE 'NO' END AS initially_deferred
+			FROM pg_constraint c
+			JOIN pg_class t ON t.oid = c.conrelid
+			JOIN pg_namespace n ON n.oid = t.relnamespace
+			JOIN pg_class rt ON rt.oid = c.confrelid
+			JOIN pg_namespace rn ON rn.oid = rt.relnamespace
+			CROSS JOIN LATERAL unnest(c.conkey, c.confkey) WITH ORDINALITY AS k(attnum, refattnum, position)
+			JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = k.attnum
+			JOIN pg_attribute ra ON ra.attrelid = c.confrelid AND ra.attnum = k.refattnum
+			WHERE c.contype = 'f'
+				AND n.nspname = $1
+				AND t.relname = $2
+			ORDER BY c.conname, k.position`,
+
+		GetCheckConstraints: `
+			SELECT
+				c.conname AS constraint_name,
+				a.attname AS column_name,
+				pg_get_constraintdef(c.oid) AS definition,
+				NULL AS domain_name
+			FROM pg_constraint c
+			JOIN pg_class t ON t.oid = c.conrelid
+			JOIN pg_namespace n ON n.oid = t.relnamespace
+			LEFT JOIN pg_attribute a ON a.attrelid = t.oid
+				AND array_length(c.conkey, 1) = 1
+				AND a.attnum = c.conkey[1]
+			WHERE c.contype = 'c' AND n.nspname = $1 AND t.relname = $2
+			UNION ALL
+			SELECT
+				c.conname,
+				a.attname,
+				pg_get_constraintdef(c.oid),
+				ty.typname
+			FROM pg_attribute a
+			JOIN pg_class t ON t.oid = a.attrelid
+			JOIN pg_namespace n ON n.oid = t.relnamespace
+			JOIN pg_type ty ON ty.oid = a.atttypid AND ty.typtype = 'd'
+			JOIN pg_constraint c ON c.contypid = ty.oid AND c.contype = 'c'
+			WHERE n.nspname = $1 AND t.relname = $2 AND a.attnum > 0 AND NOT a.attisdropped
+			ORDER BY 1`,
+
+		GetUniqueConstraints: `
+			SELECT
+				c.conname AS constraint_name,
+				a.attname AS column_name
+			FROM pg_constraint c
+			JOIN pg_class t ON t.oid = c.conrelid
+			JOIN pg_namespace n ON n.oid = t.relnamespace
+			CROSS JOIN LATERAL unnest(c.conkey) WITH ORDINALITY AS k(attnum, position)
+			JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+			WHERE c.contype = 'u' AND n.nspname = $1 AND t.relname = $2
+			ORDER BY c.conname, k.position`,
+
+		GetExclusionConstraints: `
+			SELECT
+				c.conname AS constraint_name,
+				pg_get_constraintdef(c.oid) AS definition
+			FROM pg_constraint c
+			JOIN pg_class t ON t.oid = c.conrelid
+			JOIN pg_namespace n ON n.oid = t.relnamespace
+			WHERE c.contype = 'x' AND n.nspname = $1 AND t.relname = $2
+			ORDER BY c.conname`,
+
+		GetTableComment: `
+			SELECT obj_description(c.oid, 'pg_class')
+			FROM pg_class c
+			JOIN pg_namespace n ON n.oid = c.relnamespace
+			WHERE n.nspname = $1 AND c.relname = $2`,
 	}
 }
 
@@ -188,22 +285,35 @@ func (d *PostgresDialect) ProcedureMetadata() ProcedureMetadataSQL {
 	return ProcedureMetadataSQL{
 		ListProcedures: `
 			SELECT
-				routine_schema,
-				routine_name,
-				created::timestamp AS created,
-				created::timestamp AS last_altered
-			FROM information_schema.routines
-			WHERE routine_type = 'PROCEDURE'
-				AND routine_schema NOT IN ('pg_catalog', 'information_schema')`,
-		SchemaFilter: " AND routine_schema = %s",
-		NameFilter:   " AND routine_name ILIKE %s",
-		OrderBy:      " ORDER BY routine_schema, routine_name",
+				n.nspname AS routine_schema,
+				p.proname AS routine_name,
+				NULL::timestamp AS created,
+				NULL::timestamp AS last_altered,
+				NULL AS package_name,
+				p.oid::text AS specific_name
+			FROM pg_proc p
+			JOIN pg_namespace n ON p.pronamespace = n.oid
+			WHERE n.nspname NOT IN ('pg_catalog', 'information_schema')
+				AND prokind = 'p'`,
+		SchemaFilter: " AND n.nspname = %s",
+		NameFilter:   " AND p.proname ILIKE %s",
+		OrderBy:      " ORDER BY n.nspname, p.proname, p.oid",
 
 		GetCode: `
 			SELECT pg_get_functiondef(p.oid)
 			FROM pg_proc p
 			JOIN pg_namespace n ON p.pronamespace = n.oid
-			WHERE n.nspname = $1 AND p.proname = $2 AND prokind = 'p'`,
+			WHERE n.nspname = $1 AND p.proname = $2 AND prokind = 'p'
+			ORDER BY p.oid`,
+
+		GetOverloadCode: `
+			SELECT pg_get_functiondef(p.oid)
+			FROM pg_proc p
+			JOIN pg_namespace n ON p.pronamespace = n.oid
+			WHERE n.nspname = $1 AND p.proname = $2 AND prokind = 'p' AND p.oid::text = $3`,
+
+		GetArguments:  postgresRoutineArguments('p', " AND p.proname = $2"),
+		ListArguments: postgresRoutineArguments('p', ""),
 	}
 }
 
@@ -216,7 +326,9 @@ func (d *PostgresDialect) FunctionMetadata() FunctionMetadataSQL {
 				p.proname AS routine_name,
 				CASE WHEN p.proretset THEN 'TABLE' ELSE 'SCALAR' END AS function_type,
 				NULL::timestamp AS created,
-				NULL::timestamp AS last_altered
+				NULL::timestamp AS last_altered,
+				NULL AS package_name,
+				p.oid::text AS specific_name
 			FROM pg_proc p
 			JOIN pg_namespace n ON p.pronamespace = n.oid
 			WHERE n.nspname NOT IN ('pg_catalog', 'information_schema')
@@ -226,35 +338,152 @@ func (d *PostgresDialect) FunctionMetadata() FunctionMetadataSQL {
 		TypeFilterAll:    "",
 		SchemaFilter:     " AND n.nspname = %s",
 		NameFilter:       " AND p.proname ILIKE %s",
-		OrderBy:          " ORDER BY n.nspname, p.proname",
+		OrderBy:          " ORDER BY n.nspname, p.proname, p.oid",
 
 		GetCode: `
 			SELECT pg_get_functiondef(p.oid)
 			FROM pg_proc p
 			JOIN pg_namespace n ON p.pronamespace = n.oid
-			WHERE n.nspname = $1 AND p.proname = $2 AND prokind = 'f'`,
+			WHERE n.nspname = $1 AND p.proname = $2 AND prokind = 'f'
+			ORDER BY p.oid`,
+
+		GetOverloadCode: `
+			SELECT pg_get_functiondef(p.oid)
+			FROM pg_proc p
+			JOIN pg_namespace n ON p.pronamespace = n.oid
+			WHERE n.nspname = $1 AND p.proname = $2 AND prokind = 'f' AND p.oid::text = $3`,
+
+		GetArguments:  postgresRoutineArguments('f', " AND p.proname = $2"),
+		ListArguments: postgresRoutineArguments('f', ""),
 	}
 }
 
+// postgresRoutineArguments returns the arguments of the overloads of the functions ('f')
+// or procedures ('p') of a schema, of one routine with nameFilter. proallargtypes is only set when there are OUT arguments; the
+// TABLE columns of set-returning functions are part of the result type instead.
+func postgresRoutineArguments(prokind byte, nameFilter string) string {
+	return fmt.Sprintf(`
+			SELECT
+				p.proname AS routine_name,
+				NULL AS package_name,
+				p.oid::text AS specific_name,
+				a.ord::int AS position,
+				NULLIF(p.proargnames[a.ord::int], '') AS argument_name,
+				CASE COALESCE(p.proargmodes[a.ord::int], 'i')
+					WHEN 'o' THEN 'OUT' WHEN 'b' THEN 'INOUT' WHEN 'v' THEN 'VARIADIC' ELSE 'IN'
+				END AS mode,
+				format_type(a.type_oid, NULL) AS data_type,
+				pg_get_function_arg_default(p.oid, a.ord::int) AS default_value,
+				CASE WHEN pg_get_function_arg_default(p.oid, a.ord::int) IS NULL THEN 'NO' ELSE 'YES' END AS has_default,
+				pg_get_function_result(p.oid) AS return_type
+			FROM pg_proc p
+			JOIN pg_namespace n ON p.pronamespace = n.oid
+			LEFT JOIN LATERAL unnest(COALESCE(p.proallargtypes, p.proargtypes::oid[])) WITH ORDINALITY AS a(type_oid, ord)
+				ON COALESCE(p.proargmodes[a.ord::int], 'i') <> 't'
+			WHERE n.nspname = $1 AND p.prokind = '%c'%s
+			ORDER BY p.proname, p.oid, a.ord`, prokind, nameFilter)
+}
+
 // ViewMetadata returns PostgreSQL view metadata queries
 func (d *PostgresDialect) ViewMetadata() ViewMetadataSQL {
 	return ViewMetadataSQL{
 		ListViews: `
-			SELECT
-				table_schema as view_schema,
-				table_name as view_name,
-				NULL::timestamp as created,
-				NULL::timestamp as last_altered
-			FROM information_schema.views
-			WHERE table_schema NOT IN ('pg_catalog', 'information_schema')`,
-		SchemaFilter: " AND table_schema = %s",
-		NameFilter:   " AND table_name ILIKE %s",
-		OrderBy:      " ORDER BY table_schema, table_name",
+			SELECT * FROM (
+				SELECT
+					table_schema as view_schema,
+					table_name as view_name,
+					NULL::timestamp as created,
+					NULL::timestamp as last_altered,
+					'NO' as is_materialized,
+					NULL::timestamp as last_refresh
+				FROM information_schema.views
+				WHERE table_schema NOT IN ('pg_catalog', 'information_schema')
+				UNION ALL
+				SELECT schemaname, matviewname, NULL::timestamp, NULL::timestamp, 'YES', NULL::timestamp
+				FROM pg_matviews
+				WHERE schemaname NOT IN ('pg_catalog', 'information_schema')
+			) v WHERE 1=1`,
+		SchemaFilter: " AND view_schema = %s",
+		NameFilter:   " AND view_name ILIKE %s",
+		OrderBy:      " ORDER BY view_schema, view_name",
 
 		GetDefinition: `
 			SELECT view_definition
 			FROM information_schema.views
 			WHERE table_schema = $1 AND table_name = $2`,
+
+		// PostgreSQL does not record when a materialized view was last refreshed
+		GetMaterializedView: `
+			SELECT definition, NULL::timestamp
+			FROM pg_matviews
+			WHERE schemaname = $1 AND matviewname = $2`,
+	}
+}
+
+// SequenceMetadata returns PostgreSQL sequence metadata queries
+func (d *PostgresDialect) SequenceMetadata() SequenceMetadataSQL {
+	return SequenceMetadataSQL{
+		ListSequences: `
+			SELECT
+				schemaname AS sequence_schema,
+				sequencename AS sequence_name,
+				data_type::text AS data_type,
+				start_value,
+				min_value,
+				max_value,
+				increment_by AS increment,
+				CASE WHEN cycle THEN 'YES' ELSE 'NO' END AS is_cycle,
+				cache_size,
+				last_value
+			FROM pg_sequences
+			WHERE schemaname NOT IN ('pg_catalog', 'information_schema')`,
+		SchemaFilter: " AND schemaname = %s",
+		NameFilter:   " AND sequencename ILIKE %s",
+		OrderBy:      " ORDER BY schemaname, sequencename",
+	}
+}
+
+// TypeMetadata returns PostgreSQL type metadata queries: enums, domains, ranges and
+// standalone composite types
+func (d *PostgresDialect) TypeMetadata() TypeMetadataSQL {
+	return TypeMetadataSQL{
+		ListTypes: `
+			SELECT
+				n.nspname AS type_schema,
+				t.typname AS type_name,
+				CASE t.typtype WHEN 'e' THEN 'enum' WHEN 'd' THEN 'domain' WHEN 'c' THEN 'composite' ELSE 'range' END AS kind,
+				CASE t.typtype
+					WHEN 'd' THEN format_type(t.typbasetype, t.typtypmod)
+					WHEN 'r' THEN format_type(r.rngsubtype, NULL)
+				END AS base_type,
+				obj_description(t.oid, 'pg_type') AS description,
+				NULL AS table_name
+			FROM pg_type t
+			JOIN pg_namespace n ON n.oid = t.typnamespace
+			LEFT JOIN pg_range r ON r.rngtypid = t.oid
+			LEFT JOIN pg_class c ON c.oid = t.typrelid
+			WHERE t.typtype IN ('e', 'd', 'c', 'r')
+				AND (t.typtype <> 'c' OR c.relkind = 'c')
+				AND n.nspname NOT IN ('pg_catalog', 'information_schema', 'pg_toast')`,
+		SchemaFilter: " AND n.nspname = %s",
+		NameFilter:   " AND t.typname ILIKE %s",
+		OrderBy:      " ORDER BY n.nspname, t.typname",
+
+		GetValues: `
+			SELECT e.enumlabel
+			FROM pg_enum e
+			JOIN pg_type t ON t.oid = e.enumtypid
+			JOIN pg_namespace n ON n.oid = t.typnamespace
+			WHERE n.nspname = $1 AND t.typname = $2
+			ORDER BY e.enumsortorder`,
+
+		GetAttributes: `
+			SELECT a.attname, format_type(a.atttypid, a.atttypmod)
+			FROM pg_type t
+			JOIN pg_namespace n ON n.oid = t.typnamespace
+			JOIN pg_attribute a ON a.attrelid = t.typrelid
+			WHERE n.nspname = $1 AND t.typname = $2 AND a.attnum > 0 AND NOT a.attisdropped
+			ORDER BY a.attnum`,
 	}
 }
 
@@ -336,3 +565,101 @@ func (d *PostgresDialect) DatabaseInfo() DatabaseInfoSQL {
 			ORDER BY table_schema, table_name`,
 	}
 }
+
+// DependencyMetadata returns PostgreSQL dependency queries: views (through their
+// rewrite rules), routines with SQL-standard bodies and triggers
+func (d *PostgresDialect) DependencyMetadata() DependencyMetadataSQL {
+	return DependencyMetadataSQL{
+		ListDependencies: `
+			SELECT
+				vn.nspname AS schema_name,
+				v.relname AS object_name,
+				CASE v.relkind WHEN 'm' THEN 'materialized view' ELSE 'view' END AS object_type,
+				rn.nspname AS referenced_schema,
+				rc.relname AS referenced_name,
+				CASE rc.relkind
+					WHEN 'v' THEN 'view'
+					WHEN 'm' THEN 'materialized view'
+					WHEN 'S' THEN 'sequence'
+					WHEN 'f' THEN 'foreign table'
+					ELSE 'table'
+				END AS referenced_type
+			FROM pg_depend d
+			JOIN pg_rewrite r ON r.oid = d.objid
+			JOIN pg_class v ON v.oid = r.ev_class
+			JOIN pg_namespace vn ON vn.oid = v.relnamespace
+			JOIN pg_class rc ON rc.oid = d.refobjid
+			JOIN pg_namespace rn ON rn.oid = rc.relnamespace
+			WHERE d.classid = 'pg_rewrite'::regclass
+			  AND d.refclassid = 'pg_class'::regclass
+			  AND d.deptype = 'n'
+			  AND rc.oid <> v.oid
+			UNION
+			SELECT
+				vn.nspname,
+				v.relname,
+				CASE v.relkind WHEN 'm' THEN 'materialized view' ELSE 'view' END,
+				pn.nspname,
+				p.proname,
+				CASE p.prokind WHEN 'p' THEN 'procedure' ELSE 'function' END
+			FROM pg_depend d
+			JOIN pg_rewrite r ON r.oid = d.objid
+			JOIN pg_class v ON v.oid = r.ev_class
+			JOIN pg_namespace vn ON vn.oid = v.relnamespace
+			JOIN pg_proc p ON p.oid = d.refobjid
+			JOIN pg_namespace pn ON pn.oid = p.pronamespace
+			WHERE d.classid = 'pg_rewrite'::regclass
+			  AND d.refclassid = 'pg_proc'::regclass
+			  AND d.deptype = 'n'
+			  AND pn.nspname NOT IN ('pg_catalog', 'information_schema')
+			UNION
+			SELECT
+				pn.nspname,
+				p.proname,
+				CASE p.prokind WHEN 'p' THEN 'procedure' ELSE 'function' END,
+				rn.nspname,
+				rc.relname,
+				CASE rc.relkind
+					WHEN 'v' THEN 'view'
+					WHEN 'm' THEN 'materialized view'
+					WHEN 'S' THEN 'sequence'
+					WHEN 'f' THEN 'foreign table'
+					ELSE 'table'
+				END
+			FROM pg_depend d
+			JOIN pg_proc p ON p.oid = d.objid
+			JOIN pg_namespace pn ON pn.oid = p.pronamespace
+			JOIN pg_class rc ON rc.oid = d.refobjid
+			JOIN pg_namespace rn ON rn.oid = rc.relnamespace
+			WHERE d.classid = 'pg_proc'::regclass
+			  AND d.refclassid = 'pg_class'::regclass
+			  AND d.deptype = 'n'
+			  AND rc.relkind IN ('r', 'p', 'v', 'm', 'S', 'f')
+			UNION
+			SELECT
+				tn.nspname,
+				tg.tgname,
+				'trigger',
+				tn.nspname,
+				t.relname,
+				CASE t.relkind WHEN 'v' THEN 'view' ELSE 'table' END
+			FROM pg_trigger tg
+			JOIN pg_class t ON t.oid = tg.tgrelid
+			JOIN pg_namespace tn ON tn.oid = t.relnamespace
+			WHERE NOT tg.tgisinternal
+			UNION
+			SELECT
+				tn.nspname,
+				tg.tgname,
+				'trigger',
+				pn.nspname,
+				p.proname,
+				'function'
+			FROM pg_trigger tg
+			JOIN pg_class t ON t.oid = tg.tgrelid
+			JOIN pg_namespace tn ON tn.oid = t.relnamespace
+			JOIN pg_proc p ON p.oid = tg.tgfoid
+			JOIN pg_namespace pn ON pn.oid = p.pronamespace
+			WHERE NOT tg.tgisinternal`,
+	}
+}
@@ -37,6 +37,69 @@ func (d *SQLiteDialect) PaginationClause(limit, offset int, orderBy string) stri
 	return pagination
 }
 
+// LikeExpression uses GLOB for case-sensitive matching
+// (SQLite LIKE is always case-insensitive for ASCII characters)
+func (d *SQLiteDialect) LikeExpression(column, placeholder string, caseSensitive bool) string {
+	if caseSensitive {
+		return fmt.Sprintf("%s GLOB %s", column, placeholder)
+	}
+	return fmt.Sprintf("%s LIKE %s ESCAPE '\\'", column, placeholder)
+}
+
+// LikePattern converts LIKE wildcards to GLOB wildcards for case-sensitive matching.
+// GLOB has no escape character: escaped characters and GLOB wildcards are bracketed.
+func (d *SQLiteDialect) LikePattern(pattern string, caseSensitive bool) string {
+	if !caseSensitive {
+		return d.BaseDialect.LikePattern(pattern, caseSensitive)
+	}
+	var sb strings.Builder
+	runes := []rune(pattern)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		escaped := r == '\\' && i+1 < len(runes)
+		if escaped {
+			i++
+			r = runes[i]
+		}
+		switch {
+		case r == '%' && !escaped:
+			sb.WriteRune('*')
+		case r == '_' && !escaped:
+			sb.WriteRune('?')
+		case r == '*' || r == '?' || r == '[':
+			sb.WriteRune('[')
+			sb.WriteRune(r)
+			sb.WriteRune(']')
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// OrderByExpression uses native NULLS FIRST/LAST (SQLite 3.30+)
+func (d *SQLiteDialect) OrderByExpression(column string, descending bool, nulls string) string {
+	return nativeOrderByExpression(column, descending, nulls)
+}
+
+// IsLargeObjectType returns true for BLOB columns
+// (TEXT is the common string type in SQLite and is not treated as a LOB)
+func (d *SQLiteDialect) IsLargeObjectType(dataType string, maxLength int64) bool {
+	return strings.EqualFold(dataType, "BLOB")
+}
+
+// DateTrunc uses DATE modifiers (weeks start on Monday)
+func (d *SQLiteDialect) DateTrunc(column, unit string) string {
+	switch unit {
+	case "week":
+		return fmt.Sprintf("DATE(%s, '-6 days', 'weekday 1')", column)
+	case "month":
+		return fmt.Sprintf("DATE(%s, 'start of month')", column)
+	default:
+		return fmt.Sprintf("DATE(%s)", column)
+	}
+}
+
 // ConcatOperator returns || concatenation
 func (d *SQLiteDialect) ConcatOperator(parts ...string) string {
 	return strings.Join(parts, " || ")
@@ -47,6 +110,11 @@ func (d *SQLiteDialect) CurrentDatabase() string {
 	return "'main'"
 }
 
+// CurrentSchemaQuery returns 'main'
+func (d *SQLiteDialect) CurrentSchemaQuery() string {
+	return "SELECT 'main'"
+}
+
 // SystemSchemas returns empty (SQLite has no schemas)
 func (d *SQLiteDialect) SystemSchemas() []string {
 	return []string{}
@@ -73,12 +141,13 @@ func (d *SQLiteDialect) TableMetadata() TableMetadataSQL {
 			SELECT
 				'main' as table_schema,
 				name as table_name,
-				'BASE TABLE' as table_type
+				'BASE TABLE' as table_type,
+				NULL as description
 			FROM sqlite_master
 			WHERE type = 'table'
 				AND name NOT LIKE 'sqlite_%'`,
 		SchemaFilter: "", // SQLite doesn't have schemas
-		NameFilter:   " AND name LIKE ?",
+		NameFilter:   " AND name LIKE %s",
 		OrderBy:      " ORDER BY name",
 
 		DescribeTable: "PRAGMA table_info(%s)",
@@ -94,6 +163,11 @@ func (d *SQLiteDialect) TableMetadata() TableMetadataSQL {
 		GetIndexes: "PRAGMA index_list(%s)",
 
 		GetForeignKeys: "PRAGMA foreign_key_list(%s)",
+
+		// SQLite keeps no constraint catalog: both are parsed from the CREATE TABLE statement
+		GetCheckConstraints: `SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?`,
+
+		GetUniqueConstraints: `SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?`,
 	}
 }
 
@@ -115,11 +189,13 @@ func (d *SQLiteDialect) ViewMetadata() ViewMetadataSQL {
 				'main' as view_schema,
 				name as view_name,
 				NULL as created,
-				NULL as last_altered
+				NULL as last_altered,
+				'NO' as is_materialized,
+				NULL as last_refresh
 			FROM sqlite_master
 			WHERE type = 'view'`,
 		SchemaFilter: "", // SQLite doesn't have schemas
-		NameFilter:   " AND name LIKE ?",
+		NameFilter:   " AND name LIKE %s",
 		OrderBy:      " ORDER BY name",
 
 		GetDefinition: `
@@ -129,6 +205,16 @@ func (d *SQLiteDialect) ViewMetadata() ViewMetadataSQL {
 	}
 }
 
+// SequenceMetadata returns empty (SQLite doesn't have sequences)
+func (d *SQLiteDialect) SequenceMetadata() SequenceMetadataSQL {
+	return SequenceMetadataSQL{}
+}
+
+// TypeMetadata returns empty (SQLite doesn't have user-defined types)
+func (d *SQLiteDialect) TypeMetadata() TypeMetadataSQL {
+	return TypeMetadataSQL{}
+}
+
 // TriggerMetadata returns SQLite trigger metadata queries
 func (d *SQLiteDialect) TriggerMetadata() TriggerMetadataSQL {
 	return TriggerMetadataSQL{
@@ -143,8 +229,8 @@ func (d *SQLiteDialect) TriggerMetadata() TriggerMetadataSQL {
 			FROM sqlite_master
 			WHERE type = 'trigger'`,
 		SchemaFilter:   "", // SQLite doesn't have schemas
-		TableFilter:    " AND tbl_name = ?",
-		NameFilter:     " AND name LIKE ?",
+		TableFilter:    " AND tbl_name = %s",
+		NameFilter:     " AND name LIKE %s",
 		DisabledFilter: "", // SQLite doesn't have disabled triggers
 		OrderBy:        " ORDER BY tbl_name, name",
 
@@ -189,3 +275,15 @@ func (d *SQLiteDialect) DatabaseInfo() DatabaseInfoSQL {
 			ORDER BY name`,
 	}
 }
+
+// DependencyMetadata returns SQLite dependency queries: SQLite keeps no dependency
+// catalog, so the SQL of views and triggers is parsed
+func (d *SQLiteDialect) DependencyMetadata() DependencyMetadataSQL {
+	return DependencyMetadataSQL{
+		ListObjectSQL: `
+			SELECT type, name, tbl_name, sql
+			FROM sqlite_master
+			WHERE type IN ('table', 'view', 'trigger')
+			  AND name NOT LIKE 'sqlite_%'`,
+	}
+}
@@ -36,6 +36,42 @@ func (d *SQLServerDialect) PaginationClause(limit, offset int, orderBy string) s
 	return fmt.Sprintf("ORDER BY %s OFFSET %d ROWS FETCH NEXT %d ROWS ONLY", orderBy, offset, limit)
 }
 
+// LikeExpression forces a CS/CI collation so matching does not depend on the column collation
+func (d *SQLServerDialect) LikeExpression(column, placeholder string, caseSensitive bool) string {
+	if caseSensitive {
+		return fmt.Sprintf("%s COLLATE Latin1_General_CS_AS LIKE %s ESCAPE '\\'", column, placeholder)
+	}
+	return fmt.Sprintf("%s COLLATE Latin1_General_CI_AS LIKE %s ESCAPE '\\'", column, placeholder)
+}
+
+// LikePattern also keeps the escape of [, which starts a character class
+func (d *SQLServerDialect) LikePattern(pattern string, caseSensitive bool) string {
+	return rewriteLikeEscapes(pattern, "%_[")
+}
+
+// IsLargeObjectType returns true for text, ntext, image, xml and (n)varchar/varbinary(max) columns
+func (d *SQLServerDialect) IsLargeObjectType(dataType string, maxLength int64) bool {
+	switch strings.ToLower(dataType) {
+	case "text", "ntext", "image", "xml":
+		return true
+	case "varchar", "nvarchar", "varbinary":
+		return maxLength == -1
+	}
+	return false
+}
+
+// DateTrunc uses CAST/DATEADD (weeks start on Monday regardless of DATEFIRST)
+func (d *SQLServerDialect) DateTrunc(column, unit string) string {
+	switch unit {
+	case "week":
+		return fmt.Sprintf("DATEADD(day, -((DATEPART(weekday, %s) + @@DATEFIRST - 2) %% 7), CAST(%s AS DATE))", column, column)
+	case "month":
+		return fmt.Sprintf("DATEFROMPARTS(YEAR(%s), MONTH(%s), 1)", column, column)
+	default:
+		return fmt.Sprintf("CAST(%s AS DATE)", column)
+	}
+}
+
 // ConcatOperator returns + concatenation
 func (d *SQLServerDialect) ConcatOperator(parts ...string) string {
 	return strings.Join(parts, " + ")
@@ -46,6 +82,11 @@ func (d *SQLServerDialect) CurrentDatabase() string {
 	return "DB_NAME()"
 }
 
+// CurrentSchemaQuery returns SCHEMA_NAME()
+func (d *SQLServerDialect) CurrentSchemaQuery() string {
+	return "SELECT SCHEMA_NAME()"
+}
+
 // SystemSchemas returns SQL Server system schemas
 func (d *SQLServerDialect) SystemSchemas() []string {
 	return []string{"sys", "INFORMATION_SCHEMA"}
@@ -58,7 +99,10 @@ func (d *SQLServerDialect) TableMetadata() TableMetadataSQL {
 			SELECT
 				TABLE_SCHEMA,
 				TABLE_NAME,
-				TABLE_TYPE
+				TABLE_TYPE,
+				(SELECT CAST(ep.value AS nvarchar(4000)) FROM sys.extended_properties ep
+					WHERE ep.class = 1 AND ep.minor_id = 0 AND ep.name = 'MS_Description'
+						AND ep.major_id = OBJECT_ID(QUOTENAME(TABLE_SCHEMA) + '.' + QUOTENAME(TABLE_NAME))) AS description
 			FROM INFORMATION_SCHEMA.TABLES
 			WHERE TABLE_TYPE = 'BASE TABLE'`,
 		SchemaFilter: " AND TABLE_SCHEMA = %s",
@@ -71,7 +115,11 @@ func (d *SQLServerDialect) TableMetadata() TableMetadataSQL {
 				DATA_TYPE,
 				IS_NULLABLE,
 				COLUMN_DEFAULT,
-				CHARACTER_MAXIMUM_LENGTH
+				CHARACTER_MAXIMUM_LENGTH,
+				(SELECT CAST(ep.value AS nvarchar(4000)) FROM sys.extended_properties ep
+					WHERE ep.class = 1 AND ep.name = 'MS_Description'
+						AND ep.major_id = OBJECT_ID(QUOTENAME(TABLE_SCHEMA) + '.' + QUOTENAME(TABLE_NAME))
+						AND ep.minor_id = COLUMNPROPERTY(ep.major_id, COLUMN_NAME, 'ColumnId')) AS description
 			FROM INFORMATION_SCHEMA.COLUMNS
 			WHERE TABLE_SCHEMA = @p1 AND TABLE_NAME = @p2
 			ORDER BY ORDINAL_POSITION`,
@@ -98,7 +146,11 @@ func (d *SQLServerDialect) TableMetadata() TableMetadataSQL {
 				c.NUMERIC_SCALE,
 				c.IS_NULLABLE,
 				c.COLUMN_DEFAULT,
-				CASE WHEN pk.COLUMN_NAME IS NOT NULL THEN 'YES' ELSE 'NO' END AS IS_PRIMARY_KEY
+				CASE WHEN pk.COLUMN_NAME IS NOT NULL THEN 'YES' ELSE 'NO' END AS IS_PRIMARY_KEY,
+				(SELECT CAST(ep.value AS nvarchar(4000)) FROM sys.extended_properties ep
+					WHERE ep.class = 1 AND ep.name = 'MS_Description'
+						AND ep.major_id = OBJECT_ID(QUOTENAME(c.TABLE_SCHEMA) + '.' + QUOTENAME(c.TABLE_NAME))
+						AND ep.minor_id = COLUMNPROPERTY(ep.major_id, c.COLUMN_NAME, 'ColumnId')) AS description
 			FROM INFORMATION_SCHEMA.COLUMNS c
 			LEFT JOIN (
 				SELECT ku.TABLE_SCHEMA, ku.TABLE_NAME, ku.COLUMN_NAME
@@ -137,6 +189,7 @@ func (d *SQLServerDialect) TableMetadata() TableMetadataSQL {
 			INNER JOIN sys.tables t ON i.object_id = t.object_id
 			INNER JOIN sys.schemas s ON t.schema_id = s.schema_id
 			WHERE s.name = @p1 AND t.name = @p2
+				AND ic.is_included_column = 0
 			ORDER BY i.name, ic.key_ordinal`,
 
 		GetForeignKeys: `
@@ -145,14 +198,68 @@ func (d *SQLServerDialect) TableMetadata() TableMetadataSQL {
 				COL_NAME(fkc.parent_object_id, fkc.parent_column_id) AS column_name,
 				SCHEMA_NAME(ref_t.schema_id) AS referenced_schema,
 				ref_t.name AS referenced_table,
-				COL_NAME(fkc.referenced_object_id, fkc.referenced_column_id) AS referenced_column
+				COL_NAME(fkc.referenced_object_id, fkc.referenced_column_id) AS referenced_column,
+				REPLACE(fk.delete_referential_action_desc, '_', ' ') AS on_delete,
+				REPLACE(fk.update_referential_action_desc, '_', ' ') AS on_update,
+				'SIMPLE' AS match_option,
+				'NO' AS is_deferrable,
+				'NO' AS initially_deferred
 			FROM sys.foreign_keys fk
 			INNER JOIN sys.foreign_key_columns fkc ON fk.object_id = fkc.constraint_object_id
 			INNER JOIN sys.tables t ON fk.parent_object_id = t.object_id
 			INNER JOIN sys.schemas s ON t.schema_id = s.schema_id
 			INNER JOIN sys.tables ref_t ON fkc.referenced_object_id = ref_t.object_id
 			WHERE s.name = @p1 AND t.name = @p2
-			ORDER BY fk.name`,
+			ORDER BY fk.name, fkc.constraint_column_id`,
+
+		GetCheckConstraints: `
+			SELECT
+				cc.name AS constraint_name,
+				col.name AS column_name,
+				cc.definition,
+				NULL AS domain_name
+			FROM sys.check_constraints cc
+			INNER JOIN sys.tables t ON cc.parent_object_id = t.object_id
+			INNER JOIN sys.schemas s ON t.schema_id = s.schema_id
+			LEFT JOIN sys.columns col ON col.object_id = cc.parent_object_id
+				AND col.column_id = cc.parent_column_id
+			WHERE s.name = @p1 AND t.name = @p2
+			ORDER BY cc.name`,
+
+		GetUniqueConstraints: `
+			SELECT
+				kc.name AS constraint_name,
+				col.name AS column_name
+			FROM sys.key_constraints kc
+			INNER JOIN sys.tables t ON kc.parent_object_id = t.object_id
+			INNER JOIN sys.schemas s ON t.schema_id = s.schema_id
+			INNER JOIN sys.index_columns ic ON ic.object_id = kc.parent_object_id
+				AND ic.index_id = kc.unique_index_id
+			INNER JOIN sys.columns col ON col.object_id = ic.object_id
+				AND col.column_id = ic.column_id
+			WHERE kc.type = 'UQ' AND s.name = @p1 AND t.name = @p2
+			ORDER BY kc.name, ic.key_ordinal`,
+
+		GetDefaultConstraints: `
+			SELECT
+				dc.name AS constraint_name,
+				col.name AS column_name,
+				dc.definition
+			FROM sys.default_constraints dc
+			INNER JOIN sys.tables t ON dc.parent_object_id = t.object_id
+			INNER JOIN sys.schemas s ON t.schema_id = s.schema_id
+			INNER JOIN sys.columns col ON col.object_id = dc.parent_object_id
+				AND col.column_id = dc.parent_column_id
+			WHERE s.name = @p1 AND t.name = @p2
+			ORDER BY col.column_id`,
+
+		GetTableComment: `
+			SELECT CAST(ep.value AS nvarchar(4000))
+			FROM sys.extended_properties ep
+			JOIN sys.tables t ON t.object_id = ep.major_id
+			JOIN sys.schemas s ON s.schema_id = t.schema_id
+			WHERE ep.class = 1 AND ep.minor_id = 0 AND ep.name = 'MS_Description'
+				AND s.name = @p1 AND t.name = @p2`,
 	}
 }
 
@@ -164,7 +271,9 @@ func (d *SQLServerDialect) ProcedureMetadata() ProcedureMetadataSQL {
 				s.name AS routine_schema,
 				o.name AS routine_name,
 				o.create_date AS created,
-				o.modify_date AS last_altered
+				o.modify_date AS last_altered,
+				NULL AS package_name,
+				CAST(o.object_id AS nvarchar(20)) AS specific_name
 			FROM sys.objects o
 			INNER JOIN sys.schemas s ON o.schema_id = s.schema_id
 			WHERE o.type = 'P' AND o.is_ms_shipped = 0`,
@@ -178,6 +287,9 @@ func (d *SQLServerDialect) ProcedureMetadata() ProcedureMetadataSQL {
 			INNER JOIN sys.objects o ON m.object_id = o.object_id
 			INNER JOIN sys.schemas s ON o.schema_id = s.schema_id
 			WHERE o.type = 'P' AND s.name = @p1 AND o.name = @p2`,
+
+		GetArguments:  sqlServerRoutineArguments("'P'", " AND o.name = @p2"),
+		ListArguments: sqlServerRoutineArguments("'P'", ""),
 	}
 }
 
@@ -190,7 +302,9 @@ func (d *SQLServerDialect) FunctionMetadata() FunctionMetadataSQL {
 				o.name AS routine_name,
 				o.type_desc AS function_type,
 				o.create_date AS created,
-				o.modify_date AS last_altered
+				o.modify_date AS last_altered,
+				NULL AS package_name,
+				CAST(o.object_id AS nvarchar(20)) AS specific_name
 			FROM sys.objects o
 			INNER JOIN sys.schemas s ON o.schema_id = s.schema_id
 			WHERE o.is_ms_shipped = 0`,
@@ -207,9 +321,44 @@ func (d *SQLServerDialect) FunctionMetadata() FunctionMetadataSQL {
 			INNER JOIN sys.objects o ON m.object_id = o.object_id
 			INNER JOIN sys.schemas s ON o.schema_id = s.schema_id
 			WHERE o.type IN ('FN', 'IF', 'TF') AND s.name = @p1 AND o.name = @p2`,
+
+		GetArguments:  sqlServerRoutineArguments("'FN', 'IF', 'TF'", " AND o.name = @p2"),
+		ListArguments: sqlServerRoutineArguments("'FN', 'IF', 'TF'", ""),
 	}
 }
 
+// sqlServerRoutineArguments returns the parameters of the routines of one of objectTypes
+// in a schema, of one routine with nameFilter; the return value of a scalar function is its parameter 0. SQL Server only keeps
+// whether a parameter has a default for CLR routines.
+func sqlServerRoutineArguments(objectTypes, nameFilter string) string {
+	return fmt.Sprintf(`
+			SELECT
+				o.name AS routine_name,
+				NULL AS package_name,
+				CAST(o.object_id AS nvarchar(20)) AS specific_name,
+				p.parameter_id AS position,
+				NULLIF(p.name, '') AS argument_name,
+				CASE WHEN p.parameter_id = 0 THEN 'OUT' WHEN p.is_output = 1 THEN 'INOUT' ELSE 'IN' END AS mode,
+				TYPE_NAME(p.user_type_id)
+					+ CASE
+						WHEN TYPE_NAME(p.user_type_id) IN ('varchar', 'char', 'varbinary', 'binary')
+							THEN '(' + CASE WHEN p.max_length = -1 THEN 'max' ELSE CAST(p.max_length AS varchar(10)) END + ')'
+						WHEN TYPE_NAME(p.user_type_id) IN ('nvarchar', 'nchar')
+							THEN '(' + CASE WHEN p.max_length = -1 THEN 'max' ELSE CAST(p.max_length / 2 AS varchar(10)) END + ')'
+						WHEN TYPE_NAME(p.user_type_id) IN ('decimal', 'numeric')
+							THEN '(' + CAST(p.precision AS varchar(10)) + ', ' + CAST(p.scale AS varchar(10)) + ')'
+						ELSE ''
+					END AS data_type,
+				NULL AS default_value,
+				CASE WHEN p.has_default_value = 1 THEN 'YES' ELSE 'NO' END AS has_default,
+				CASE WHEN o.type IN ('IF', 'TF') THEN 'TABLE' END AS return_type
+			FROM sys.objects o
+			INNER JOIN sys.schemas s ON o.schema_id = s.schema_id
+			LEFT JOIN sys.parameters p ON p.object_id = o.object_id
+			WHERE o.type IN (%s) AND s.name = @p1%s
+			ORDER BY o.name, o.object_id, p.parameter_id`, objectTypes, nameFilter)
+}
+
 // ViewMetadata returns SQL Server view metadata queries
 func (d *SQLServerDialect) ViewMetadata() ViewMetadataSQL {
 	return ViewMetadataSQL{
@@ -218,7 +367,10 @@ func (d *SQLServerDialect) ViewMetadata() ViewMetadataSQL {
 				s.name AS view_schema,
 				v.name AS view_name,
 				v.create_date AS created,
-				v.modify_date AS last_altered
+				v.modify_date AS last_altered,
+				CASE WHEN EXISTS (SELECT 1 FROM sys.indexes i WHERE i.object_id = v.object_id AND i.index_id = 1)
+					THEN 'YES' ELSE 'NO' END AS is_materialized,
+				NULL AS last_refresh
 			FROM sys.views v
 			INNER JOIN sys.schemas s ON v.schema_id = s.schema_id
 			WHERE v.is_ms_shipped = 0`,
@@ -232,6 +384,79 @@ func (d *SQLServerDialect) ViewMetadata() ViewMetadataSQL {
 			INNER JOIN sys.views v ON m.object_id = v.object_id
 			INNER JOIN sys.schemas s ON v.schema_id = s.schema_id
 			WHERE s.name = @p1 AND v.name = @p2`,
+
+		// Indexed views (with a clustered index) are kept up to date by the engine
+		GetMaterializedView: `
+			SELECT m.definition, NULL
+			FROM sys.sql_modules m
+			INNER JOIN sys.views v ON m.object_id = v.object_id
+			INNER JOIN sys.schemas s ON v.schema_id = s.schema_id
+			WHERE s.name = @p1 AND v.name = @p2
+				AND EXISTS (SELECT 1 FROM sys.indexes i WHERE i.object_id = v.object_id AND i.index_id = 1)`,
+	}
+}
+
+// SequenceMetadata returns SQL Server sequence metadata queries
+func (d *SQLServerDialect) SequenceMetadata() SequenceMetadataSQL {
+	return SequenceMetadataSQL{
+		ListSequences: `
+			SELECT
+				s.name AS sequence_schema,
+				seq.name AS sequence_name,
+				TYPE_NAME(seq.user_type_id) AS data_type,
+				CAST(seq.start_value AS nvarchar(50)) AS start_value,
+				CAST(seq.minimum_value AS nvarchar(50)) AS min_value,
+				CAST(seq.maximum_value AS nvarchar(50)) AS max_value,
+				CAST(seq.increment AS nvarchar(50)) AS increment,
+				CASE WHEN seq.is_cycling = 1 THEN 'YES' ELSE 'NO' END AS is_cycle,
+				seq.cache_size,
+				CAST(seq.current_value AS nvarchar(50)) AS last_value
+			FROM sys.sequences seq
+			INNER JOIN sys.schemas s ON seq.schema_id = s.schema_id
+			WHERE 1=1`,
+		SchemaFilter: " AND s.name = %s",
+		NameFilter:   " AND seq.name LIKE %s",
+		OrderBy:      " ORDER BY s.name, seq.name",
+	}
+}
+
+// TypeMetadata returns SQL Server type metadata queries: alias, table and CLR types
+func (d *SQLServerDialect) TypeMetadata() TypeMetadataSQL {
+	return TypeMetadataSQL{
+		ListTypes: `
+			SELECT
+				s.name AS type_schema,
+				t.name AS type_name,
+				CASE WHEN t.is_table_type = 1 THEN 'table type' WHEN t.is_assembly_type = 1 THEN 'clr' ELSE 'alias' END AS kind,
+				CASE WHEN t.is_table_type = 0 AND t.is_assembly_type = 0 THEN TYPE_NAME(t.system_type_id)
+					+ CASE
+						WHEN TYPE_NAME(t.system_type_id) IN ('varchar', 'char', 'varbinary', 'binary')
+							THEN '(' + CASE WHEN t.max_length = -1 THEN 'max' ELSE CAST(t.max_length AS varchar(10)) END + ')'
+						WHEN TYPE_NAME(t.system_type_id) IN ('nvarchar', 'nchar')
+							THEN '(' + CASE WHEN t.max_length = -1 THEN 'max' ELSE CAST(t.max_length / 2 AS varchar(10)) END + ')'
+						WHEN TYPE_NAME(t.system_type_id) IN ('decimal', 'numeric')
+							THEN '(' + CAST(t.precision AS varchar(10)) + ', ' + CAST(t.scale AS varchar(10)) + ')'
+						ELSE ''
+					END
+				END AS base_type,
+				CAST(ep.value AS nvarchar(4000)) AS description,
+				NULL AS table_name
+			FROM sys.types t
+			INNER JOIN sys.schemas s ON t.schema_id = s.schema_id
+			LEFT JOIN sys.extended_properties ep
+				ON ep.class = 6 AND ep.major_id = t.user_type_id AND ep.minor_id = 0 AND ep.name = 'MS_Description'
+			WHERE t.is_user_defined = 1`,
+		SchemaFilter: " AND s.name = %s",
+		NameFilter:   " AND t.name LIKE %s",
+		OrderBy:      " ORDER BY s.name, t.name",
+
+		GetAttributes: `
+			SELECT c.name, TYPE_NAME(c.user_type_id)
+			FROM sys.table_types tt
+			INNER JOIN sys.schemas s ON tt.schema_id = s.schema_id
+			INNER JOIN sys.columns c ON c.object_id = tt.type_table_object_id
+			WHERE s.name = @p1 AND tt.name = @p2
+			ORDER BY c.column_id`,
 	}
 }
 
@@ -313,3 +538,52 @@ func (d *SQLServerDialect) DatabaseInfo() DatabaseInfoSQL {
 			ORDER BY s.name, o.name`,
 	}
 }
+
+// DependencyMetadata returns SQL Server dependency queries
+func (d *SQLServerDialect) DependencyMetadata() DependencyMetadataSQL {
+	return DependencyMetadataSQL{
+		ListDependencies: `
+			SELECT
+				OBJECT_SCHEMA_NAME(d.referencing_id) AS schema_name,
+				o.name AS object_name,
+				CASE
+					WHEN o.type = 'V' THEN 'view'
+					WHEN o.type = 'P' THEN 'procedure'
+					WHEN o.type = 'TR' THEN 'trigger'
+					WHEN o.type IN ('FN', 'IF', 'TF') THEN 'function'
+					ELSE LOWER(o.type_desc)
+				END AS object_type,
+				COALESCE(d.referenced_schema_name, OBJECT_SCHEMA_NAME(d.referenced_id), OBJECT_SCHEMA_NAME(d.referencing_id)) AS referenced_schema,
+				d.referenced_entity_name AS referenced_name,
+				CASE
+					WHEN ro.object_id IS NULL THEN 'unresolved'
+					WHEN ro.type = 'U' THEN 'table'
+					WHEN ro.type = 'V' THEN 'view'
+					WHEN ro.type = 'P' THEN 'procedure'
+					WHEN ro.type = 'TR' THEN 'trigger'
+					WHEN ro.type IN ('FN', 'IF', 'TF') THEN 'function'
+					WHEN ro.type = 'SN' THEN 'synonym'
+					WHEN ro.type = 'SO' THEN 'sequence'
+					ELSE LOWER(ro.type_desc)
+				END AS referenced_type
+			FROM sys.sql_expression_dependencies d
+			INNER JOIN sys.objects o ON o.object_id = d.referencing_id
+			LEFT JOIN sys.objects ro ON ro.object_id = d.referenced_id
+			WHERE d.referencing_class = 1
+			  AND d.referenced_class = 1
+			  AND d.referenced_server_name IS NULL
+			  AND d.referenced_database_name IS NULL
+			  AND d.referencing_id <> COALESCE(d.referenced_id, 0)
+			UNION
+			SELECT
+				OBJECT_SCHEMA_NAME(t.object_id),
+				t.name,
+				'trigger',
+				OBJECT_SCHEMA_NAME(t.parent_id),
+				p.name,
+				CASE WHEN p.type = 'V' THEN 'view' ELSE 'table' END
+			FROM sys.triggers t
+			INNER JOIN sys.objects p ON p.object_id = t.parent_id
+			WHERE t.parent_class = 1`,
+	}
+}
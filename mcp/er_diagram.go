@@ -0,0 +1,203 @@
+package mcp
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// EREntity is a table of an ER diagram
+type EREntity struct {
+	Name    string     `json:"name"`
+	Columns []ERColumn `json:"-"`
+}
+
+// ERColumn is a column of an ER diagram entity
+type ERColumn struct {
+	Name       string
+	Type       string
+	Nullable   bool
+	PrimaryKey bool
+	ForeignKey bool
+}
+
+// ERRelationship is a foreign key between two entities of an ER diagram. Table
+// references RefTable; Optional means the foreign key columns are nullable and
+// Unique that each referenced row has at most one referencing row.
+type ERRelationship struct {
+	Name        string   `json:"name"`
+	Table       string   `json:"table"`
+	Columns     []string `json:"columns"`
+	RefTable    string   `json:"referenced_table"`
+	RefColumns  []string `json:"referenced_columns"`
+	Cardinality string   `json:"cardinality"`
+	Optional    bool     `json:"optional"`
+	Unique      bool     `json:"-"`
+}
+
+// ERDiagram is the set of entities and relationships rendered by export_er_diagram
+type ERDiagram struct {
+	Entities      []*EREntity
+	Relationships []*ERRelationship
+}
+
+// erTypeSanitizer replaces what Mermaid does not accept in an attribute type
+var erTypeSanitizer = regexp.MustCompile(`[^A-Za-z0-9_()\[\]-]+`)
+
+// cardinality describes the relationship from the referencing side
+func (r *ERRelationship) cardinality() string {
+	if r.Unique {
+		return "one-to-one"
+	}
+	return "many-to-one"
+}
+
+// crowsFoot returns the crow's foot notation of the relationship, referenced table
+// on the left, as used by Mermaid and PlantUML
+func (r *ERRelationship) crowsFoot() string {
+	left := "||"
+	if r.Optional {
+		left = "|o"
+	}
+	right := "o{"
+	if r.Unique {
+		right = "o|"
+	}
+	return left + "--" + right
+}
+
+// Render returns the diagram in the given format
+func (d *ERDiagram) Render(format string) (string, error) {
+	switch format {
+	case ERFormatMermaid:
+		return d.renderMermaid(), nil
+	case ERFormatPlantUML:
+		return d.renderPlantUML(), nil
+	case ERFormatDOT:
+		return d.renderDOT(), nil
+	default:
+		return "", fmt.Errorf("%w: format must be %s, %s or %s", ErrInvalidArguments, ERFormatMermaid, ERFormatPlantUML, ERFormatDOT)
+	}
+}
+
+func (d *ERDiagram) renderMermaid() string {
+	var b strings.Builder
+	b.WriteString("erDiagram\n")
+	for _, entity := range d.Entities {
+		fmt.Fprintf(&b, "    %s {\n", entity.Name)
+		for _, col := range entity.Columns {
+			typ := strings.Trim(erTypeSanitizer.ReplaceAllString(col.Type, "_"), "_")
+			if typ == "" {
+				typ = "unknown"
+			}
+			fmt.Fprintf(&b, "        %s %s", typ, col.Name)
+			var keys []string
+			if col.PrimaryKey {
+				keys = append(keys, "PK")
+			}
+			if col.ForeignKey {
+				keys = append(keys, "FK")
+			}
+			if len(keys) > 0 {
+				b.WriteString(" " + strings.Join(keys, ", "))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("    }\n")
+	}
+	for _, r := range d.Relationships {
+		fmt.Fprintf(&b, "    %s %s %s : %q\n", r.RefTable, r.crowsFoot(), r.Table, r.Name)
+	}
+	return b.String()
+}
+
+func (d *ERDiagram) renderPlantUML() string {
+	var b strings.Builder
+	b.WriteString("@startuml\nhide circle\nskinparam linetype ortho\n\n")
+	for _, entity := range d.Entities {
+		fmt.Fprintf(&b, "entity \"%s\" as %s {\n", entity.Name, entity.Name)
+		keys, others := splitKeyColumns(entity.Columns)
+		for _, col := range keys {
+			b.WriteString("  " + plantUMLColumn(col) + "\n")
+		}
+		if len(keys) > 0 && len(others) > 0 {
+			b.WriteString("  --\n")
+		}
+		for _, col := range others {
+			b.WriteString("  " + plantUMLColumn(col) + "\n")
+		}
+		b.WriteString("}\n\n")
+	}
+	for _, r := range d.Relationships {
+		fmt.Fprintf(&b, "%s %s %s : %s\n", r.RefTable, r.crowsFoot(), r.Table, r.Name)
+	}
+	b.WriteString("@enduml\n")
+	return b.String()
+}
+
+func (d *ERDiagram) renderDOT() string {
+	var b strings.Builder
+	b.WriteString("digraph er {\n    rankdir=LR;\n    node [shape=record, fontname=\"Helvetica\"];\n    edge [dir=both, fontname=\"Helvetica\", fontsize=10];\n\n")
+	for _, entity := range d.Entities {
+		var fields []string
+		for _, col := range entity.Columns {
+			field := col.Name + " : " + col.Type
+			if col.PrimaryKey {
+				field += " PK"
+			}
+			if col.ForeignKey {
+				field += " FK"
+			}
+			fields = append(fields, dotRecordEscape(field)+`\l`)
+		}
+		fmt.Fprintf(&b, "    %q [label=\"{%s|%s}\"];\n", entity.Name, dotRecordEscape(entity.Name), strings.Join(fields, ""))
+	}
+	b.WriteString("\n")
+	for _, r := range d.Relationships {
+		// The arrow points at the referenced table; its tail shows how many rows reference it
+		head := "teetee"
+		if r.Optional {
+			head = "teeodot"
+		}
+		tail := "crowodot"
+		if r.Unique {
+			tail = "teeodot"
+		}
+		fmt.Fprintf(&b, "    %q -> %q [label=%q, arrowhead=%s, arrowtail=%s];\n", r.Table, r.RefTable, r.Name, head, tail)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// splitKeyColumns separates the primary key columns from the others
+func splitKeyColumns(columns []ERColumn) ([]ERColumn, []ERColumn) {
+	var keys, others []ERColumn
+	for _, col := range columns {
+		if col.PrimaryKey {
+			keys = append(keys, col)
+		} else {
+			others = append(others, col)
+		}
+	}
+	return keys, others
+}
+
+// plantUMLColumn renders a column, marking mandatory columns with *
+func plantUMLColumn(col ERColumn) string {
+	line := col.Name + " : " + col.Type
+	if !col.Nullable {
+		line = "* " + line
+	}
+	if col.PrimaryKey {
+		line += " <<PK>>"
+	}
+	if col.ForeignKey {
+		line += " <<FK>>"
+	}
+	return line
+}
+
+// dotRecordEscape escapes the characters with a meaning in DOT record labels
+func dotRecordEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "{", `\{`, "}", `\}`, "|", `\|`, "<", `\<`, ">", `\>`).Replace(s)
+}
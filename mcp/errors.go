@@ -31,6 +31,7 @@ var (
 	ErrQueryRequired      = errors.New("query is required")
 	ErrReadingRow         = errors.New("error reading row")
 	ErrReadingResults     = errors.New("error reading results")
+	ErrExecutingQuery     = errors.New("error executing query")
 )
 
 // Query validation errors
@@ -61,12 +62,15 @@ var (
 	ErrFunctionNotFound  = errors.New("function not found")
 	ErrTriggerNotFound   = errors.New("trigger not found")
 	ErrObjectNotFound    = errors.New("object not found")
+	ErrOverloadNotFound  = errors.New("overload not found")
 )
 
 // Feature support errors
 var (
 	ErrStoredProceduresNotSupported = errors.New("stored procedures are not supported by this database")
 	ErrFunctionsNotSupported        = errors.New("functions are not supported by this database")
+	ErrSequencesNotSupported        = errors.New("sequences are not supported by this database")
+	ErrTypesNotSupported            = errors.New("user-defined types are not supported by this database")
 	ErrFeatureNotSupported          = errors.New("feature not supported by this database")
 )
 
@@ -78,6 +82,7 @@ var (
 	ErrInvalidProcedureName = errors.New("invalid procedure name")
 	ErrInvalidFunctionName  = errors.New("invalid function name")
 	ErrInvalidTriggerName   = errors.New("invalid trigger name")
+	ErrInvalidPackageName   = errors.New("invalid package name")
 	ErrInvalidSchemaName    = errors.New("invalid schema name")
 	ErrInvalidColumnName    = errors.New("invalid column name")
 	ErrInvalidOperator      = errors.New("invalid operator")
@@ -90,6 +95,8 @@ var (
 	ErrDefinitionNotAvailable = errors.New("definition not available")
 	ErrNoColumnsFound         = errors.New("no columns found in the table")
 	ErrColumnNotExists        = errors.New("column does not exist")
+	ErrNoColumnsSelected      = errors.New("no columns selected")
+	ErrInvalidOrderBy         = errors.New("invalid order_by")
 )
 
 // Serialization errors
@@ -99,21 +106,26 @@ var (
 
 // Operation errors
 var (
-	ErrListingTables      = errors.New("error listing tables")
-	ErrListingViews       = errors.New("error listing views")
-	ErrListingProcedures  = errors.New("error listing procedures")
-	ErrListingFunctions   = errors.New("error listing functions")
-	ErrListingTriggers    = errors.New("error listing triggers")
-	ErrDescribingTable    = errors.New("error describing table")
-	ErrCheckingTable      = errors.New("error checking table")
-	ErrRetrievingColumns  = errors.New("error retrieving columns")
-	ErrCountingRows       = errors.New("error counting rows")
-	ErrFetchingRows       = errors.New("error fetching rows")
-	ErrSearchingObjects   = errors.New("error searching objects")
-	ErrFetchingCode       = errors.New("error fetching code")
-	ErrExecutingProcedure = errors.New("error executing procedure")
-	ErrRetrievingView     = errors.New("error retrieving view definition")
-	ErrRetrievingTrigger  = errors.New("error retrieving trigger code")
+	ErrListingTables         = errors.New("error listing tables")
+	ErrListingViews          = errors.New("error listing views")
+	ErrListingProcedures     = errors.New("error listing procedures")
+	ErrListingFunctions      = errors.New("error listing functions")
+	ErrListingTriggers       = errors.New("error listing triggers")
+	ErrListingSequences      = errors.New("error listing sequences")
+	ErrListingTypes          = errors.New("error listing types")
+	ErrDescribingTable       = errors.New("error describing table")
+	ErrCheckingTable         = errors.New("error checking table")
+	ErrRetrievingColumns     = errors.New("error retrieving columns")
+	ErrCountingRows          = errors.New("error counting rows")
+	ErrFetchingRows          = errors.New("error fetching rows")
+	ErrSearchingObjects      = errors.New("error searching objects")
+	ErrFetchingCode          = errors.New("error fetching code")
+	ErrExecutingProcedure    = errors.New("error executing procedure")
+	ErrRetrievingView        = errors.New("error retrieving view definition")
+	ErrRetrievingTrigger     = errors.New("error retrieving trigger code")
+	ErrAggregatingRows       = errors.New("error aggregating rows")
+	ErrRetrievingForeignKeys = errors.New("error retrieving foreign keys")
+	ErrRetrievingConstraints = errors.New("error retrieving constraints")
 )
 
 // Filter errors
@@ -121,4 +133,135 @@ var (
 	ErrContainsRequiresString   = errors.New("'contains' operator requires a string value")
 	ErrStartsWithRequiresString = errors.New("'starts_with' operator requires a string value")
 	ErrEndsWithRequiresString   = errors.New("'ends_with' operator requires a string value")
+	ErrPatternRequiresString    = errors.New("pattern operator requires a string value")
+	ErrInRequiresArray          = errors.New("operator requires a non-empty array value")
+	ErrBetweenRequiresTwoValues = errors.New("operator requires an array with exactly two values")
+	ErrInvalidFilter            = errors.New("invalid filter")
+	ErrFilterTooDeep            = errors.New("filter nesting too deep")
+	ErrTooManyFilterConditions  = errors.New("too many filter conditions")
+	ErrTooManyInValues          = errors.New("too many values in IN list")
+)
+
+// Aggregation errors
+var (
+	ErrAggregateRequired        = errors.New("at least one group_by column or aggregate is required")
+	ErrInvalidGroupBy           = errors.New("invalid group_by")
+	ErrInvalidAggregate         = errors.New("invalid aggregate")
+	ErrInvalidAggregateFunction = errors.New("invalid aggregate function - use: count, count_distinct, sum, avg, min or max")
+	ErrInvalidDateBucket        = errors.New("invalid date bucket - use: day, week or month")
+	ErrInvalidAlias             = errors.New("invalid alias")
+	ErrDuplicateAlias           = errors.New("duplicate output column")
+	ErrTooManyAggregateTerms    = errors.New("too many group_by columns or aggregates")
+)
+
+// Query builder errors
+var (
+	ErrInvalidJoin           = errors.New("invalid join")
+	ErrTooManyJoins          = errors.New("too many joins")
+	ErrDuplicateTableAlias   = errors.New("duplicate table alias")
+	ErrNoForeignKeyFound     = errors.New("no foreign key found to join table")
+	ErrAmbiguousForeignKey   = errors.New("more than one foreign key can join table")
+	ErrAmbiguousColumn       = errors.New("ambiguous column")
+	ErrColumnsWithAggregates = errors.New("'columns' cannot be combined with group_by or aggregates")
+)
+
+// Explain errors
+var (
+	ErrExplainingQuery     = errors.New("error explaining query")
+	ErrEmptyPlan           = errors.New("the database returned an empty plan")
+	ErrAnalyzeNotSupported = errors.New("'analyze' is only supported on PostgreSQL")
+)
+
+// Configuration errors
+var (
+	ErrReadingConfig = errors.New("error reading configuration file")
+	ErrInvalidConfig = errors.New("invalid configuration file")
+)
+
+// Query guard errors
+var (
+	ErrQueryTooExpensive = errors.New("query rejected by the cost guard")
+	ErrQueryCostUnknown  = errors.New("query rejected by the cost guard: its plan could not be estimated")
+)
+
+// Policy errors
+var (
+	ErrInvalidPolicyRule   = errors.New("invalid policy rule")
+	ErrObjectNotAllowed    = errors.New("access denied by the datasource policy")
+	ErrCheckingPolicy      = errors.New("could not check the query against the datasource policy")
+	ErrUnresolvedReference = errors.New("query references a name the datasource policy cannot resolve")
+)
+
+// Masking errors
+var (
+	ErrInvalidMaskingRule  = errors.New("invalid masking rule")
+	ErrInvalidMaskStrategy = errors.New("invalid masking strategy - use: redact, partial, hash or null")
+)
+
+// Audit errors
+var (
+	ErrOpeningAuditLog    = errors.New("error opening audit log")
+	ErrAuditEntryModified = errors.New("hash mismatch, the entry was modified")
+	ErrAuditEntryUnsigned = errors.New("entry has no hash")
+	ErrAuditKeyMissing    = errors.New("the audit log needs a hash key, set audit.hash_key or DB_MCP_AUDIT_KEY")
+)
+
+// Limit errors
+var (
+	ErrRateLimited              = errors.New("rate limit exceeded")
+	ErrTooManyConcurrentQueries = errors.New("too many concurrent queries")
+	ErrQuotaExceeded            = errors.New("daily quota exceeded")
+)
+
+// Secret errors
+var (
+	ErrSecretNotFound   = errors.New("secret reference could not be resolved")
+	ErrSecretNotAllowed = errors.New("secret reference not allowed by the configuration")
+)
+
+// Connection policy errors
+var (
+	ErrAdHocDataSourceDisabled = errors.New("ad-hoc connection strings are disabled, connect to a declared datasource by name")
+	ErrHostNotAllowed          = errors.New("host not allowed by the connection policy")
+	ErrPortNotAllowed          = errors.New("port not allowed by the connection policy")
+	ErrPathNotAllowed          = errors.New("database file not allowed by the connection policy")
+	ErrDataSourceNameReserved  = errors.New("the name belongs to a configured datasource, choose another name for the connection string")
+)
+
+// Query history errors
+var (
+	ErrOpeningHistory           = errors.New("error opening query history")
+	ErrHistoryDisabled          = errors.New("query history is disabled")
+	ErrHistoryEntryNotFound     = errors.New("query history entry not found")
+	ErrHistoryDatasourceChanged = errors.New("query history entry was run on another datasource")
+	ErrHistoryEntryRedacted     = errors.New("query history entry has masked arguments and cannot be rerun")
+)
+
+// Saved query errors
+var (
+	ErrReadingSavedQueries        = errors.New("error reading saved queries")
+	ErrInvalidSavedQuery          = errors.New("invalid saved query")
+	ErrInvalidSavedQueryParameter = errors.New("invalid saved query parameter")
+	ErrSavedQueryDatasource       = errors.New("saved query belongs to another datasource")
+)
+
+// DDL errors
+var (
+	ErrGeneratingDDL = errors.New("error generating DDL")
+)
+
+// Schema diff and snapshot errors
+var (
+	ErrDataSourceNotDeclared = errors.New("datasource is not declared in the configuration")
+	ErrSnapshotsDisabled     = errors.New("schema snapshots are not configured")
+	ErrInvalidSnapshotName   = errors.New("invalid snapshot name")
+	ErrSnapshotNotFound      = errors.New("snapshot not found")
+	ErrReadingSnapshot       = errors.New("error reading snapshot")
+	ErrWritingSnapshot       = errors.New("error writing snapshot")
+	ErrListingSnapshots      = errors.New("error listing snapshots")
+)
+
+// Dependency errors
+var (
+	ErrListingDependencies = errors.New("error listing dependencies")
 )
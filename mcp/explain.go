@@ -0,0 +1,467 @@
+package mcp
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PlanNode is an operator of a normalized execution plan
+type PlanNode struct {
+	Operator      string      `json:"operator"`
+	Object        string      `json:"object,omitempty"`
+	Index         string      `json:"index,omitempty"`
+	EstimatedRows float64     `json:"estimated_rows,omitempty"`
+	EstimatedCost float64     `json:"estimated_cost,omitempty"`
+	ActualRows    *float64    `json:"actual_rows,omitempty"`
+	Filter        string      `json:"filter,omitempty"`
+	FullScan      bool        `json:"full_scan,omitempty"`
+	Children      []*PlanNode `json:"children,omitempty"`
+}
+
+// ExecutionPlan is a normalized execution plan
+type ExecutionPlan struct {
+	Root          *PlanNode
+	TotalCost     float64
+	EstimatedRows float64
+	FullScans     []string
+	Warnings      []string
+	Raw           interface{}
+}
+
+// annotatePlan fills the totals (when the database did not report them),
+// the list of full scans and the missing-index warnings
+func annotatePlan(plan *ExecutionPlan) {
+	if plan.Root == nil {
+		return
+	}
+	if plan.TotalCost == 0 {
+		plan.TotalCost = plan.Root.EstimatedCost
+	}
+	if plan.EstimatedRows == 0 {
+		plan.EstimatedRows = plan.Root.EstimatedRows
+	}
+
+	var walk func(node *PlanNode)
+	walk = func(node *PlanNode) {
+		if node.FullScan {
+			object := node.Object
+			if object == "" {
+				object = node.Operator
+			}
+			plan.FullScans = append(plan.FullScans, object)
+			if node.Filter != "" {
+				plan.Warnings = append(plan.Warnings, fmt.Sprintf(
+					"full scan of %s filtered by %s - an index on the filtered columns may help", object, node.Filter))
+			}
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(plan.Root)
+}
+
+// planRoot returns the single root node, or a "Query" node grouping several roots
+func planRoot(roots []*PlanNode) *PlanNode {
+	if len(roots) == 1 {
+		return roots[0]
+	}
+	return &PlanNode{Operator: "Query", Children: roots}
+}
+
+// planFloat converts a JSON number or numeric string to float64
+func planFloat(v interface{}) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case string:
+		f, _ := strconv.ParseFloat(n, 64)
+		return f
+	case json.Number:
+		f, _ := n.Float64()
+		return f
+	}
+	return 0
+}
+
+// -----------------------------------------------------------------------------
+// PostgreSQL: EXPLAIN (FORMAT JSON)
+// -----------------------------------------------------------------------------
+
+// parsePostgresPlan parses the output of EXPLAIN (FORMAT JSON)
+func parsePostgresPlan(raw []byte) (*ExecutionPlan, error) {
+	var doc []map[string]interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc) == 0 {
+		return nil, ErrEmptyPlan
+	}
+
+	top, _ := doc[0]["Plan"].(map[string]interface{})
+	if top == nil {
+		return nil, ErrEmptyPlan
+	}
+
+	plan := &ExecutionPlan{Root: postgresPlanNode(top), Raw: doc}
+	annotatePlan(plan)
+	return plan, nil
+}
+
+func postgresPlanNode(p map[string]interface{}) *PlanNode {
+	nodeType, _ := p["Node Type"].(string)
+	node := &PlanNode{
+		Operator:      nodeType,
+		EstimatedRows: planFloat(p["Plan Rows"]),
+		EstimatedCost: planFloat(p["Total Cost"]),
+		FullScan:      nodeType == "Seq Scan",
+	}
+	node.Object, _ = p["Relation Name"].(string)
+	node.Index, _ = p["Index Name"].(string)
+	node.Filter, _ = p["Filter"].(string)
+	if actual, ok := p["Actual Rows"]; ok {
+		rows := planFloat(actual) * planFloat(p["Actual Loops"])
+		node.ActualRows = &rows
+	}
+
+	children, _ := p["Plans"].([]interface{})
+	for _, child := range children {
+		if c, ok := child.(map[string]interface{}); ok {
+			node.Children = append(node.Children, postgresPlanNode(c))
+		}
+	}
+	return node
+}
+
+// -----------------------------------------------------------------------------
+// MySQL: EXPLAIN FORMAT=JSON
+// -----------------------------------------------------------------------------
+
+// mysqlPlanOperations maps the structural keys of a MySQL JSON plan to operator names
+var mysqlPlanOperations = map[string]string{
+	"query_block":                "Query Block",
+	"ordering_operation":         "Sort",
+	"grouping_operation":         "Group",
+	"duplicates_removal":         "Distinct",
+	"windowing":                  "Window",
+	"buffer_result":              "Buffer Result",
+	"materialized_from_subquery": "Materialize",
+	"union_result":               "Union",
+}
+
+// parseMySQLPlan parses the output of EXPLAIN FORMAT=JSON
+func parseMySQLPlan(raw []byte) (*ExecutionPlan, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	block, _ := doc["query_block"].(map[string]interface{})
+	if block == nil {
+		return nil, ErrEmptyPlan
+	}
+
+	plan := &ExecutionPlan{Root: mysqlPlanNode("query_block", block), Raw: doc}
+	if costInfo, ok := block["cost_info"].(map[string]interface{}); ok {
+		plan.TotalCost = planFloat(costInfo["query_cost"])
+	}
+	annotatePlan(plan)
+	return plan, nil
+}
+
+func mysqlPlanNode(key string, v map[string]interface{}) *PlanNode {
+	if key == "table" {
+		return mysqlTableNode(v)
+	}
+
+	node := &PlanNode{Operator: mysqlPlanOperations[key]}
+	if costInfo, ok := v["cost_info"].(map[string]interface{}); ok {
+		node.EstimatedCost = planFloat(costInfo["query_cost"]) + planFloat(costInfo["sort_cost"])
+	}
+
+	keys := make([]string, 0, len(v))
+	for k := range v {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		switch val := v[k].(type) {
+		case map[string]interface{}:
+			if k == "table" || mysqlPlanOperations[k] != "" {
+				node.Children = append(node.Children, mysqlPlanNode(k, val))
+			}
+		case []interface{}:
+			switch k {
+			case "nested_loop":
+				loop := &PlanNode{Operator: "Nested Loop"}
+				for _, item := range val {
+					if m, ok := item.(map[string]interface{}); ok {
+						if table, ok := m["table"].(map[string]interface{}); ok {
+							loop.Children = append(loop.Children, mysqlTableNode(table))
+						}
+					}
+				}
+				node.Children = append(node.Children, loop)
+			case "query_specifications", "attached_subqueries", "optimized_away_subqueries":
+				for _, item := range val {
+					if m, ok := item.(map[string]interface{}); ok {
+						if block, ok := m["query_block"].(map[string]interface{}); ok {
+							node.Children = append(node.Children, mysqlPlanNode("query_block", block))
+						}
+					}
+				}
+			}
+		}
+	}
+	return node
+}
+
+func mysqlTableNode(t map[string]interface{}) *PlanNode {
+	accessType, _ := t["access_type"].(string)
+	node := &PlanNode{
+		Operator:      "Table Access (" + accessType + ")",
+		EstimatedRows: planFloat(t["rows_examined_per_scan"]),
+		FullScan:      accessType == "ALL" || accessType == "index",
+	}
+	node.Object, _ = t["table_name"].(string)
+	node.Index, _ = t["key"].(string)
+	node.Filter, _ = t["attached_condition"].(string)
+	if costInfo, ok := t["cost_info"].(map[string]interface{}); ok {
+		node.EstimatedCost = planFloat(costInfo["prefix_cost"])
+	}
+	if sub, ok := t["materialized_from_subquery"].(map[string]interface{}); ok {
+		node.Children = append(node.Children, mysqlPlanNode("materialized_from_subquery", sub))
+	}
+	return node
+}
+
+// -----------------------------------------------------------------------------
+// SQL Server: SET SHOWPLAN_XML ON
+// -----------------------------------------------------------------------------
+
+// sqlServerScanOperators lists the physical operators that read a whole table or index
+var sqlServerScanOperators = map[string]bool{
+	"Table Scan":           true,
+	"Clustered Index Scan": true,
+	"Index Scan":           true,
+}
+
+// parseSQLServerPlan parses a SHOWPLAN_XML document
+func parseSQLServerPlan(raw string) (*ExecutionPlan, error) {
+	decoder := xml.NewDecoder(strings.NewReader(raw))
+	plan := &ExecutionPlan{Raw: raw}
+
+	var roots, stack []*PlanNode
+	inPredicate := false
+	var missingTable string
+	var missingUsage string
+	var missingColumns map[string][]string
+
+	attr := func(e xml.StartElement, name string) string {
+		for _, a := range e.Attr {
+			if a.Name.Local == name {
+				return a.Value
+			}
+		}
+		return ""
+	}
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+
+		switch e := token.(type) {
+		case xml.StartElement:
+			switch e.Name.Local {
+			case "StmtSimple":
+				if plan.TotalCost == 0 {
+					plan.TotalCost = planFloat(attr(e, "StatementSubTreeCost"))
+					plan.EstimatedRows = planFloat(attr(e, "StatementEstRows"))
+				}
+			case "RelOp":
+				op := attr(e, "PhysicalOp")
+				node := &PlanNode{
+					Operator:      op,
+					EstimatedRows: planFloat(attr(e, "EstimateRows")),
+					EstimatedCost: planFloat(attr(e, "EstimatedTotalSubtreeCost")),
+					FullScan:      sqlServerScanOperators[op],
+				}
+				if len(stack) > 0 {
+					parent := stack[len(stack)-1]
+					parent.Children = append(parent.Children, node)
+				} else {
+					roots = append(roots, node)
+				}
+				stack = append(stack, node)
+			case "Object":
+				if len(stack) > 0 && stack[len(stack)-1].Object == "" {
+					node := stack[len(stack)-1]
+					node.Object = strings.Trim(attr(e, "Table"), "[]")
+					node.Index = strings.Trim(attr(e, "Index"), "[]")
+				}
+			case "Predicate":
+				inPredicate = true
+			case "ScalarOperator":
+				if inPredicate && len(stack) > 0 && stack[len(stack)-1].Filter == "" {
+					stack[len(stack)-1].Filter = attr(e, "ScalarString")
+				}
+			case "MissingIndex":
+				missingTable = strings.Trim(attr(e, "Schema"), "[]") + "." + strings.Trim(attr(e, "Table"), "[]")
+				missingColumns = make(map[string][]string)
+			case "ColumnGroup":
+				missingUsage = attr(e, "Usage")
+			case "Column":
+				if missingColumns != nil && missingUsage != "" {
+					missingColumns[missingUsage] = append(missingColumns[missingUsage], strings.Trim(attr(e, "Name"), "[]"))
+				}
+			case "RunTimeCountersPerThread":
+				if len(stack) > 0 {
+					rows := planFloat(attr(e, "ActualRows"))
+					node := stack[len(stack)-1]
+					if node.ActualRows != nil {
+						rows += *node.ActualRows
+					}
+					node.ActualRows = &rows
+				}
+			}
+		case xml.EndElement:
+			switch e.Name.Local {
+			case "RelOp":
+				if len(stack) > 0 {
+					stack = stack[:len(stack)-1]
+				}
+			case "Predicate":
+				inPredicate = false
+			case "ColumnGroup":
+				missingUsage = ""
+			case "MissingIndex":
+				var parts []string
+				for _, usage := range []string{"EQUALITY", "INEQUALITY", "INCLUDE"} {
+					if cols := missingColumns[usage]; len(cols) > 0 {
+						parts = append(parts, usage+": "+strings.Join(cols, ", "))
+					}
+				}
+				plan.Warnings = append(plan.Warnings, fmt.Sprintf("missing index on %s (%s)", missingTable, strings.Join(parts, "; ")))
+				missingColumns = nil
+			}
+		}
+	}
+
+	if len(roots) == 0 {
+		return nil, ErrEmptyPlan
+	}
+	plan.Root = planRoot(roots)
+	annotatePlan(plan)
+	return plan, nil
+}
+
+// -----------------------------------------------------------------------------
+// Oracle: EXPLAIN PLAN FOR + PLAN_TABLE
+// -----------------------------------------------------------------------------
+
+// OraclePlanRow is a row of PLAN_TABLE
+type OraclePlanRow struct {
+	ID        int
+	ParentID  *int
+	Operation string
+	Options   string
+	Object    string
+	Rows      float64
+	Cost      float64
+	Filter    string
+}
+
+// buildOraclePlan builds the plan tree from PLAN_TABLE rows (ordered by id)
+func buildOraclePlan(rows []OraclePlanRow, xplan string) (*ExecutionPlan, error) {
+	if len(rows) == 0 {
+		return nil, ErrEmptyPlan
+	}
+
+	nodes := make(map[int]*PlanNode)
+	var roots []*PlanNode
+	for _, r := range rows {
+		operator := strings.TrimSpace(r.Operation + " " + r.Options)
+		node := &PlanNode{
+			Operator:      operator,
+			Object:        r.Object,
+			EstimatedRows: r.Rows,
+			EstimatedCost: r.Cost,
+			Filter:        r.Filter,
+			FullScan:      strings.HasSuffix(operator, " FULL") || strings.Contains(operator, "FULL SCAN"),
+		}
+		if strings.HasPrefix(r.Operation, "INDEX") {
+			node.Index, node.Object = r.Object, ""
+		}
+		nodes[r.ID] = node
+
+		if r.ParentID == nil {
+			roots = append(roots, node)
+		} else if parent, ok := nodes[*r.ParentID]; ok {
+			parent.Children = append(parent.Children, node)
+		}
+	}
+
+	plan := &ExecutionPlan{Root: planRoot(roots), Raw: xplan}
+	annotatePlan(plan)
+	return plan, nil
+}
+
+// -----------------------------------------------------------------------------
+// SQLite: EXPLAIN QUERY PLAN
+// -----------------------------------------------------------------------------
+
+// SQLitePlanRow is a row of EXPLAIN QUERY PLAN
+type SQLitePlanRow struct {
+	ID     int
+	Parent int
+	Detail string
+}
+
+// buildSQLitePlan builds the plan tree from EXPLAIN QUERY PLAN rows.
+// SQLite does not report costs or row estimates.
+func buildSQLitePlan(rows []SQLitePlanRow) (*ExecutionPlan, error) {
+	if len(rows) == 0 {
+		return nil, ErrEmptyPlan
+	}
+
+	nodes := make(map[int]*PlanNode)
+	var roots []*PlanNode
+	var raw []string
+	for _, r := range rows {
+		raw = append(raw, r.Detail)
+
+		fields := strings.Fields(r.Detail)
+		node := &PlanNode{Operator: r.Detail}
+		if len(fields) >= 2 && (fields[0] == "SCAN" || fields[0] == "SEARCH") {
+			node.Operator = fields[0]
+			node.Object = fields[1]
+			if i := strings.Index(r.Detail, " USING "); i >= 0 {
+				node.Index = strings.TrimSpace(r.Detail[i+len(" USING "):])
+			}
+			// "SCAN t" reads the whole table; "SCAN t USING COVERING INDEX i" the whole index
+			node.FullScan = fields[0] == "SCAN"
+		}
+		nodes[r.ID] = node
+
+		if parent, ok := nodes[r.Parent]; ok && r.Parent != 0 {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+
+	plan := &ExecutionPlan{Root: planRoot(roots), Raw: raw}
+	annotatePlan(plan)
+	for _, r := range rows {
+		if strings.Contains(r.Detail, "USE TEMP B-TREE") {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("%s - an index may avoid the temporary sort", r.Detail))
+		}
+	}
+	return plan, nil
+}
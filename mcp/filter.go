@@ -0,0 +1,314 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FilterNode is a node of a boolean filter expression tree.
+// Exactly one of And, Or, Not or Condition is set.
+type FilterNode struct {
+	And       []*FilterNode
+	Or        []*FilterNode
+	Not       *FilterNode
+	Condition *FilterCondition
+}
+
+// FilterCondition is a single column comparison (leaf of the filter tree)
+type FilterCondition struct {
+	Column        string
+	Operator      string
+	Value         interface{}
+	CaseSensitive bool
+}
+
+// ColumnResolver validates a column reference and returns the SQL expression to use for it
+type ColumnResolver func(name string) (string, error)
+
+// filterOperators lists the operators accepted in filter conditions
+var filterOperators = []string{
+	"eq", "neq", "gt", "gte", "lt", "lte",
+	"contains", "not_contains", "starts_with", "ends_with", "like", "not_like",
+	"in", "not_in", "between", "not_between",
+	"is_null", "is_not_null",
+}
+
+// comparisonOperators maps simple comparison operators to SQL
+var comparisonOperators = map[string]string{
+	"eq":  "=",
+	"neq": "!=",
+	"gt":  ">",
+	"gte": ">=",
+	"lt":  "<",
+	"lte": "<=",
+}
+
+// ParseFilter parses the "filters" argument into a filter tree.
+// It accepts either a legacy array of conditions (joined with AND) or a single
+// node object using "and", "or" and "not" keys. Returns nil if raw is nil.
+func ParseFilter(raw interface{}) (*FilterNode, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	return parseFilterNode(raw, 1)
+}
+
+func parseFilterNode(raw interface{}, depth int) (*FilterNode, error) {
+	if depth > MaxFilterDepth {
+		return nil, fmt.Errorf("%w (maximum %d)", ErrFilterTooDeep, MaxFilterDepth)
+	}
+
+	switch v := raw.(type) {
+	case []interface{}:
+		children, err := parseFilterList(v, depth)
+		if err != nil {
+			return nil, err
+		}
+		return &FilterNode{And: children}, nil
+	case map[string]interface{}:
+		if and, ok := v["and"]; ok {
+			list, ok := and.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%w: 'and' must be an array", ErrInvalidFilter)
+			}
+			children, err := parseFilterList(list, depth)
+			if err != nil {
+				return nil, err
+			}
+			return &FilterNode{And: children}, nil
+		}
+		if or, ok := v["or"]; ok {
+			list, ok := or.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%w: 'or' must be an array", ErrInvalidFilter)
+			}
+			children, err := parseFilterList(list, depth)
+			if err != nil {
+				return nil, err
+			}
+			return &FilterNode{Or: children}, nil
+		}
+		if not, ok := v["not"]; ok {
+			child, err := parseFilterNode(not, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			return &FilterNode{Not: child}, nil
+		}
+		return parseFilterCondition(v)
+	default:
+		return nil, fmt.Errorf("%w: expected an object or an array", ErrInvalidFilter)
+	}
+}
+
+func parseFilterList(list []interface{}, depth int) ([]*FilterNode, error) {
+	var children []*FilterNode
+	for _, item := range list {
+		child, err := parseFilterNode(item, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+	return children, nil
+}
+
+func parseFilterCondition(v map[string]interface{}) (*FilterNode, error) {
+	column, _ := v["column"].(string)
+	operator, _ := v["operator"].(string)
+	if column == "" || operator == "" {
+		return nil, fmt.Errorf("%w: condition requires 'column' and 'operator'", ErrInvalidFilter)
+	}
+
+	caseSensitive, _ := v["case_sensitive"].(bool)
+
+	return &FilterNode{Condition: &FilterCondition{
+		Column:        column,
+		Operator:      strings.ToLower(operator),
+		Value:         v["value"],
+		CaseSensitive: caseSensitive,
+	}}, nil
+}
+
+// FilterBuilder renders a filter tree into a SQL boolean expression using
+// dialect placeholders, collecting the bound parameters
+type FilterBuilder struct {
+	qb         *QueryBuilder
+	resolve    ColumnResolver
+	params     []interface{}
+	paramIndex int
+	conditions int
+}
+
+// NewFilterBuilder creates a FilterBuilder whose first placeholder has index startIndex
+func NewFilterBuilder(qb *QueryBuilder, resolve ColumnResolver, startIndex int) *FilterBuilder {
+	return &FilterBuilder{
+		qb:         qb,
+		resolve:    resolve,
+		paramIndex: startIndex,
+	}
+}
+
+// Params returns the parameters bound so far, in placeholder order
+func (fb *FilterBuilder) Params() []interface{} {
+	return fb.params
+}
+
+// NextIndex returns the index of the next placeholder
+func (fb *FilterBuilder) NextIndex() int {
+	return fb.paramIndex
+}
+
+// Build renders the node into a SQL expression. Returns "" for an empty tree.
+func (fb *FilterBuilder) Build(node *FilterNode) (string, error) {
+	if node == nil {
+		return "", nil
+	}
+
+	switch {
+	case node.Condition != nil:
+		fb.conditions++
+		if fb.conditions > MaxFilterConditions {
+			return "", fmt.Errorf("%w (maximum %d)", ErrTooManyFilterConditions, MaxFilterConditions)
+		}
+		return fb.buildCondition(node.Condition)
+	case node.Not != nil:
+		expr, err := fb.Build(node.Not)
+		if err != nil || expr == "" {
+			return expr, err
+		}
+		return "NOT (" + expr + ")", nil
+	case node.Or != nil:
+		return fb.buildGroup(node.Or, " OR ")
+	default:
+		return fb.buildGroup(node.And, " AND ")
+	}
+}
+
+func (fb *FilterBuilder) buildGroup(children []*FilterNode, separator string) (string, error) {
+	var parts []string
+	for _, child := range children {
+		expr, err := fb.Build(child)
+		if err != nil {
+			return "", err
+		}
+		if expr != "" {
+			parts = append(parts, expr)
+		}
+	}
+
+	switch len(parts) {
+	case 0:
+		return "", nil
+	case 1:
+		return parts[0], nil
+	default:
+		return "(" + strings.Join(parts, separator) + ")", nil
+	}
+}
+
+func (fb *FilterBuilder) bind(value interface{}) string {
+	placeholder := fb.qb.Placeholder(fb.paramIndex)
+	fb.params = append(fb.params, value)
+	fb.paramIndex++
+	return placeholder
+}
+
+func (fb *FilterBuilder) buildCondition(c *FilterCondition) (string, error) {
+	column, err := fb.resolve(c.Column)
+	if err != nil {
+		return "", err
+	}
+
+	if sqlOp, ok := comparisonOperators[c.Operator]; ok {
+		return fmt.Sprintf("%s %s %s", column, sqlOp, fb.bind(c.Value)), nil
+	}
+
+	switch c.Operator {
+	case "contains", "not_contains", "starts_with", "ends_with", "like", "not_like":
+		value, ok := c.Value.(string)
+		if !ok {
+			return "", patternValueError(c.Operator)
+		}
+		pattern := value
+		switch c.Operator {
+		case "contains", "not_contains":
+			pattern = "%" + escapeLikeValue(value) + "%"
+		case "starts_with":
+			pattern = escapeLikeValue(value) + "%"
+		case "ends_with":
+			pattern = "%" + escapeLikeValue(value)
+		}
+		placeholder := fb.bind(fb.qb.LikePattern(pattern, c.CaseSensitive))
+		expr := fb.qb.LikeExpression(column, placeholder, c.CaseSensitive)
+		if strings.HasPrefix(c.Operator, "not_") {
+			return "NOT (" + expr + ")", nil
+		}
+		return expr, nil
+	case "in", "not_in":
+		values, ok := c.Value.([]interface{})
+		if !ok || len(values) == 0 {
+			return "", fmt.Errorf("%w: '%s'", ErrInRequiresArray, c.Operator)
+		}
+		if len(values) > MaxFilterInValues {
+			return "", fmt.Errorf("%w (maximum %d)", ErrTooManyInValues, MaxFilterInValues)
+		}
+		placeholders := make([]string, len(values))
+		for i, v := range values {
+			placeholders[i] = fb.bind(v)
+		}
+		op := "IN"
+		if c.Operator == "not_in" {
+			op = "NOT IN"
+		}
+		return fmt.Sprintf("%s %s (%s)", column, op, strings.Join(placeholders, ", ")), nil
+	case "between", "not_between":
+		values, ok := c.Value.([]interface{})
+		if !ok || len(values) != 2 {
+			return "", fmt.Errorf("%w: '%s'", ErrBetweenRequiresTwoValues, c.Operator)
+		}
+		low := fb.bind(values[0])
+		high := fb.bind(values[1])
+		op := "BETWEEN"
+		if c.Operator == "not_between" {
+			op = "NOT BETWEEN"
+		}
+		return fmt.Sprintf("%s %s %s AND %s", column, op, low, high), nil
+	case "is_null":
+		return fmt.Sprintf("%s IS NULL", column), nil
+	case "is_not_null":
+		return fmt.Sprintf("%s IS NOT NULL", column), nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrInvalidOperator, c.Operator)
+	}
+}
+
+// patternValueError returns the error for a pattern operator used with a non-string value
+func patternValueError(operator string) error {
+	switch operator {
+	case "contains":
+		return ErrContainsRequiresString
+	case "starts_with":
+		return ErrStartsWithRequiresString
+	case "ends_with":
+		return ErrEndsWithRequiresString
+	default:
+		return fmt.Errorf("%w: '%s'", ErrPatternRequiresString, operator)
+	}
+}
+
+// filterInputSchema returns the JSON schema for the filter tree argument
+func filterInputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"description": "Filter expression. Either an array of conditions (joined with AND) or a node: " +
+			"{\"and\": [...]}, {\"or\": [...]}, {\"not\": {...}} or a condition " +
+			"{\"column\": \"name\", \"operator\": \"contains\", \"value\": \"john\", \"case_sensitive\": false}. " +
+			"Operators: " + strings.Join(filterOperators, ", ") + ". " +
+			"'contains', 'starts_with' and 'ends_with' match the value literally; 'like' patterns use % and _ wildcards, " +
+			"a backslash escaping the next character. " +
+			"'in'/'not_in' take an array of values, 'between'/'not_between' take [low, high], " +
+			"'is_null'/'is_not_null' take no value. Example: " +
+			"{\"and\": [{\"column\": \"status\", \"operator\": \"in\", \"value\": [\"open\", \"pending\"]}, " +
+			"{\"or\": [{\"column\": \"owner\", \"operator\": \"eq\", \"value\": \"ana\"}, {\"column\": \"owner\", \"operator\": \"is_null\"}]}]}",
+	}
+}
@@ -0,0 +1,116 @@
+package mcp
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func TestFilterBuilderPatterns(t *testing.T) {
+	resolve := func(name string) (string, error) { return name, nil }
+
+	tests := []struct {
+		name      string
+		driver    string
+		condition FilterCondition
+		sql       string
+		param     string
+	}{
+		{"postgres contains", "postgres", FilterCondition{Column: "c", Operator: "contains", Value: `50%_a\b[x]`},
+			"c ILIKE $1", `%50\%\_a\\b[x]%`},
+		{"postgres starts_with case sensitive", "postgres", FilterCondition{Column: "c", Operator: "starts_with", Value: "a_b", CaseSensitive: true},
+			"c LIKE $1", `a\_b%`},
+		{"postgres like", "postgres", FilterCondition{Column: "c", Operator: "like", Value: `a\%%`},
+			"c ILIKE $1", `a\%%`},
+		{"mysql contains", "mysql", FilterCondition{Column: "c", Operator: "contains", Value: `50%_a\b[x]`},
+			"LOWER(c) LIKE LOWER(?)", `%50\%\_a\\b[x]%`},
+		{"mysql ends_with case sensitive", "mysql", FilterCondition{Column: "c", Operator: "ends_with", Value: "%", CaseSensitive: true},
+			"c LIKE BINARY ?", `%\%`},
+		{"sqlserver contains", "sqlserver", FilterCondition{Column: "c", Operator: "contains", Value: `50%_a\b[x]`},
+			`c COLLATE Latin1_General_CI_AS LIKE @p1 ESCAPE '\'`, `%50\%\_a\\b\[x]%`},
+		{"sqlserver like", "sqlserver", FilterCondition{Column: "c", Operator: "not_like", Value: `[a-c]\_%`},
+			`NOT (c COLLATE Latin1_General_CI_AS LIKE @p1 ESCAPE '\')`, `[a-c]\_%`},
+		{"oracle contains", "godror", FilterCondition{Column: "c", Operator: "contains", Value: `50%_a\b[x]`},
+			`LOWER(c) LIKE LOWER(:1) ESCAPE '\'`, `%50\%\_a\\b[x]%`},
+		{"oracle like with stray escapes", "godror", FilterCondition{Column: "c", Operator: "like", Value: `\a%\`, CaseSensitive: true},
+			`c LIKE :1 ESCAPE '\'`, `a%\\`},
+		{"sqlite contains", "sqlite3", FilterCondition{Column: "c", Operator: "contains", Value: `50%_a\b[x]`},
+			`c LIKE ? ESCAPE '\'`, `%50\%\_a\\b[x]%`},
+		{"sqlite contains case sensitive", "sqlite3", FilterCondition{Column: "c", Operator: "contains", Value: `50%_a\b[x]*?`, CaseSensitive: true},
+			"c GLOB ?", `*50%_a\b[[]x][*][?]*`},
+		{"sqlite like case sensitive", "sqlite3", FilterCondition{Column: "c", Operator: "like", Value: `a\%_%`, CaseSensitive: true},
+			"c GLOB ?", `a%?*`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fb := NewFilterBuilder(NewQueryBuilder(tt.driver), resolve, 1)
+			got, err := fb.Build(&FilterNode{Condition: &tt.condition})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.sql {
+				t.Errorf("Build() = %q, want %q", got, tt.sql)
+			}
+			if params := fb.Params(); !reflect.DeepEqual(params, []interface{}{tt.param}) {
+				t.Errorf("Params() = %q, want [%q]", params, tt.param)
+			}
+		})
+	}
+}
+
+func TestFilterBuilderPatternsSQLite(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`CREATE TABLE t (c TEXT);
+		INSERT INTO t VALUES ('50% off'), ('500 off'), ('a_b'), ('axb'), ('x\y'), ('[x]'), ('*'), ('?')`); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		operator      string
+		value         string
+		caseSensitive bool
+		want          []string
+	}{
+		{"contains", "0%", false, []string{"50% off"}},
+		{"contains", "0%", true, []string{"50% off"}},
+		{"contains", "_", false, []string{"a_b"}},
+		{"starts_with", "a_", true, []string{"a_b"}},
+		{"ends_with", `\y`, false, []string{`x\y`}},
+		{"contains", "[x]", true, []string{"[x]"}},
+		{"contains", "*", true, []string{"*"}},
+		{"contains", "?", true, []string{"?"}},
+		{"like", "a_b", false, []string{"a_b", "axb"}},
+		{"like", `a\_b`, true, []string{"a_b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.operator+" "+tt.value, func(t *testing.T) {
+			fb := NewFilterBuilder(NewQueryBuilder("sqlite3"), func(name string) (string, error) { return name, nil }, 1)
+			where, err := fb.Build(&FilterNode{Condition: &FilterCondition{Column: "c", Operator: tt.operator, Value: tt.value, CaseSensitive: tt.caseSensitive}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			rows, err := db.Query("SELECT c FROM t WHERE "+where+" ORDER BY rowid", fb.Params()...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer rows.Close()
+			var got []string
+			for rows.Next() {
+				var c string
+				if err := rows.Scan(&c); err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, c)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("%s %q matched %q, want %q", tt.operator, tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,313 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// HistoryEntry is a query tool call kept in the history of a session
+type HistoryEntry struct {
+	ID         int64                  `json:"id"`
+	SessionID  string                 `json:"session_id"`
+	Timestamp  time.Time              `json:"timestamp"`
+	Datasource string                 `json:"datasource"`
+	Tool       string                 `json:"tool"`
+	Arguments  map[string]interface{} `json:"arguments"`
+	Statements []AuditStatement       `json:"statements,omitempty"`
+	RowCount   *int                   `json:"row_count,omitempty"`
+	DurationMs int64                  `json:"duration_ms"`
+	Outcome    string                 `json:"outcome"`
+	Error      string                 `json:"error,omitempty"`
+	RerunOf    int64                  `json:"rerun_of,omitempty"`
+	// Redacted is set when the masking detectors changed some arguments, which makes the
+	// entry unfit for rerun_query
+	Redacted bool `json:"redacted,omitempty"`
+}
+
+// HistoryStore keeps the latest entries of each session
+type HistoryStore interface {
+	// Add assigns the entry an ID and stores it, dropping the oldest entries of its session
+	Add(entry *HistoryEntry) error
+	// List returns the latest entries of a session, newest first
+	List(session string, limit int) ([]*HistoryEntry, error)
+	// Get returns an entry of a session, nil if there is none
+	Get(session string, id int64) (*HistoryEntry, error)
+	Close() error
+}
+
+// NewHistoryStore returns the history store of the configuration: a SQLite file when a
+// path is set, memory otherwise. It returns nil when the history is disabled.
+func NewHistoryStore(config *HistoryConfig) (HistoryStore, error) {
+	maxEntries := DefaultHistoryMaxEntries
+	if config != nil {
+		if config.Disabled {
+			return nil, nil
+		}
+		if config.MaxEntries > 0 {
+			maxEntries = config.MaxEntries
+		}
+		if config.Path != "" {
+			return newSQLiteHistory(config.Path, maxEntries)
+		}
+	}
+	return &memoryHistory{maxEntries: maxEntries, sessions: make(map[string][]*HistoryEntry)}, nil
+}
+
+// memoryHistory keeps a ring buffer of entries per session
+type memoryHistory struct {
+	mu         sync.Mutex
+	maxEntries int
+	lastID     int64
+	sessions   map[string][]*HistoryEntry
+}
+
+func (h *memoryHistory) Add(entry *HistoryEntry) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.lastID++
+	entry.ID = h.lastID
+	entries := append(h.sessions[entry.SessionID], entry)
+	if len(entries) > h.maxEntries {
+		entries = entries[len(entries)-h.maxEntries:]
+	}
+	h.sessions[entry.SessionID] = entries
+	return nil
+}
+
+func (h *memoryHistory) List(session string, limit int) ([]*HistoryEntry, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	entries := h.sessions[session]
+	var list []*HistoryEntry
+	for i := len(entries) - 1; i >= 0 && len(list) < limit; i-- {
+		list = append(list, entries[i])
+	}
+	return list, nil
+}
+
+func (h *memoryHistory) Get(session string, id int64) (*HistoryEntry, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, entry := range h.sessions[session] {
+		if entry.ID == id {
+			return entry, nil
+		}
+	}
+	return nil, nil
+}
+
+func (h *memoryHistory) Close() error {
+	return nil
+}
+
+// sqliteHistory keeps the entries in a local SQLite file, surviving restarts
+type sqliteHistory struct {
+	db         *sql.DB
+	maxEntries int
+}
+
+func newSQLiteHistory(path string, maxEntries int) (*sqliteHistory, error) {
+	db, err := sql.Open(string(DriverSQLite), path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOpeningHistory, err)
+	}
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(`CREATE TABLE IF NOT EXISTS query_history (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		session_id TEXT NOT NULL,
+		entry TEXT NOT NULL
+	)`)
+	if err == nil {
+		_, err = db.Exec(`CREATE INDEX IF NOT EXISTS query_history_session ON query_history (session_id, id)`)
+	}
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%w: %v", ErrOpeningHistory, err)
+	}
+	return &sqliteHistory{db: db, maxEntries: maxEntries}, nil
+}
+
+func (h *sqliteHistory) Add(entry *HistoryEntry) error {
+	tx, err := h.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// The ID is only known after the insert, so the stored JSON is written in two steps
+	result, err := tx.Exec(`INSERT INTO query_history (session_id, entry) VALUES (?, '')`, entry.SessionID)
+	if err != nil {
+		return err
+	}
+	if entry.ID, err = result.LastInsertId(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if _, err = tx.Exec(`UPDATE query_history SET entry = ? WHERE id = ?`, string(data), entry.ID); err != nil {
+		return err
+	}
+	if _, err = tx.Exec(`DELETE FROM query_history WHERE session_id = ? AND id NOT IN (
+		SELECT id FROM query_history WHERE session_id = ? ORDER BY id DESC LIMIT ?)`,
+		entry.SessionID, entry.SessionID, h.maxEntries); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (h *sqliteHistory) List(session string, limit int) ([]*HistoryEntry, error) {
+	rows, err := h.db.Query(`SELECT entry FROM query_history WHERE session_id = ? ORDER BY id DESC LIMIT ?`, session, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*HistoryEntry
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var entry HistoryEntry
+		if err := json.Unmarshal([]byte(data), &entry); err != nil {
+			return nil, err
+		}
+		list = append(list, &entry)
+	}
+	return list, rows.Err()
+}
+
+func (h *sqliteHistory) Get(session string, id int64) (*HistoryEntry, error) {
+	var data string
+	err := h.db.QueryRow(`SELECT entry FROM query_history WHERE session_id = ? AND id = ?`, session, id).Scan(&data)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entry HistoryEntry
+	if err := json.Unmarshal([]byte(data), &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (h *sqliteHistory) Close() error {
+	return h.db.Close()
+}
+
+// historyHandlers returns the tools kept in the query history, which rerun_query can replay
+func (s *DbMCPServer) historyHandlers() map[string]server.ToolHandlerFunc {
+	return map[string]server.ToolHandlerFunc{
+		"execute_query":   s.handleExecuteQuery,
+		"list_table_rows": s.handleListTableRows,
+		"aggregate_table": s.handleAggregateTable,
+		"query_builder":   s.handleQueryBuilder,
+	}
+}
+
+// historyMiddleware adds the calls of the query tools to the history of their session
+func (s *DbMCPServer) historyMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if _, ok := s.historyHandlers()[request.Params.Name]; !ok || s.history == nil {
+			return next(ctx, request)
+		}
+		return s.runWithHistory(ctx, request, next, 0)
+	}
+}
+
+// runWithHistory runs a query tool and records it, as a rerun of another entry when rerunOf is set
+func (s *DbMCPServer) runWithHistory(ctx context.Context, request mcp.CallToolRequest, handler server.ToolHandlerFunc, rerunOf int64) (*mcp.CallToolResult, error) {
+	ctx, record := withCallRecord(ctx)
+	record.mu.Lock()
+	first := len(record.statements)
+	record.rowCount = nil
+	record.mu.Unlock()
+
+	args, _ := getArgs(request.Params.Arguments)
+	entry := &HistoryEntry{
+		SessionID:  sessionID(ctx),
+		Timestamp:  time.Now().UTC(),
+		Datasource: s.datasource,
+		Tool:       request.Params.Name,
+		Arguments:  args,
+		RerunOf:    rerunOf,
+	}
+
+	start := time.Now()
+	result, err := handler(ctx, request)
+	entry.DurationMs = time.Since(start).Milliseconds()
+
+	record.mu.Lock()
+	entry.Statements = append([]AuditStatement(nil), record.statements[first:]...)
+	entry.RowCount = record.rowCount
+	record.mu.Unlock()
+	if entry.Outcome, entry.Error = callOutcome(result, err); entry.Outcome != "success" && len(entry.Statements) == 0 {
+		// Rejected before reaching the database, nothing worth replaying
+		return result, err
+	}
+
+	// Hide the values the audit log hides
+	masker := s.masker()
+	redact := s.config != nil && s.config.Audit != nil && s.config.Audit.RedactParams
+	for i := range entry.Statements {
+		entry.Statements[i].Params = redactAuditParams(entry.Statements[i].Params, masker, redact)
+	}
+	if masker != nil {
+		masked, changed := maskHistoryValue(entry.Arguments, masker)
+		entry.Arguments, _ = masked.(map[string]interface{})
+		entry.Redacted = changed
+	}
+
+	if herr := s.history.Add(entry); herr != nil {
+		log.Printf("Query history: could not store entry for %s: %v\n", entry.Tool, herr)
+	}
+	return result, err
+}
+
+// maskHistoryValue returns a copy of a tool argument with the parts of its text values
+// matched by the masking detectors masked, and whether any was
+func maskHistoryValue(value interface{}, masker *Masker) (interface{}, bool) {
+	switch v := value.(type) {
+	case string:
+		if masked, changed := masker.detect(v); changed {
+			return masked, true
+		}
+		return v, false
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		changed := false
+		for key, item := range v {
+			masked, itemChanged := maskHistoryValue(item, masker)
+			copied[key] = masked
+			changed = changed || itemChanged
+		}
+		return copied, changed
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		changed := false
+		for i, item := range v {
+			masked, itemChanged := maskHistoryValue(item, masker)
+			copied[i] = masked
+			changed = changed || itemChanged
+		}
+		return copied, changed
+	default:
+		return value, false
+	}
+}
@@ -0,0 +1,69 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestRunWithHistoryMasksValues(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(`CREATE TABLE contacts (id INTEGER PRIMARY KEY, email TEXT)`); err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := NewHistoryStore(&HistoryConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &Config{Datasources: map[string]*DataSourceConfig{
+		"default": {Masking: &MaskingConfig{Detectors: []MaskingDetectorConfig{{Name: "email", Strategy: MaskRedact}}}},
+	}}
+	s := &DbMCPServer{db: db, queryBuilder: NewQueryBuilder("sqlite3"), config: config, history: history,
+		datasource: "default", profile: "default"}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "list_table_rows"
+	request.Params.Arguments = map[string]interface{}{
+		"table_name": "contacts",
+		"filters":    []interface{}{map[string]interface{}{"column": "email", "operator": "eq", "value": "ana@example.com"}},
+	}
+	if _, err := s.runWithHistory(context.Background(), request, s.handleListTableRows, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := history.List(sessionID(context.Background()), 10)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("history entries = %v, %v, want 1", entries, err)
+	}
+	stored, err := json.Marshal(entries[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(stored), "ana@example.com") {
+		t.Fatalf("history entry keeps the email: %s", stored)
+	}
+	if !entries[0].Redacted {
+		t.Fatal("history entry with masked arguments is not marked redacted")
+	}
+	if args := request.Params.Arguments.(map[string]interface{}); !strings.Contains(args["filters"].([]interface{})[0].(map[string]interface{})["value"].(string), "@") {
+		t.Fatal("masking the history entry changed the request arguments")
+	}
+
+	rerun := mcp.CallToolRequest{}
+	rerun.Params.Arguments = map[string]interface{}{"id": float64(entries[0].ID)}
+	result, err := s.handleRerunQuery(context.Background(), rerun)
+	if err != nil || !result.IsError {
+		t.Fatalf("rerun of a redacted entry = %v, %v, want an error result", result, err)
+	}
+}
@@ -0,0 +1,306 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// unmeteredTools are the tools not counted by rate limits and quotas: they manage
+// connections or report usage rather than query the active datasource
+var unmeteredTools = map[string]bool{
+	"configure_datasource":    true,
+	"get_current_datasource":  true,
+	"test_connection":         true,
+	"disconnect_datasource":   true,
+	"list_database_drivers":   true,
+	"get_usage":               true,
+	"list_query_history":      true,
+	"get_query_history_entry": true,
+	"list_snapshots":          true,
+}
+
+// tokenBucket allows bursts of up to burst calls, refilled at rate calls per second
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(perMinute float64, burst int, now time.Time) *tokenBucket {
+	if burst <= 0 {
+		burst = int(math.Max(1, math.Ceil(perMinute)))
+	}
+	return &tokenBucket{rate: perMinute / 60, burst: float64(burst), tokens: float64(burst), last: now}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+}
+
+// take consumes a token, or returns how long until one is available
+func (b *tokenBucket) take(now time.Time) (bool, time.Duration) {
+	b.refill(now)
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+// dailyUsage accumulates the calls of a UTC day
+type dailyUsage struct {
+	day       string
+	calls     int64
+	rejected  int64
+	rows      int64
+	queryTime time.Duration
+}
+
+// reset starts a new day when the date changed
+func (u *dailyUsage) reset(now time.Time) {
+	if day := now.UTC().Format("2006-01-02"); u.day != day {
+		*u = dailyUsage{day: day}
+	}
+}
+
+// Limiter enforces the rate limits, concurrency limits and daily quotas of the datasources
+type Limiter struct {
+	mu          sync.Mutex
+	sessions    map[string]*tokenBucket // keyed by session and datasource
+	datasources map[string]*tokenBucket
+	running     map[string]chan struct{}
+	usage       map[string]*dailyUsage // per datasource
+	sessionUse  map[string]*dailyUsage // per session and datasource
+}
+
+// NewLimiter creates an empty limiter
+func NewLimiter() *Limiter {
+	return &Limiter{
+		sessions:    make(map[string]*tokenBucket),
+		datasources: make(map[string]*tokenBucket),
+		running:     make(map[string]chan struct{}),
+		usage:       make(map[string]*dailyUsage),
+		sessionUse:  make(map[string]*dailyUsage),
+	}
+}
+
+func sessionKey(session, datasource string) string {
+	return session + "\x00" + datasource
+}
+
+// usageOf returns the usage of a key for the current day
+func usageOf(usage map[string]*dailyUsage, key string, now time.Time) *dailyUsage {
+	u, ok := usage[key]
+	if !ok {
+		u = &dailyUsage{}
+		usage[key] = u
+	}
+	u.reset(now)
+	return u
+}
+
+// admit checks the quotas and rate limits of a call, consuming its tokens
+func (l *Limiter) admit(session, datasource string, limits *LimitsConfig, now time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	usage := usageOf(l.usage, datasource, now)
+	sessionUse := usageOf(l.sessionUse, sessionKey(session, datasource), now)
+	reject := func(err error) error {
+		usage.rejected++
+		sessionUse.rejected++
+		return err
+	}
+
+	untilTomorrow := now.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour).Sub(now)
+	if limits.DailyMaxRows > 0 && usage.rows >= limits.DailyMaxRows {
+		return reject(fmt.Errorf("%w: %d of %d rows returned today, retry after %s",
+			ErrQuotaExceeded, usage.rows, limits.DailyMaxRows, retryAfter(untilTomorrow)))
+	}
+	if limits.DailyMaxQuerySeconds > 0 && usage.queryTime.Seconds() >= limits.DailyMaxQuerySeconds {
+		return reject(fmt.Errorf("%w: %.0fs of %gs query time used today, retry after %s",
+			ErrQuotaExceeded, usage.queryTime.Seconds(), limits.DailyMaxQuerySeconds, retryAfter(untilTomorrow)))
+	}
+
+	var sessionBucket *tokenBucket
+	if limits.SessionRequestsPerMinute > 0 {
+		key := sessionKey(session, datasource)
+		if sessionBucket = l.sessions[key]; sessionBucket == nil {
+			sessionBucket = newTokenBucket(limits.SessionRequestsPerMinute, limits.SessionBurst, now)
+			l.sessions[key] = sessionBucket
+		}
+		if ok, wait := sessionBucket.take(now); !ok {
+			return reject(fmt.Errorf("%w: session limit of %g calls per minute, retry after %s",
+				ErrRateLimited, limits.SessionRequestsPerMinute, retryAfter(wait)))
+		}
+	}
+	if limits.DatasourceRequestsPerMinute > 0 {
+		bucket := l.datasources[datasource]
+		if bucket == nil {
+			bucket = newTokenBucket(limits.DatasourceRequestsPerMinute, limits.DatasourceBurst, now)
+			l.datasources[datasource] = bucket
+		}
+		if ok, wait := bucket.take(now); !ok {
+			if sessionBucket != nil {
+				sessionBucket.tokens++ // the call did not happen
+			}
+			return reject(fmt.Errorf("%w: datasource limit of %g calls per minute, retry after %s",
+				ErrRateLimited, limits.DatasourceRequestsPerMinute, retryAfter(wait)))
+		}
+	}
+
+	usage.calls++
+	sessionUse.calls++
+	return nil
+}
+
+// acquire waits for a query slot of a datasource, returning the function releasing it
+func (l *Limiter) acquire(ctx context.Context, datasource string, limits *LimitsConfig) (func(), error) {
+	if limits.MaxConcurrentQueries <= 0 {
+		return func() {}, nil
+	}
+
+	l.mu.Lock()
+	slots, ok := l.running[datasource]
+	if !ok || cap(slots) != limits.MaxConcurrentQueries {
+		slots = make(chan struct{}, limits.MaxConcurrentQueries)
+		l.running[datasource] = slots
+	}
+	l.mu.Unlock()
+
+	timer := time.NewTimer(ConcurrencyWaitTimeout)
+	defer timer.Stop()
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	l.mu.Lock()
+	usageOf(l.usage, datasource, time.Now()).rejected++
+	l.mu.Unlock()
+	return nil, fmt.Errorf("%w: %d queries already running on %s, retry after %s",
+		ErrTooManyConcurrentQueries, limits.MaxConcurrentQueries, datasource, retryAfter(time.Second))
+}
+
+// record adds the rows and query time of a finished call to the daily usage
+func (l *Limiter) record(session, datasource string, rows int, queryTime time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	for _, u := range []*dailyUsage{usageOf(l.usage, datasource, now), usageOf(l.sessionUse, sessionKey(session, datasource), now)} {
+		u.rows += int64(rows)
+		u.queryTime += queryTime
+	}
+}
+
+// retryAfter formats a wait in whole seconds, rounded up
+func retryAfter(wait time.Duration) string {
+	return fmt.Sprintf("%ds", int64(math.Ceil(wait.Seconds())))
+}
+
+// sessionID returns the MCP session of a call, empty when there is none
+func sessionID(ctx context.Context) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return session.SessionID()
+	}
+	return ""
+}
+
+// limitsMiddleware applies the limits of the active datasource to every metered tool call.
+// Buckets and quotas are keyed by the settings profile, so all ad-hoc connections share them.
+func (s *DbMCPServer) limitsMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		datasource := s.profile
+		limits := s.config.Limits(datasource)
+		if limits == nil || datasource == "" || unmeteredTools[request.Params.Name] {
+			return next(ctx, request)
+		}
+
+		session := sessionID(ctx)
+		if err := s.limiter.admit(session, datasource, limits, time.Now()); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		release, err := s.limiter.acquire(ctx, datasource, limits)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		defer release()
+
+		ctx, record := withCallRecord(ctx)
+		start := time.Now()
+		result, err := next(ctx, request)
+
+		rows := 0
+		if record.rowCount != nil {
+			rows = *record.rowCount
+		}
+		s.limiter.record(session, datasource, rows, time.Since(start))
+		return result, err
+	}
+}
+
+// Usage returns the limits and current usage of a datasource as seen by a session
+func (l *Limiter) Usage(session, datasource string, limits *LimitsConfig) map[string]interface{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	describe := func(u *dailyUsage) map[string]interface{} {
+		return map[string]interface{}{
+			"calls":              u.calls,
+			"rejected":           u.rejected,
+			"rows":               u.rows,
+			"query_time_seconds": math.Round(u.queryTime.Seconds()*1000) / 1000,
+		}
+	}
+
+	today := describe(usageOf(l.usage, datasource, now))
+	today["date"] = now.UTC().Format("2006-01-02")
+	today["resets_at"] = now.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+
+	response := map[string]interface{}{
+		"datasource": datasource,
+		"session_id": session,
+		"limits":     limits,
+		"today":      today,
+		"session":    describe(usageOf(l.sessionUse, sessionKey(session, datasource), now)),
+	}
+	if limits == nil {
+		return response
+	}
+
+	if limits.DailyMaxRows > 0 {
+		today["rows_remaining"] = max(0, limits.DailyMaxRows-l.usage[datasource].rows)
+	}
+	if limits.DailyMaxQuerySeconds > 0 {
+		today["query_seconds_remaining"] = math.Max(0, limits.DailyMaxQuerySeconds-l.usage[datasource].queryTime.Seconds())
+	}
+	if bucket := l.sessions[sessionKey(session, datasource)]; bucket != nil {
+		bucket.refill(now)
+		response["session_calls_available"] = math.Floor(bucket.tokens)
+	}
+	if bucket := l.datasources[datasource]; bucket != nil {
+		bucket.refill(now)
+		response["datasource_calls_available"] = math.Floor(bucket.tokens)
+	}
+	if limits.MaxConcurrentQueries > 0 {
+		running := 0
+		if slots, ok := l.running[datasource]; ok {
+			running = len(slots)
+		}
+		response["running_queries"] = running
+	}
+	return response
+}
@@ -0,0 +1,505 @@
+package mcp
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// builtinDetectors are the value detectors available by name
+var builtinDetectors = map[string]struct {
+	pattern string
+	luhn    bool
+}{
+	"email": {pattern: `[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`},
+	"phone": {pattern: `\+\d{1,3}[ .-]?(?:\(\d{1,4}\)[ .-]?)?\d{2,4}(?:[ .-]?\d{2,4}){1,4}|\(?\b\d{3}\)?[ .-]\d{3}[ .-]\d{4}\b`},
+	"card":  {pattern: `\b\d(?:[ -]?\d){12,18}\b`, luhn: true},
+	"ssn":   {pattern: `\b\d{3}-\d{2}-\d{4}\b`},
+}
+
+// ColumnSource is a table column a result column may come from. An empty or "*" name
+// stands for any: Column "*" is any column of the table, and an empty Table any table,
+// for result columns whose origin could not be resolved.
+type ColumnSource struct {
+	Schema string
+	Table  string
+	Column string
+}
+
+// maskingColumnRule masks whole columns. Its segments are matched right-aligned
+// against schema, table and column.
+type maskingColumnRule struct {
+	segments []string
+	strategy string
+}
+
+type maskingDetector struct {
+	name     string
+	pattern  *regexp.Regexp
+	luhn     bool
+	strategy string
+}
+
+// Masker masks sensitive values of result rows
+type Masker struct {
+	columns   []maskingColumnRule
+	detectors []maskingDetector
+	key       []byte
+}
+
+// NewMasker compiles masking rules. The hash key is the configured one, then
+// DB_MCP_MASKING_KEY, then fallbackKey.
+func NewMasker(config *MaskingConfig, fallbackKey []byte) (*Masker, error) {
+	m := &Masker{key: fallbackKey}
+	if config.HashKey != "" {
+		m.key = []byte(config.HashKey)
+	} else if key := os.Getenv("DB_MCP_MASKING_KEY"); key != "" {
+		m.key = []byte(key)
+	}
+
+	for _, c := range config.Columns {
+		segments := strings.Split(strings.ToLower(strings.TrimSpace(c.Match)), ".")
+		if len(segments) > 3 {
+			return nil, fmt.Errorf("%w: %q has more than 3 segments", ErrInvalidMaskingRule, c.Match)
+		}
+		for _, segment := range segments {
+			if segment == "" {
+				return nil, fmt.Errorf("%w: %q has an empty segment", ErrInvalidMaskingRule, c.Match)
+			}
+			if _, err := path.Match(segment, ""); err != nil {
+				return nil, fmt.Errorf("%w: %q: %v", ErrInvalidMaskingRule, c.Match, err)
+			}
+		}
+		if err := validateMaskStrategy(c.Strategy); err != nil {
+			return nil, err
+		}
+		m.columns = append(m.columns, maskingColumnRule{segments: segments, strategy: c.Strategy})
+	}
+
+	for _, d := range config.Detectors {
+		pattern, luhn := d.Pattern, d.Luhn
+		if pattern == "" {
+			builtin, ok := builtinDetectors[d.Name]
+			if !ok {
+				return nil, fmt.Errorf("%w: unknown detector %q", ErrInvalidMaskingRule, d.Name)
+			}
+			pattern, luhn = builtin.pattern, builtin.luhn
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%w: detector %q: %v", ErrInvalidMaskingRule, d.Name, err)
+		}
+		if err := validateMaskStrategy(d.Strategy); err != nil {
+			return nil, err
+		}
+		m.detectors = append(m.detectors, maskingDetector{name: d.Name, pattern: re, luhn: luhn, strategy: d.Strategy})
+	}
+
+	return m, nil
+}
+
+func validateMaskStrategy(strategy string) error {
+	switch strategy {
+	case MaskRedact, MaskPartial, MaskHash, MaskNull:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidMaskStrategy, strategy)
+	}
+}
+
+// defaultMaskingKey returns a random hash key, used when none is configured:
+// hashes then only correlate within the running server
+func defaultMaskingKey() []byte {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		log.Printf("Masking: could not generate a hash key: %v\n", err)
+	}
+	return key
+}
+
+// matches reports whether the rule matches a column of a table
+func (r maskingColumnRule) matches(source ColumnSource) bool {
+	names := []string{source.Schema, source.Table, source.Column}
+	offset := len(names) - len(r.segments)
+	for i, segment := range r.segments {
+		name := names[offset+i]
+		if name == "" || name == "*" {
+			continue
+		}
+		if ok, _ := path.Match(segment, strings.ToLower(name)); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// columnStrategy returns the strategy of the first column rule matching a result column.
+// Without sources, only the column segment of the rules is matched against its name.
+func (m *Masker) columnStrategy(column string, sources []ColumnSource) string {
+	for _, rule := range m.columns {
+		if len(sources) == 0 || len(rule.segments) == 1 {
+			if ok, _ := path.Match(rule.segments[len(rule.segments)-1], strings.ToLower(column)); ok {
+				return rule.strategy
+			}
+		}
+		for _, source := range sources {
+			if rule.matches(source) {
+				return rule.strategy
+			}
+		}
+	}
+	return ""
+}
+
+// RowMask masks the rows of one result as they are read, before the result budget
+// cuts long cells that the detectors would no longer recognise
+type RowMask struct {
+	masker     *Masker
+	columns    []string
+	strategies map[string]string
+	masked     map[string]bool
+}
+
+// NewRowMask resolves the column strategies of a result.
+// sources maps result columns to the table columns they may come from.
+func (m *Masker) NewRowMask(columns []string, sources map[string][]ColumnSource) *RowMask {
+	strategies := make(map[string]string, len(columns))
+	for _, col := range columns {
+		strategies[col] = m.columnStrategy(col, sources[col])
+	}
+	return &RowMask{masker: m, columns: columns, strategies: strategies, masked: make(map[string]bool)}
+}
+
+// Mask masks a row in place. A nil RowMask masks nothing.
+func (r *RowMask) Mask(row map[string]interface{}) {
+	if r == nil {
+		return
+	}
+	for _, col := range r.columns {
+		value := row[col]
+		if value == nil {
+			continue
+		}
+		if strategy := r.strategies[col]; strategy != "" {
+			row[col] = r.masker.maskValue(fmt.Sprint(value), strategy)
+			r.masked[col] = true
+			continue
+		}
+		if text, ok := value.(string); ok && len(r.masker.detectors) > 0 {
+			if result, changed := r.masker.detect(text); changed {
+				row[col] = result
+				r.masked[col] = true
+			}
+		}
+	}
+}
+
+// Columns returns the masked columns in result order
+func (r *RowMask) Columns() []string {
+	if r == nil {
+		return nil
+	}
+	var maskedColumns []string
+	for _, col := range r.columns {
+		if r.masked[col] {
+			maskedColumns = append(maskedColumns, col)
+		}
+	}
+	return maskedColumns
+}
+
+// MaskRows masks rows in place and returns the masked columns in result order.
+// sources maps result columns to the table columns they may come from.
+func (m *Masker) MaskRows(columns []string, sources map[string][]ColumnSource, rows []map[string]interface{}) []string {
+	mask := m.NewRowMask(columns, sources)
+	for _, row := range rows {
+		mask.Mask(row)
+	}
+	return mask.Columns()
+}
+
+// detect masks the parts of text matched by the detectors. A match of a detector
+// with the null strategy nulls the whole value.
+func (m *Masker) detect(text string) (interface{}, bool) {
+	changed := false
+	for _, d := range m.detectors {
+		nulled := false
+		text = d.pattern.ReplaceAllStringFunc(text, func(match string) string {
+			if d.luhn && !luhnValid(match) {
+				return match
+			}
+			changed = true
+			if d.strategy == MaskNull {
+				nulled = true
+				return match
+			}
+			return m.maskValue(match, d.strategy).(string)
+		})
+		if nulled {
+			return nil, true
+		}
+	}
+	return text, changed
+}
+
+// maskValue applies a strategy to a value
+func (m *Masker) maskValue(value, strategy string) interface{} {
+	switch strategy {
+	case MaskPartial:
+		runes := []rune(value)
+		if len(runes) <= MaskPartialKeep {
+			return strings.Repeat("*", len(runes))
+		}
+		return strings.Repeat("*", len(runes)-MaskPartialKeep) + string(runes[len(runes)-MaskPartialKeep:])
+	case MaskHash:
+		mac := hmac.New(sha256.New, m.key)
+		mac.Write([]byte(value))
+		return "hash:" + hex.EncodeToString(mac.Sum(nil)[:MaskHashBytes])
+	case MaskNull:
+		return nil
+	default:
+		return MaskRedactedValue
+	}
+}
+
+// luhnValid reports whether the digits of value pass the Luhn checksum
+func luhnValid(value string) bool {
+	sum, count := 0, 0
+	for i := len(value) - 1; i >= 0; i-- {
+		c := value[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		digit := int(c - '0')
+		if count%2 == 1 {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		count++
+	}
+	return count > 0 && sum%10 == 0
+}
+
+// masker returns the masking stage of the active datasource
+func (s *DbMCPServer) masker() *Masker {
+	return s.config.Masker(s.profile)
+}
+
+// maskRows applies the masking stage of the active datasource to result rows and
+// returns the masked columns
+func (s *DbMCPServer) maskRows(ctx context.Context, columns []string, sources map[string][]ColumnSource, rows []map[string]interface{}) []string {
+	mask := s.rowMask(ctx, columns, sources)
+	for _, row := range rows {
+		mask.Mask(row)
+	}
+	return mask.Columns()
+}
+
+// rowMask returns the row mask of a result for the active datasource, nil without
+// masking rules. Sources without schema are taken from the current schema.
+func (s *DbMCPServer) rowMask(ctx context.Context, columns []string, sources map[string][]ColumnSource) *RowMask {
+	masker := s.masker()
+	if masker == nil {
+		return nil
+	}
+
+	current := ""
+	resolved := false
+	for _, list := range sources {
+		for i := range list {
+			if list[i].Schema != "" || list[i].Table == "" {
+				continue
+			}
+			if !resolved {
+				current, _ = s.currentSchema(ctx)
+				resolved = true
+			}
+			list[i].Schema = current
+		}
+	}
+
+	return masker.NewRowMask(columns, sources)
+}
+
+// tableMaskSources maps the columns of a table to themselves
+func tableMaskSources(schema, table string, columns []string) map[string][]ColumnSource {
+	sources := make(map[string][]ColumnSource, len(columns))
+	for _, col := range columns {
+		sources[col] = []ColumnSource{{Schema: schema, Table: table, Column: col}}
+	}
+	return sources
+}
+
+// termMaskSources maps the output columns of a built query to the table columns they read.
+// resolve returns the table of a column qualifier. Counts are not sources.
+func termMaskSources(resolve func(qualifier string) (schema, table string), projection []SelectTerm, groupBy []GroupByTerm, aggregates []AggregateTerm) map[string][]ColumnSource {
+	sources := make(map[string][]ColumnSource)
+	add := func(alias, qualifier, column string) {
+		schema, table := resolve(qualifier)
+		sources[alias] = append(sources[alias], ColumnSource{Schema: schema, Table: table, Column: column})
+	}
+	for _, t := range projection {
+		add(t.Alias, t.Qualifier, t.Column)
+	}
+	for _, g := range groupBy {
+		add(g.Alias, g.Qualifier, g.Column)
+	}
+	for _, a := range aggregates {
+		if a.Column != "" && a.Function != "count" && a.Function != "count_distinct" {
+			add(a.Alias, a.Qualifier, a.Column)
+		}
+	}
+	return sources
+}
+
+// queryMaskSources maps the result columns of a SQL query to the table columns read by
+// the select list items they come from, matched by position or, when the select list
+// has a star, by name. Result columns whose origin cannot be resolved (derived tables,
+// whole-row references, unparsable queries) are mapped to any column of the tables the
+// query reads, so that the column rules covering those tables mask them. A query whose
+// string literals read differently with backslash escapes gets the sources of both readings.
+func queryMaskSources(query string, columns []string) map[string][]ColumnSource {
+	readings, err := parseSQLReadings(query)
+	if err != nil {
+		readings = []*SQLReferences{nil}
+	}
+	sources := make(map[string][]ColumnSource, len(columns))
+	for _, refs := range readings {
+		for col, list := range readingMaskSources(refs, columns) {
+			sources[col] = append(sources[col], list...)
+		}
+	}
+	return sources
+}
+
+// readingMaskSources maps the result columns to their sources in one reading of a query,
+// nil when the query could not be parsed
+func readingMaskSources(refs *SQLReferences, columns []string) map[string][]ColumnSource {
+	sources := make(map[string][]ColumnSource, len(columns))
+	if refs == nil || len(refs.Items) == 0 {
+		for _, col := range columns {
+			sources[col] = []ColumnSource{{Column: "*"}}
+		}
+		return sources
+	}
+
+	anyColumn := []ColumnSource{{Column: "*"}}
+	if len(refs.Tables) > 0 {
+		anyColumn = nil
+		for _, t := range refs.Tables {
+			anyColumn = append(anyColumn, ColumnSource{Schema: t.Schema, Table: t.Name, Column: "*"})
+		}
+	}
+	// Columns of derived tables and CTEs are not traced back to their tables
+	derived := len(refs.derived) > 0
+
+	table := func(qualifier string) (TableReference, bool) {
+		if refs.IsDerived(qualifier) {
+			return TableReference{}, false
+		}
+		for _, t := range refs.Tables {
+			if strings.EqualFold(t.Alias, qualifier) || (t.Alias == "" && strings.EqualFold(t.Name, qualifier)) {
+				return t, true
+			}
+		}
+		return TableReference{}, false
+	}
+
+	// columnSources returns the table columns a column reference may read; column is
+	// the result column a star stands for
+	columnSources := func(ref ColumnReference, column string) []ColumnSource {
+		star := ref.Column == "*"
+		if star {
+			ref.Column = column
+		}
+		switch {
+		case ref.Schema != "":
+			return []ColumnSource{{Schema: ref.Schema, Table: ref.Qualifier, Column: ref.Column}}
+		case ref.Qualifier != "":
+			if t, ok := table(ref.Qualifier); ok {
+				return []ColumnSource{{Schema: t.Schema, Table: t.Name, Column: ref.Column}}
+			}
+			return anyColumn
+		case derived:
+			return anyColumn
+		}
+		if t, ok := table(ref.Column); ok && !star {
+			// A bare table name or alias reads the whole row
+			return []ColumnSource{{Schema: t.Schema, Table: t.Name, Column: "*"}}
+		}
+		if len(refs.Tables) == 0 {
+			return anyColumn
+		}
+		var list []ColumnSource
+		for _, t := range refs.Tables {
+			list = append(list, ColumnSource{Schema: t.Schema, Table: t.Name, Column: ref.Column})
+		}
+		return list
+	}
+
+	itemSources := func(item SelectItem, column string) []ColumnSource {
+		var list []ColumnSource
+		for _, ref := range item.Columns {
+			list = append(list, columnSources(ref, column)...)
+		}
+		return list
+	}
+	isStar := func(item SelectItem) bool {
+		return item.Plain && item.Columns[0].Column == "*"
+	}
+
+	// Match the items by position when every SELECT of the query lists one per column
+	byPosition := true
+	counts := make(map[int]int)
+	for _, item := range refs.Items {
+		counts[item.Branch]++
+		if isStar(item) {
+			byPosition = false
+		}
+	}
+	for _, count := range counts {
+		if count != len(columns) {
+			byPosition = false
+		}
+	}
+
+	if byPosition {
+		position := make(map[int]int)
+		for _, item := range refs.Items {
+			col := columns[position[item.Branch]]
+			position[item.Branch]++
+			sources[col] = append(sources[col], itemSources(item, col)...)
+		}
+		return sources
+	}
+
+	// Otherwise match them by name, which only a single SELECT allows
+	for _, col := range columns {
+		matched := false
+		if len(counts) == 1 {
+			for _, item := range refs.Items {
+				named := strings.EqualFold(item.Alias, col) ||
+					(item.Alias == "" && item.Plain && strings.EqualFold(item.Columns[0].Column, col))
+				if named || isStar(item) {
+					sources[col] = append(sources[col], itemSources(item, col)...)
+					matched = true
+				}
+			}
+		}
+		if !matched {
+			sources[col] = anyColumn
+		}
+	}
+	return sources
+}
@@ -0,0 +1,80 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestQueryMaskSources(t *testing.T) {
+	masker, err := NewMasker(&MaskingConfig{Columns: []MaskingColumnConfig{
+		{Match: "people.ssn", Strategy: MaskRedact},
+	}}, []byte("key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		query   string
+		columns []string
+		masked  []string
+	}{
+		{"plain column", "SELECT ssn, name FROM people", []string{"ssn", "name"}, []string{"ssn"}},
+		{"alias", "SELECT ssn AS x, name FROM people", []string{"x", "name"}, []string{"x"}},
+		{"function", "SELECT upper(ssn), name FROM people", []string{"upper(ssn)", "name"}, []string{"upper(ssn)"}},
+		{"concatenation", "SELECT ssn || '' AS v, name FROM people", []string{"v", "name"}, []string{"v"}},
+		{"qualified alias", "SELECT p.ssn s FROM people p", []string{"s"}, []string{"s"}},
+		{"star", "SELECT * FROM people", []string{"id", "ssn", "name"}, []string{"ssn"}},
+		{"star and expression", "SELECT *, lower(ssn) AS l FROM people", []string{"id", "ssn", "l"}, []string{"ssn", "l"}},
+		{"whole row", "SELECT p FROM people p", []string{"p"}, []string{"p"}},
+		{"derived table", "SELECT * FROM (SELECT ssn AS x FROM people) t", []string{"x"}, []string{"x"}},
+		{"union", "SELECT name FROM people UNION SELECT ssn FROM people", []string{"name"}, []string{"name"}},
+		{"other table", "SELECT ssn FROM orders", []string{"ssn"}, nil},
+		{"join", "SELECT o.total, p.name FROM orders o JOIN people p ON p.id = o.person_id", []string{"total", "name"}, nil},
+		{"backslash escaped quote", `SELECT 'x\' AS n, ssn FROM people -- '`, []string{"n", "ssn"}, []string{"n", "ssn"}},
+		{"literal", "SELECT 1 AS one, name FROM people", []string{"one", "name"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			row := make(map[string]interface{}, len(tt.columns))
+			for _, col := range tt.columns {
+				row[col] = "value"
+			}
+			masked := masker.MaskRows(tt.columns, queryMaskSources(tt.query, tt.columns), []map[string]interface{}{row})
+			if len(masked) != len(tt.masked) {
+				t.Fatalf("MaskRows(%q) masked %v, want %v", tt.query, masked, tt.masked)
+			}
+			for i := range masked {
+				if masked[i] != tt.masked[i] {
+					t.Fatalf("MaskRows(%q) masked %v, want %v", tt.query, masked, tt.masked)
+				}
+			}
+		})
+	}
+}
+
+func TestRowMaskBeforeBudget(t *testing.T) {
+	masker, err := NewMasker(&MaskingConfig{Detectors: []MaskingDetectorConfig{
+		{Name: "email", Strategy: MaskRedact},
+	}}, []byte("key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	columns := []string{"note"}
+	row := map[string]interface{}{"note": strings.Repeat("x", 20) + " contact: someone@example.com"}
+	mask := masker.NewRowMask(columns, nil)
+	budget := &ResultBudget{MaxBytes: 1024, MaxCellBytes: 40, limit: "max_bytes"}
+
+	mask.Mask(row)
+	if !budget.Add(columns, row, 0) {
+		t.Fatal("row does not fit in the budget")
+	}
+	if note := row["note"].(string); strings.Contains(note, "someone@") {
+		t.Fatalf("truncated cell %q still holds part of the email", note)
+	}
+	if masked := mask.Columns(); len(masked) != 1 || masked[0] != "note" {
+		t.Fatalf("masked columns = %v, want [note]", masked)
+	}
+}
@@ -0,0 +1,424 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+)
+
+// policyRule is a "schema.table.column" glob rule split into its segments
+type policyRule [3]string
+
+// Policy decides which schemas, tables and columns of a datasource are visible.
+// A nil or empty policy allows everything.
+type Policy struct {
+	allow []policyRule
+	deny  []policyRule
+}
+
+// NewPolicy parses allow and deny rules. Missing trailing segments match anything:
+// "payroll" covers a schema, "*.audit_*" a set of tables and "*.users.password_hash" a column.
+func NewPolicy(allow, deny []string) (*Policy, error) {
+	policy := &Policy{}
+	for _, rule := range allow {
+		parsed, err := parsePolicyRule(rule)
+		if err != nil {
+			return nil, err
+		}
+		policy.allow = append(policy.allow, parsed)
+	}
+	for _, rule := range deny {
+		parsed, err := parsePolicyRule(rule)
+		if err != nil {
+			return nil, err
+		}
+		policy.deny = append(policy.deny, parsed)
+	}
+	return policy, nil
+}
+
+func parsePolicyRule(rule string) (policyRule, error) {
+	segments := strings.Split(strings.ToLower(strings.TrimSpace(rule)), ".")
+	if len(segments) > 3 {
+		return policyRule{}, fmt.Errorf("%w: %q has more than 3 segments", ErrInvalidPolicyRule, rule)
+	}
+
+	parsed := policyRule{"*", "*", "*"}
+	for i, segment := range segments {
+		if segment == "" {
+			return policyRule{}, fmt.Errorf("%w: %q has an empty segment", ErrInvalidPolicyRule, rule)
+		}
+		if _, err := path.Match(segment, ""); err != nil {
+			return policyRule{}, fmt.Errorf("%w: %q: %v", ErrInvalidPolicyRule, rule, err)
+		}
+		parsed[i] = segment
+	}
+	return parsed, nil
+}
+
+// matches reports whether the leading segments of the rule match names (case-insensitive)
+func (r policyRule) matches(names ...string) bool {
+	for i, name := range names {
+		if ok, _ := path.Match(r[i], strings.ToLower(name)); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// IsEmpty reports whether the policy has no rules
+func (p *Policy) IsEmpty() bool {
+	return p == nil || (len(p.allow) == 0 && len(p.deny) == 0)
+}
+
+// TableAllowed reports whether a table is visible. Views, routines and other schema
+// objects are matched the same way, their name standing for the table segment.
+// A table is hidden by deny rules covering all its columns, and by allow rules
+// when none of them matches it.
+func (p *Policy) TableAllowed(schema, table string) bool {
+	if p.IsEmpty() {
+		return true
+	}
+	for _, rule := range p.deny {
+		if rule[2] == "*" && rule.matches(schema, table) {
+			return false
+		}
+	}
+	if len(p.allow) == 0 {
+		return true
+	}
+	for _, rule := range p.allow {
+		if rule.matches(schema, table) {
+			return true
+		}
+	}
+	return false
+}
+
+// ColumnAllowed reports whether a column is visible
+func (p *Policy) ColumnAllowed(schema, table, column string) bool {
+	if p.IsEmpty() {
+		return true
+	}
+	if !p.TableAllowed(schema, table) {
+		return false
+	}
+	for _, rule := range p.deny {
+		if rule.matches(schema, table, column) {
+			return false
+		}
+	}
+	if len(p.allow) == 0 {
+		return true
+	}
+	for _, rule := range p.allow {
+		if rule.matches(schema, table, column) {
+			return true
+		}
+	}
+	return false
+}
+
+// policy returns the access policy of the active datasource
+func (s *DbMCPServer) policy() *Policy {
+	return s.config.Policy(s.profile)
+}
+
+// policyScope returns the active policy and the schema its rules are matched against,
+// which is the current schema of the connection when schema is empty.
+// ok is false when that schema cannot be determined.
+func (s *DbMCPServer) policyScope(ctx context.Context, schema string) (*Policy, string, bool) {
+	policy := s.policy()
+	if policy.IsEmpty() || schema != "" {
+		return policy, schema, true
+	}
+
+	current, err := s.currentSchema(ctx)
+	if err != nil {
+		log.Printf("Policy: could not determine the current schema: %v\n", err)
+		return policy, "", false
+	}
+	return policy, current, true
+}
+
+func (s *DbMCPServer) currentSchema(ctx context.Context) (string, error) {
+	var schema sql.NullString
+	if err := s.db.QueryRowContext(ctx, s.queryBuilder.CurrentSchemaQuery()).Scan(&schema); err != nil {
+		return "", err
+	}
+	return schema.String, nil
+}
+
+// objectVisible reports whether the policy allows a table, view, routine or trigger.
+// Objects are hidden when their schema cannot be determined.
+func (s *DbMCPServer) objectVisible(ctx context.Context, schema, name string) bool {
+	policy, schema, ok := s.policyScope(ctx, schema)
+	return ok && policy.TableAllowed(schema, name)
+}
+
+// visibleColumnMaps removes the columns hidden by the policy from a column listing,
+// matching the column name under key
+func (s *DbMCPServer) visibleColumnMaps(ctx context.Context, schema, table string, columns []map[string]interface{}, key string) []map[string]interface{} {
+	policy, schema, ok := s.policyScope(ctx, schema)
+	if policy.IsEmpty() {
+		return columns
+	}
+
+	var visible []map[string]interface{}
+	for _, col := range columns {
+		name, isString := col[key].(string)
+		if ok && (!isString || policy.ColumnAllowed(schema, table, name)) {
+			visible = append(visible, col)
+		}
+	}
+	return visible
+}
+
+// visibleColumnNames removes the columns hidden by the policy from a list of names
+func (s *DbMCPServer) visibleColumnNames(ctx context.Context, schema, table string, columns []string) []string {
+	policy, schema, ok := s.policyScope(ctx, schema)
+	if policy.IsEmpty() {
+		return columns
+	}
+
+	var visible []string
+	for _, col := range columns {
+		if ok && policy.ColumnAllowed(schema, table, col) {
+			visible = append(visible, col)
+		}
+	}
+	return visible
+}
+
+// visibleIndexes removes the indexes with a key column hidden by the policy
+func (s *DbMCPServer) visibleIndexes(ctx context.Context, schema, table string, indexes []TableIndex) []TableIndex {
+	policy, schema, ok := s.policyScope(ctx, schema)
+	if policy.IsEmpty() {
+		return indexes
+	}
+
+	var visible []TableIndex
+	for _, index := range indexes {
+		if ok && columnsAllowed(policy, schema, table, index.Columns) {
+			visible = append(visible, index)
+		}
+	}
+	return visible
+}
+
+// visibleForeignKeys removes the foreign keys with a column or referenced column hidden by the policy
+func (s *DbMCPServer) visibleForeignKeys(ctx context.Context, schema, table string, foreignKeys []ForeignKey) []ForeignKey {
+	policy, schema, ok := s.policyScope(ctx, schema)
+	if policy.IsEmpty() {
+		return foreignKeys
+	}
+
+	var visible []ForeignKey
+	for _, fk := range foreignKeys {
+		refSchema := fk.RefSchema
+		if refSchema == "" {
+			refSchema = schema
+		}
+		if ok && columnsAllowed(policy, schema, table, fk.Columns) && columnsAllowed(policy, refSchema, fk.RefTable, fk.RefColumns) {
+			visible = append(visible, fk)
+		}
+	}
+	return visible
+}
+
+// columnsAllowed reports whether the policy allows every one of the columns of a table
+func columnsAllowed(policy *Policy, schema, table string, columns []string) bool {
+	for _, column := range columns {
+		if !policy.ColumnAllowed(schema, table, column) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkQueryPolicy rejects a query that references tables or columns hidden by the policy.
+// Unqualified and star references are resolved against the columns of the referenced tables;
+// a bare table name or alias reads all its columns. Names that resolve to nothing are
+// rejected, so that a reference the parser misreads cannot bypass the policy. A query
+// whose string literals read differently with backslash escapes is checked both ways.
+func (s *DbMCPServer) checkQueryPolicy(ctx context.Context, query string) error {
+	policy := s.policy()
+	if policy.IsEmpty() {
+		return nil
+	}
+
+	readings, err := parseSQLReadings(query)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCheckingPolicy, err)
+	}
+
+	current, err := s.currentSchema(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrCheckingPolicy, err)
+	}
+
+	for _, refs := range readings {
+		if err := s.checkReferences(ctx, policy, current, refs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkReferences checks one reading of a query against the policy
+func (s *DbMCPServer) checkReferences(ctx context.Context, policy *Policy, current string, refs *SQLReferences) error {
+	type policyTable struct{ schema, name string }
+
+	var tables []policyTable
+	byName := make(map[string]policyTable)
+	for _, ref := range refs.Tables {
+		table := policyTable{schema: ref.Schema, name: ref.Name}
+		if table.schema == "" {
+			table.schema = current
+		}
+		if !policy.TableAllowed(table.schema, table.name) {
+			return fmt.Errorf("%w: %s.%s", ErrObjectNotAllowed, table.schema, table.name)
+		}
+		tables = append(tables, table)
+		byName[strings.ToLower(ref.Name)] = table
+		if ref.Alias != "" {
+			byName[strings.ToLower(ref.Alias)] = table
+		}
+	}
+
+	columnCache := make(map[policyTable][]TableColumn)
+	tableColumns := func(table policyTable) ([]TableColumn, error) {
+		if columns, ok := columnCache[table]; ok {
+			return columns, nil
+		}
+		columns, err := s.fetchTableColumns(ctx, table.schema, table.name)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrCheckingPolicy, err)
+		}
+		columnCache[table] = columns
+		return columns, nil
+	}
+
+	// checkColumn verifies a column of a table, or all its columns for "*", and reports
+	// whether the table has the column. With mustExist false, the column is checked by
+	// name without listing the columns of the table.
+	checkColumn := func(table policyTable, column string, mustExist bool) (bool, error) {
+		if column != "*" && !mustExist {
+			if !policy.ColumnAllowed(table.schema, table.name, column) {
+				return true, fmt.Errorf("%w: %s.%s.%s", ErrObjectNotAllowed, table.schema, table.name, column)
+			}
+			return true, nil
+		}
+
+		columns, err := tableColumns(table)
+		if err != nil {
+			return false, err
+		}
+		found := column == "*"
+		for _, col := range columns {
+			if column != "*" && !strings.EqualFold(col.Name, column) {
+				continue
+			}
+			found = true
+			if !policy.ColumnAllowed(table.schema, table.name, col.Name) {
+				if column == "*" {
+					return true, fmt.Errorf("%w: %s.%s has hidden columns, select the visible columns explicitly",
+						ErrObjectNotAllowed, table.schema, table.name)
+				}
+				return true, fmt.Errorf("%w: %s.%s.%s", ErrObjectNotAllowed, table.schema, table.name, col.Name)
+			}
+		}
+		return found, nil
+	}
+
+	for _, ref := range refs.Columns {
+		switch {
+		case ref.Schema != "":
+			table := policyTable{schema: ref.Schema, name: ref.Qualifier}
+			if !policy.TableAllowed(table.schema, table.name) {
+				return fmt.Errorf("%w: %s.%s", ErrObjectNotAllowed, table.schema, table.name)
+			}
+			if _, err := checkColumn(table, ref.Column, false); err != nil {
+				return err
+			}
+		case ref.Qualifier != "":
+			if refs.IsDerived(ref.Qualifier) {
+				// Derived tables and CTEs are checked through their own references
+				continue
+			}
+			table, ok := byName[strings.ToLower(ref.Qualifier)]
+			if !ok {
+				if !strings.EqualFold(ref.Column, "NEXTVAL") && !strings.EqualFold(ref.Column, "CURRVAL") {
+					return fmt.Errorf("%w: %s.%s", ErrUnresolvedReference, ref.Qualifier, ref.Column)
+				}
+				// Oracle sequence pseudo-column
+				if !policy.TableAllowed(current, ref.Qualifier) {
+					return fmt.Errorf("%w: %s.%s", ErrObjectNotAllowed, current, ref.Qualifier)
+				}
+				continue
+			}
+			if _, err := checkColumn(table, ref.Column, false); err != nil {
+				return err
+			}
+		default:
+			// Unqualified: check every referenced table that has the column
+			resolved := false
+			for _, table := range tables {
+				found, err := checkColumn(table, ref.Column, true)
+				if err != nil {
+					return err
+				}
+				resolved = resolved || found
+			}
+			if ref.Column == "*" {
+				continue
+			}
+			if table, ok := byName[strings.ToLower(ref.Column)]; ok {
+				// A bare table name or alias stands for the whole row
+				if _, err := checkColumn(table, "*", true); err != nil {
+					return err
+				}
+				resolved = true
+			}
+			if !resolved && !refs.IsDerived(ref.Column) && !refs.IsAlias(ref.Column) && !sqlBuiltinWords[strings.ToUpper(ref.Column)] {
+				return fmt.Errorf("%w: %s", ErrUnresolvedReference, ref.Column)
+			}
+		}
+	}
+
+	if refs.HasNaturalJoin() {
+		// A natural join compares the columns the joined tables have in common, which
+		// are unknown when a derived table or CTE takes part in it
+		for i, table := range tables {
+			if len(refs.derived) > 0 {
+				if _, err := checkColumn(table, "*", true); err != nil {
+					return err
+				}
+				continue
+			}
+			columns, err := tableColumns(table)
+			if err != nil {
+				return err
+			}
+			for j, other := range tables {
+				if i == j {
+					continue
+				}
+				for _, col := range columns {
+					found, err := checkColumn(other, col.Name, true)
+					if err != nil {
+						return err
+					}
+					if found && !policy.ColumnAllowed(table.schema, table.name, col.Name) {
+						return fmt.Errorf("%w: %s.%s.%s", ErrObjectNotAllowed, table.schema, table.name, col.Name)
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -34,6 +34,26 @@ func (qb *QueryBuilder) LikeOperator(caseSensitive bool) string {
 	return qb.dialect.LikeOperator(caseSensitive)
 }
 
+// LikeExpression returns a pattern match expression with the requested case sensitivity
+func (qb *QueryBuilder) LikeExpression(column, placeholder string, caseSensitive bool) string {
+	return qb.dialect.LikeExpression(column, placeholder, caseSensitive)
+}
+
+// LikePattern converts a LIKE pattern to the value bound for LikeExpression
+func (qb *QueryBuilder) LikePattern(pattern string, caseSensitive bool) string {
+	return qb.dialect.LikePattern(pattern, caseSensitive)
+}
+
+// IsLargeObjectType reports whether a column type is a LOB for the driver
+func (qb *QueryBuilder) IsLargeObjectType(dataType string, maxLength int64) bool {
+	return qb.dialect.IsLargeObjectType(dataType, maxLength)
+}
+
+// DateTrunc truncates a date/time expression to a day, week or month bucket
+func (qb *QueryBuilder) DateTrunc(column, unit string) string {
+	return qb.dialect.DateTrunc(column, unit)
+}
+
 // Concat returns the concat operator for the driver
 func (qb *QueryBuilder) Concat(parts ...string) string {
 	return qb.dialect.ConcatOperator(parts...)
@@ -44,6 +64,11 @@ func (qb *QueryBuilder) CurrentDatabase() string {
 	return qb.dialect.CurrentDatabase()
 }
 
+// CurrentSchemaQuery returns the query for the current schema name
+func (qb *QueryBuilder) CurrentSchemaQuery() string {
+	return qb.dialect.CurrentSchemaQuery()
+}
+
 // QuoteIdentifier returns the properly quoted identifier for the driver
 func (qb *QueryBuilder) QuoteIdentifier(name string) string {
 	return qb.dialect.QuoteIdentifier(name)
@@ -270,6 +295,48 @@ func (qb *QueryBuilder) GetForeignKeysQuery(schema, tableName string) (string, [
 	}
 }
 
+// GetCheckConstraintsQuery returns query to get the check constraints of a table
+func (qb *QueryBuilder) GetCheckConstraintsQuery(schema, tableName string) (string, []interface{}) {
+	return qb.tableConstraintQuery(qb.dialect.TableMetadata().GetCheckConstraints, schema, tableName)
+}
+
+// GetUniqueConstraintsQuery returns query to get the unique constraints of a table
+func (qb *QueryBuilder) GetUniqueConstraintsQuery(schema, tableName string) (string, []interface{}) {
+	return qb.tableConstraintQuery(qb.dialect.TableMetadata().GetUniqueConstraints, schema, tableName)
+}
+
+// GetDefaultConstraintsQuery returns query to get the named default constraints of a
+// table, or "" when the database has none
+func (qb *QueryBuilder) GetDefaultConstraintsQuery(schema, tableName string) (string, []interface{}) {
+	return qb.tableConstraintQuery(qb.dialect.TableMetadata().GetDefaultConstraints, schema, tableName)
+}
+
+// GetExclusionConstraintsQuery returns query to get the exclusion constraints of a
+// table, or "" when the database has none
+func (qb *QueryBuilder) GetExclusionConstraintsQuery(schema, tableName string) (string, []interface{}) {
+	return qb.tableConstraintQuery(qb.dialect.TableMetadata().GetExclusionConstraints, schema, tableName)
+}
+
+// GetTableCommentQuery returns query to get the description of a table, or "" when
+// the database keeps none
+func (qb *QueryBuilder) GetTableCommentQuery(schema, tableName string) (string, []interface{}) {
+	return qb.tableConstraintQuery(qb.dialect.TableMetadata().GetTableComment, schema, tableName)
+}
+
+// tableConstraintQuery binds the schema and table of a constraint query
+func (qb *QueryBuilder) tableConstraintQuery(query, schema, tableName string) (string, []interface{}) {
+	if query == "" {
+		return "", nil
+	}
+	if qb.driver == DriverSQLite {
+		return query, []interface{}{tableName}
+	}
+	return query, []interface{}{
+		qb.dialect.NormalizeIdentifier(schema),
+		qb.dialect.NormalizeIdentifier(tableName),
+	}
+}
+
 // -----------------------------------------------------------------------------
 // Procedure Queries
 // -----------------------------------------------------------------------------
@@ -314,6 +381,30 @@ func (qb *QueryBuilder) GetProcedureCodeQuery(schema, procedureName string) (str
 	}
 }
 
+// GetProcedureArgumentsQuery returns the query to get the arguments of the overloads
+// of a procedure; packageName is only used on Oracle
+func (qb *QueryBuilder) GetProcedureArgumentsQuery(schema, packageName, procedureName string) (string, []interface{}) {
+	return qb.routineArgumentsQuery(qb.dialect.ProcedureMetadata().GetArguments, schema, packageName, procedureName)
+}
+
+// ListProcedureArgumentsQuery returns the query to get the arguments of every
+// procedure of a schema, or "" when the database has no procedures
+func (qb *QueryBuilder) ListProcedureArgumentsQuery(schema string) (string, []interface{}) {
+	return qb.schemaArgumentsQuery(qb.dialect.ProcedureMetadata().ListArguments, schema)
+}
+
+// GetProcedureOverloadCodeQuery returns the query to get the source code of one overload
+// of a procedure, or "" when the database has no overloading
+func (qb *QueryBuilder) GetProcedureOverloadCodeQuery(schema, procedureName, specificName string) (string, []interface{}) {
+	return qb.routineOverloadCodeQuery(qb.dialect.ProcedureMetadata().GetOverloadCode, schema, procedureName, specificName)
+}
+
+// GetProcedurePackageCodeQuery returns the query to get the body of the package of a
+// procedure, or "" when the database has no packages
+func (qb *QueryBuilder) GetProcedurePackageCodeQuery(schema, packageName string) (string, []interface{}) {
+	return qb.packageCodeQuery(qb.dialect.ProcedureMetadata().GetPackageCode, schema, packageName)
+}
+
 // -----------------------------------------------------------------------------
 // Function Queries
 // -----------------------------------------------------------------------------
@@ -369,6 +460,77 @@ func (qb *QueryBuilder) GetFunctionCodeQuery(schema, functionName string) (strin
 	}
 }
 
+// GetFunctionArgumentsQuery returns the query to get the arguments and return type of
+// the overloads of a function; packageName is only used on Oracle
+func (qb *QueryBuilder) GetFunctionArgumentsQuery(schema, packageName, functionName string) (string, []interface{}) {
+	return qb.routineArgumentsQuery(qb.dialect.FunctionMetadata().GetArguments, schema, packageName, functionName)
+}
+
+// ListFunctionArgumentsQuery returns the query to get the arguments and return type
+// of every function of a schema, or "" when the database has no functions
+func (qb *QueryBuilder) ListFunctionArgumentsQuery(schema string) (string, []interface{}) {
+	return qb.schemaArgumentsQuery(qb.dialect.FunctionMetadata().ListArguments, schema)
+}
+
+// GetFunctionOverloadCodeQuery returns the query to get the source code of one overload
+// of a function, or "" when the database has no overloading
+func (qb *QueryBuilder) GetFunctionOverloadCodeQuery(schema, functionName, specificName string) (string, []interface{}) {
+	return qb.routineOverloadCodeQuery(qb.dialect.FunctionMetadata().GetOverloadCode, schema, functionName, specificName)
+}
+
+// GetFunctionPackageCodeQuery returns the query to get the body of the package of a
+// function, or "" when the database has no packages
+func (qb *QueryBuilder) GetFunctionPackageCodeQuery(schema, packageName string) (string, []interface{}) {
+	return qb.packageCodeQuery(qb.dialect.FunctionMetadata().GetPackageCode, schema, packageName)
+}
+
+// routineArgumentsQuery binds the arguments of a GetArguments query
+func (qb *QueryBuilder) routineArgumentsQuery(query, schema, packageName, name string) (string, []interface{}) {
+	if query == "" {
+		return "", nil
+	}
+
+	args := []interface{}{qb.dialect.NormalizeIdentifier(schema)}
+	if qb.driver == DriverOracle {
+		args = append(args, qb.dialect.NormalizeIdentifier(packageName))
+	}
+	return query, append(args, qb.dialect.NormalizeIdentifier(name))
+}
+
+// schemaArgumentsQuery binds the argument of a ListArguments query
+func (qb *QueryBuilder) schemaArgumentsQuery(query, schema string) (string, []interface{}) {
+	if query == "" {
+		return "", nil
+	}
+
+	return query, []interface{}{qb.dialect.NormalizeIdentifier(schema)}
+}
+
+// routineOverloadCodeQuery binds the arguments of a GetOverloadCode query
+func (qb *QueryBuilder) routineOverloadCodeQuery(query, schema, name, specificName string) (string, []interface{}) {
+	if query == "" {
+		return "", nil
+	}
+
+	return query, []interface{}{
+		qb.dialect.NormalizeIdentifier(schema),
+		qb.dialect.NormalizeIdentifier(name),
+		specificName,
+	}
+}
+
+// packageCodeQuery binds the arguments of a GetPackageCode query
+func (qb *QueryBuilder) packageCodeQuery(query, schema, packageName string) (string, []interface{}) {
+	if query == "" {
+		return "", nil
+	}
+
+	return query, []interface{}{
+		qb.dialect.NormalizeIdentifier(schema),
+		qb.dialect.NormalizeIdentifier(packageName),
+	}
+}
+
 // -----------------------------------------------------------------------------
 // View Queries
 // -----------------------------------------------------------------------------
@@ -410,6 +572,96 @@ func (qb *QueryBuilder) GetViewDefinitionQuery(schema, viewName string) (string,
 	}
 }
 
+// GetMaterializedViewQuery returns the query to get the definition and last refresh
+// of a materialized view
+func (qb *QueryBuilder) GetMaterializedViewQuery(schema, viewName string) (string, []interface{}) {
+	meta := qb.dialect.ViewMetadata()
+	return meta.GetMaterializedView, []interface{}{
+		qb.dialect.NormalizeIdentifier(schema),
+		qb.dialect.NormalizeIdentifier(viewName),
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Sequence Queries
+// -----------------------------------------------------------------------------
+
+// ListSequencesQuery returns the query to list sequences
+func (qb *QueryBuilder) ListSequencesQuery(schemaFilter, nameFilter string, limit, offset int) (string, []interface{}) {
+	meta := qb.dialect.SequenceMetadata()
+	if meta.ListSequences == "" {
+		return "", nil
+	}
+
+	query := meta.ListSequences
+	var args []interface{}
+	argIndex := 1
+
+	if schemaFilter != "" && meta.SchemaFilter != "" {
+		query += fmt.Sprintf(meta.SchemaFilter, qb.Placeholder(argIndex))
+		args = append(args, qb.dialect.NormalizeIdentifier(schemaFilter))
+		argIndex++
+	}
+
+	if nameFilter != "" && meta.NameFilter != "" {
+		query += fmt.Sprintf(meta.NameFilter, qb.Placeholder(argIndex))
+		args = append(args, "%"+qb.dialect.NormalizeIdentifier(nameFilter)+"%")
+	}
+
+	query = qb.appendPaginationClause(query, meta.OrderBy, limit, offset)
+
+	return query, args
+}
+
+// -----------------------------------------------------------------------------
+// Type Queries
+// -----------------------------------------------------------------------------
+
+// ListTypesQuery returns the query to list user-defined types
+func (qb *QueryBuilder) ListTypesQuery(schemaFilter, nameFilter string, limit, offset int) (string, []interface{}) {
+	meta := qb.dialect.TypeMetadata()
+	if meta.ListTypes == "" {
+		return "", nil
+	}
+
+	query := meta.ListTypes
+	var args []interface{}
+	argIndex := 1
+
+	if schemaFilter != "" && meta.SchemaFilter != "" {
+		query += fmt.Sprintf(meta.SchemaFilter, qb.Placeholder(argIndex))
+		args = append(args, qb.dialect.NormalizeIdentifier(schemaFilter))
+		argIndex++
+	}
+
+	if nameFilter != "" && meta.NameFilter != "" {
+		query += fmt.Sprintf(meta.NameFilter, qb.Placeholder(argIndex))
+		args = append(args, "%"+qb.dialect.NormalizeIdentifier(nameFilter)+"%")
+	}
+
+	query = qb.appendPaginationClause(query, meta.OrderBy, limit, offset)
+
+	return query, args
+}
+
+// GetTypeValuesQuery returns the query to get the labels of an enum type
+func (qb *QueryBuilder) GetTypeValuesQuery(schema, typeName string) (string, []interface{}) {
+	meta := qb.dialect.TypeMetadata()
+	return meta.GetValues, []interface{}{
+		qb.dialect.NormalizeIdentifier(schema),
+		qb.dialect.NormalizeIdentifier(typeName),
+	}
+}
+
+// GetTypeAttributesQuery returns the query to get the attributes of a composite type
+func (qb *QueryBuilder) GetTypeAttributesQuery(schema, typeName string) (string, []interface{}) {
+	meta := qb.dialect.TypeMetadata()
+	return meta.GetAttributes, []interface{}{
+		qb.dialect.NormalizeIdentifier(schema),
+		qb.dialect.NormalizeIdentifier(typeName),
+	}
+}
+
 // -----------------------------------------------------------------------------
 // Trigger Queries
 // -----------------------------------------------------------------------------
@@ -488,17 +740,31 @@ func (qb *QueryBuilder) GetSchemasListQuery() (string, bool) {
 	return schemas, schemas != ""
 }
 
-// SearchObjectsQuery returns the query to search database objects
-func (qb *QueryBuilder) SearchObjectsQuery(searchTerm string, searchInCode bool, objectTypes []string) (string, []interface{}) {
+// ListDependenciesQuery returns the query listing the dependencies between objects
+func (qb *QueryBuilder) ListDependenciesQuery() (string, bool) {
+	query := qb.dialect.DependencyMetadata().ListDependencies
+	return query, query != ""
+}
+
+// ListObjectSQLQuery returns the query listing the SQL of the objects whose
+// dependencies are parsed from their definition
+func (qb *QueryBuilder) ListObjectSQLQuery() (string, bool) {
+	query := qb.dialect.DependencyMetadata().ListObjectSQL
+	return query, query != ""
+}
+
+// SearchObjectsQuery returns the query to search database objects.
+// searchInComments also matches the table and column comments of the object.
+func (qb *QueryBuilder) SearchObjectsQuery(searchTerm string, searchInCode, searchInComments bool, objectTypes []string) (string, []interface{}) {
 	switch qb.driver {
 	case DriverSQLServer:
-		return qb.buildSQLServerSearchQuery(searchTerm, searchInCode, objectTypes)
+		return qb.buildSQLServerSearchQuery(searchTerm, searchInCode, searchInComments, objectTypes)
 	case DriverPostgresSQL:
-		return qb.buildPostgresSearchQuery(searchTerm, searchInCode, objectTypes)
+		return qb.buildPostgresSearchQuery(searchTerm, searchInCode, searchInComments, objectTypes)
 	case DriverMySQL:
-		return qb.buildMySQLSearchQuery(searchTerm, objectTypes)
+		return qb.buildMySQLSearchQuery(searchTerm, searchInComments, objectTypes)
 	case DriverOracle:
-		return qb.buildOracleSearchQuery(searchTerm, objectTypes)
+		return qb.buildOracleSearchQuery(searchTerm, searchInComments, objectTypes)
 	case DriverSQLite:
 		return qb.buildSQLiteSearchQuery(searchTerm, searchInCode, objectTypes)
 	}
@@ -509,7 +775,7 @@ func (qb *QueryBuilder) SearchObjectsQuery(searchTerm string, searchInCode bool,
 // Search Query Builders (driver-specific due to complexity)
 // -----------------------------------------------------------------------------
 
-func (qb *QueryBuilder) buildSQLServerSearchQuery(searchTerm string, searchInCode bool, objectTypes []string) (string, []interface{}) {
+func (qb *QueryBuilder) buildSQLServerSearchQuery(searchTerm string, searchInCode, searchInComments bool, objectTypes []string) (string, []interface{}) {
 	typeMap := map[string]string{
 		"table":     "U",
 		"view":      "V",
@@ -538,6 +804,13 @@ func (qb *QueryBuilder) buildSQLServerSearchQuery(searchTerm string, searchInCod
 		searchInCodeClause = "OR (m.definition IS NOT NULL AND m.definition LIKE '%' + @p1 + '%')"
 	}
 
+	searchInCommentsClause := ""
+	if searchInComments {
+		searchInCommentsClause = `OR EXISTS (SELECT 1 FROM sys.extended_properties cp
+			WHERE cp.class = 1 AND cp.major_id = o.object_id AND cp.name = 'MS_Description'
+				AND CAST(cp.value AS nvarchar(4000)) LIKE '%' + @p1 + '%')`
+	}
+
 	query := fmt.Sprintf(`
 		SELECT DISTINCT
 			s.name AS schema_name,
@@ -545,18 +818,21 @@ func (qb *QueryBuilder) buildSQLServerSearchQuery(searchTerm string, searchInCod
 			o.type_desc AS object_type,
 			o.create_date,
 			o.modify_date,
-			CASE WHEN m.definition IS NOT NULL THEN 1 ELSE 0 END AS has_code
+			CASE WHEN m.definition IS NOT NULL THEN 1 ELSE 0 END AS has_code,
+			CAST(ep.value AS nvarchar(4000)) AS description
 		FROM sys.objects o
 		INNER JOIN sys.schemas s ON o.schema_id = s.schema_id
 		LEFT JOIN sys.sql_modules m ON o.object_id = m.object_id
+		LEFT JOIN sys.extended_properties ep
+			ON ep.class = 1 AND ep.major_id = o.object_id AND ep.minor_id = 0 AND ep.name = 'MS_Description'
 		WHERE o.type IN (%s)
-		  AND (o.name LIKE '%%' + @p1 + '%%' %s)
-		ORDER BY s.name, o.name`, typeInClause, searchInCodeClause)
+		  AND (o.name LIKE '%%' + @p1 + '%%' %s %s)
+		ORDER BY s.name, o.name`, typeInClause, searchInCodeClause, searchInCommentsClause)
 
 	return query, []interface{}{searchTerm}
 }
 
-func (qb *QueryBuilder) buildPostgresSearchQuery(searchTerm string, searchInCode bool, objectTypes []string) (string, []interface{}) {
+func (qb *QueryBuilder) buildPostgresSearchQuery(searchTerm string, searchInCode, searchInComments bool, objectTypes []string) (string, []interface{}) {
 	objectTypeFilter := ""
 	if len(objectTypes) > 0 {
 		var types []string
@@ -578,6 +854,15 @@ func (qb *QueryBuilder) buildPostgresSearchQuery(searchTerm string, searchInCode
 		searchInCodeClause = " OR view_definition LIKE '%' || $1 || '%'"
 	}
 
+	// The comments of a table or view and of its columns are all kept against its pg_class row
+	searchInCommentsClause := ""
+	if searchInComments {
+		searchInCommentsClause = ` OR EXISTS (SELECT 1 FROM pg_description d
+			WHERE d.classoid = 'pg_class'::regclass
+				AND d.objoid = (quote_ident(table_schema) || '.' || quote_ident(table_name))::regclass
+				AND d.description ILIKE '%' || $1 || '%')`
+	}
+
 	query := fmt.Sprintf(`
 		SELECT
 			table_schema AS schema_name,
@@ -585,18 +870,19 @@ func (qb *QueryBuilder) buildPostgresSearchQuery(searchTerm string, searchInCode
 			table_type AS object_type,
 			NULL AS create_date,
 			NULL AS modify_date,
-			CASE WHEN view_definition IS NOT NULL THEN 1 ELSE 0 END AS has_code
+			CASE WHEN view_definition IS NOT NULL THEN 1 ELSE 0 END AS has_code,
+			obj_description((quote_ident(table_schema) || '.' || quote_ident(table_name))::regclass, 'pg_class') AS description
 		FROM information_schema.tables
 		LEFT JOIN information_schema.views USING (table_schema, table_name)
 		WHERE table_schema NOT IN ('pg_catalog', 'information_schema')
-		  AND (table_name LIKE '%%' || $1 || '%%' %s)
+		  AND (table_name LIKE '%%' || $1 || '%%' %s %s)
 		  %s
-		ORDER BY table_schema, table_name`, searchInCodeClause, objectTypeFilter)
+		ORDER BY table_schema, table_name`, searchInCodeClause, searchInCommentsClause, objectTypeFilter)
 
 	return query, []interface{}{searchTerm}
 }
 
-func (qb *QueryBuilder) buildMySQLSearchQuery(searchTerm string, objectTypes []string) (string, []interface{}) {
+func (qb *QueryBuilder) buildMySQLSearchQuery(searchTerm string, searchInComments bool, objectTypes []string) (string, []interface{}) {
 	objectTypeFilter := ""
 	if len(objectTypes) > 0 {
 		var types []string
@@ -613,6 +899,17 @@ func (qb *QueryBuilder) buildMySQLSearchQuery(searchTerm string, objectTypes []s
 		}
 	}
 
+	args := []interface{}{searchTerm}
+	searchInCommentsClause := ""
+	if searchInComments {
+		searchInCommentsClause = ` OR TABLE_COMMENT LIKE CONCAT('%', ?, '%')
+			OR EXISTS (SELECT 1 FROM INFORMATION_SCHEMA.COLUMNS c
+				WHERE c.TABLE_SCHEMA = t.TABLE_SCHEMA AND c.TABLE_NAME = t.TABLE_NAME
+					AND c.COLUMN_COMMENT LIKE CONCAT('%', ?, '%'))`
+		args = append(args, searchTerm, searchTerm)
+	}
+
+	// MySQL reports 'VIEW' as the comment of every view
 	query := fmt.Sprintf(`
 		SELECT
 			TABLE_SCHEMA AS schema_name,
@@ -620,17 +917,18 @@ func (qb *QueryBuilder) buildMySQLSearchQuery(searchTerm string, objectTypes []s
 			TABLE_TYPE AS object_type,
 			CREATE_TIME AS create_date,
 			UPDATE_TIME AS modify_date,
-			0 AS has_code
-		FROM INFORMATION_SCHEMA.TABLES
+			0 AS has_code,
+			CASE WHEN TABLE_TYPE = 'VIEW' THEN NULL ELSE NULLIF(TABLE_COMMENT, '') END AS description
+		FROM INFORMATION_SCHEMA.TABLES t
 		WHERE TABLE_SCHEMA NOT IN ('mysql', 'information_schema', 'performance_schema', 'sys')
-		  AND TABLE_NAME LIKE CONCAT('%%', ?, '%%')
+		  AND (TABLE_NAME LIKE CONCAT('%%', ?, '%%') %s)
 		  %s
-		ORDER BY TABLE_SCHEMA, TABLE_NAME`, objectTypeFilter)
+		ORDER BY TABLE_SCHEMA, TABLE_NAME`, searchInCommentsClause, objectTypeFilter)
 
-	return query, []interface{}{searchTerm}
+	return query, args
 }
 
-func (qb *QueryBuilder) buildOracleSearchQuery(searchTerm string, objectTypes []string) (string, []interface{}) {
+func (qb *QueryBuilder) buildOracleSearchQuery(searchTerm string, searchInComments bool, objectTypes []string) (string, []interface{}) {
 	objectTypeFilter := ""
 	if len(objectTypes) > 0 {
 		var types []string
@@ -651,6 +949,18 @@ func (qb *QueryBuilder) buildOracleSearchQuery(searchTerm string, objectTypes []
 		}
 	}
 
+	args := []interface{}{strings.ToUpper(searchTerm)}
+	searchInCommentsClause := ""
+	if searchInComments {
+		searchInCommentsClause = ` OR EXISTS (SELECT 1 FROM all_tab_comments tc
+				WHERE tc.owner = o.owner AND tc.table_name = o.object_name
+					AND UPPER(tc.comments) LIKE '%' || :2 || '%')
+			OR EXISTS (SELECT 1 FROM all_col_comments cc
+				WHERE cc.owner = o.owner AND cc.table_name = o.object_name
+					AND UPPER(cc.comments) LIKE '%' || :3 || '%')`
+		args = append(args, strings.ToUpper(searchTerm), strings.ToUpper(searchTerm))
+	}
+
 	query := fmt.Sprintf(`
 		SELECT
 			owner AS schema_name,
@@ -658,14 +968,16 @@ func (qb *QueryBuilder) buildOracleSearchQuery(searchTerm string, objectTypes []
 			object_type,
 			created AS create_date,
 			last_ddl_time AS modify_date,
-			0 AS has_code
-		FROM all_objects
+			0 AS has_code,
+			(SELECT tc.comments FROM all_tab_comments tc
+				WHERE tc.owner = o.owner AND tc.table_name = o.object_name) AS description
+		FROM all_objects o
 		WHERE owner NOT IN ('SYS', 'SYSTEM')
-		  AND object_name LIKE '%%' || :1 || '%%'
+		  AND (object_name LIKE '%%' || :1 || '%%' %s)
 		  %s
-		ORDER BY owner, object_name`, objectTypeFilter)
+		ORDER BY owner, object_name`, searchInCommentsClause, objectTypeFilter)
 
-	return query, []interface{}{strings.ToUpper(searchTerm)}
+	return query, args
 }
 
 func (qb *QueryBuilder) buildSQLiteSearchQuery(searchTerm string, searchInCode bool, objectTypes []string) (string, []interface{}) {
@@ -699,7 +1011,8 @@ func (qb *QueryBuilder) buildSQLiteSearchQuery(searchTerm string, searchInCode b
 			type AS object_type,
 			NULL AS create_date,
 			NULL AS modify_date,
-			CASE WHEN sql IS NOT NULL THEN 1 ELSE 0 END AS has_code
+			CASE WHEN sql IS NOT NULL THEN 1 ELSE 0 END AS has_code,
+			NULL AS description
 		FROM sqlite_master
 		WHERE name NOT LIKE 'sqlite_%%'
 		  AND (name LIKE '%%' || ? || '%%' %s)
@@ -726,13 +1039,110 @@ func (qb *QueryBuilder) BuildSelectQuery(params SelectQueryParams) string {
 	}
 	columnsStr := strings.Join(quotedColumns, ", ")
 
-	quotedOrderBy := qb.QuoteIdentifier(params.OrderBy)
-	orderClause := fmt.Sprintf("%s %s", quotedOrderBy, params.OrderDirection)
+	var orderTerms []string
+	for _, ob := range params.OrderBy {
+		orderTerms = append(orderTerms, qb.dialect.OrderByExpression(qb.QuoteIdentifier(ob.Column), ob.Descending, ob.Nulls))
+	}
+	orderClause := strings.Join(orderTerms, ", ")
 
 	baseQuery := fmt.Sprintf(`SELECT %s FROM %s %s`, columnsStr, qualifiedTable, params.WhereClause)
 	return qb.appendPaginationClause(baseQuery, orderClause, params.Limit, params.Offset)
 }
 
+// BuildAggregateQuery builds a GROUP BY query with aggregate functions.
+// Pagination is only applied when there are GROUP BY columns.
+func (qb *QueryBuilder) BuildAggregateQuery(params AggregateQueryParams) string {
+	return qb.BuildJoinQuery(JoinQueryParams{
+		Schema:      params.Schema,
+		Table:       params.Table,
+		GroupBy:     params.GroupBy,
+		Aggregates:  params.Aggregates,
+		WhereClause: params.WhereClause,
+		OrderBy:     params.OrderBy,
+		Limit:       params.Limit,
+		Offset:      params.Offset,
+	})
+}
+
+// BuildJoinQuery builds a SELECT over a base table and tables joined to it,
+// optionally grouped. Pagination is skipped for aggregates without GROUP BY (single row).
+func (qb *QueryBuilder) BuildJoinQuery(params JoinQueryParams) string {
+	from := qb.QualifyTable(params.Schema, params.Table)
+	if params.Alias != "" {
+		from += " " + qb.QuoteIdentifier(params.Alias)
+	}
+	for _, j := range params.Joins {
+		var on []string
+		for i := range j.Columns {
+			on = append(on, fmt.Sprintf("%s = %s", qb.ColumnRef(j.FromAlias, j.FromColumns[i]), qb.ColumnRef(j.Alias, j.Columns[i])))
+		}
+		from += fmt.Sprintf(" %s JOIN %s %s ON %s", j.Type, qb.QualifyTable(j.Schema, j.Table), qb.QuoteIdentifier(j.Alias), strings.Join(on, " AND "))
+	}
+
+	// Expressions by alias, used for ORDER BY since not every database
+	// accepts aliases inside ORDER BY expressions
+	expressions := make(map[string]string)
+
+	var selectTerms, groupTerms []string
+	for _, c := range params.Columns {
+		expr := qb.ColumnRef(c.Qualifier, c.Column)
+		expressions[c.Alias] = expr
+		selectTerms = append(selectTerms, fmt.Sprintf("%s AS %s", expr, qb.QuoteIdentifier(c.Alias)))
+	}
+
+	for _, g := range params.GroupBy {
+		expr := qb.ColumnRef(g.Qualifier, g.Column)
+		if g.Bucket != "" {
+			expr = qb.dialect.DateTrunc(expr, g.Bucket)
+		}
+		expressions[g.Alias] = expr
+		selectTerms = append(selectTerms, fmt.Sprintf("%s AS %s", expr, qb.QuoteIdentifier(g.Alias)))
+		groupTerms = append(groupTerms, expr)
+	}
+
+	for _, a := range params.Aggregates {
+		expr := qb.aggregateExpression(a)
+		expressions[a.Alias] = expr
+		selectTerms = append(selectTerms, fmt.Sprintf("%s AS %s", expr, qb.QuoteIdentifier(a.Alias)))
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s %s", strings.Join(selectTerms, ", "), from, params.WhereClause)
+	if len(groupTerms) > 0 {
+		query += " GROUP BY " + strings.Join(groupTerms, ", ")
+	} else if len(params.Aggregates) > 0 {
+		return query
+	}
+
+	var orderTerms []string
+	for _, ob := range params.OrderBy {
+		orderTerms = append(orderTerms, qb.dialect.OrderByExpression(expressions[ob.Column], ob.Descending, ob.Nulls))
+	}
+
+	return qb.appendPaginationClause(query, strings.Join(orderTerms, ", "), params.Limit, params.Offset)
+}
+
+// ColumnRef returns a quoted column reference, qualified by a table alias when given
+func (qb *QueryBuilder) ColumnRef(qualifier, column string) string {
+	if qualifier == "" {
+		return qb.QuoteIdentifier(column)
+	}
+	return qb.QuoteIdentifier(qualifier) + "." + qb.QuoteIdentifier(column)
+}
+
+// aggregateExpression renders an aggregate function call
+func (qb *QueryBuilder) aggregateExpression(a AggregateTerm) string {
+	if a.Column == "" {
+		return "COUNT(*)"
+	}
+	column := qb.ColumnRef(a.Qualifier, a.Column)
+	switch a.Function {
+	case "count_distinct":
+		return fmt.Sprintf("COUNT(DISTINCT %s)", column)
+	default:
+		return fmt.Sprintf("%s(%s)", strings.ToUpper(a.Function), column)
+	}
+}
+
 // BuildCountQuery builds a COUNT query
 func (qb *QueryBuilder) BuildCountQuery(schema, table, whereClause string) string {
 	qualifiedTable := qb.QualifyTable(schema, table)
@@ -0,0 +1,95 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// checkQueryCost runs an estimate-only plan of a validated query with its bound
+// parameters and rejects it when the estimated cost, the estimated result rows or the
+// rows of any plan operator exceed the query guard limits of the active datasource.
+// A query whose plan cannot be estimated is rejected unless the guard fails open.
+// Databases that do not report estimates (SQLite) are never rejected.
+func (s *DbMCPServer) checkQueryCost(ctx context.Context, query string, params []interface{}) error {
+	guard := s.config.QueryGuard(s.profile)
+	if guard == nil || !guard.Enabled {
+		return nil
+	}
+
+	plan, err := s.explainQuery(ctx, query, params, false)
+	if err != nil {
+		if guard.FailOpen {
+			// The query itself will report the error if it is invalid
+			log.Printf("Query guard: could not estimate the plan, query allowed: %v\n", err)
+			return nil
+		}
+		return fmt.Errorf("%w: %v", ErrQueryCostUnknown, err)
+	}
+	return checkPlanLimits(guard, plan)
+}
+
+// checkPlanLimits checks the estimates of a plan against the query guard limits
+func checkPlanLimits(guard *QueryGuardConfig, plan *ExecutionPlan) error {
+	if guard.MaxEstimatedCost > 0 && plan.TotalCost > guard.MaxEstimatedCost {
+		node := costliestPlanNode(plan.Root, guard.MaxEstimatedCost)
+		return fmt.Errorf("%w: estimated cost %.2f exceeds the limit of %.2f, mostly from %s",
+			ErrQueryTooExpensive, plan.TotalCost, guard.MaxEstimatedCost, describePlanNode(node))
+	}
+
+	if guard.MaxEstimatedRows > 0 && plan.EstimatedRows > guard.MaxEstimatedRows {
+		return fmt.Errorf("%w: the query is estimated to return %.0f rows, exceeding the limit of %.0f",
+			ErrQueryTooExpensive, plan.EstimatedRows, guard.MaxEstimatedRows)
+	}
+
+	if guard.MaxScannedRows > 0 && plan.Root != nil {
+		node := largestPlanNode(plan.Root)
+		if node.EstimatedRows > guard.MaxScannedRows {
+			return fmt.Errorf("%w: %s estimates %.0f rows, exceeding the scanned rows limit of %.0f",
+				ErrQueryTooExpensive, describePlanNode(node), node.EstimatedRows, guard.MaxScannedRows)
+		}
+	}
+
+	return nil
+}
+
+// costliestPlanNode descends from node through the costliest child while that child
+// still exceeds limit, returning the deepest operator responsible for the cost.
+// Plan costs are cumulative, so the children of a node never cost more than the node.
+func costliestPlanNode(node *PlanNode, limit float64) *PlanNode {
+	for {
+		var next *PlanNode
+		for _, child := range node.Children {
+			if child.EstimatedCost > limit && (next == nil || child.EstimatedCost > next.EstimatedCost) {
+				next = child
+			}
+		}
+		if next == nil {
+			return node
+		}
+		node = next
+	}
+}
+
+// largestPlanNode returns the operator with the most estimated rows
+func largestPlanNode(node *PlanNode) *PlanNode {
+	largest := node
+	for _, child := range node.Children {
+		if candidate := largestPlanNode(child); candidate.EstimatedRows > largest.EstimatedRows {
+			largest = candidate
+		}
+	}
+	return largest
+}
+
+// describePlanNode returns a short description of a plan operator for error messages
+func describePlanNode(node *PlanNode) string {
+	description := fmt.Sprintf("'%s'", node.Operator)
+	if node.Object != "" {
+		description += " on " + node.Object
+	}
+	if node.Index != "" {
+		description += " using " + node.Index
+	}
+	return description + fmt.Sprintf(" (cost %.2f, rows %.0f)", node.EstimatedCost, node.EstimatedRows)
+}
@@ -0,0 +1,90 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestCheckQueryCostUnknownPlan(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)`); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		failOpen bool
+		query    string
+		want     error
+	}{
+		{"estimated", false, "SELECT id FROM users", nil},
+		{"unknown plan", false, "SELECT id FROM missing", ErrQueryCostUnknown},
+		{"unknown plan fail open", true, "SELECT id FROM missing", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{Datasources: map[string]*DataSourceConfig{
+				"default": {QueryGuard: &QueryGuardConfig{Enabled: true, MaxEstimatedRows: 1000, FailOpen: tt.failOpen}},
+			}}
+			s := &DbMCPServer{db: db, queryBuilder: NewQueryBuilder("sqlite3"), config: config, datasource: "default", profile: "default"}
+			err := s.checkQueryCost(context.Background(), tt.query, nil)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("checkQueryCost(%q) = %v, want nil", tt.query, err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("checkQueryCost(%q) = %v, want %v", tt.query, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckPlanLimits(t *testing.T) {
+	// SELECT * FROM big LIMIT 10: a large scan under a small result
+	scan := &PlanNode{Operator: "Seq Scan", Object: "big", EstimatedRows: 5000000, EstimatedCost: 80}
+	limited := &ExecutionPlan{Root: &PlanNode{Operator: "Limit", EstimatedRows: 10, EstimatedCost: 90, Children: []*PlanNode{scan}}}
+	annotatePlan(limited)
+	full := &ExecutionPlan{Root: &PlanNode{Operator: "Seq Scan", Object: "big", EstimatedRows: 5000000, EstimatedCost: 80}}
+	annotatePlan(full)
+
+	tests := []struct {
+		name  string
+		guard QueryGuardConfig
+		plan  *ExecutionPlan
+		want  error
+	}{
+		{"result rows under limit", QueryGuardConfig{MaxEstimatedRows: 1000}, limited, nil},
+		{"result rows over limit", QueryGuardConfig{MaxEstimatedRows: 1000}, full, ErrQueryTooExpensive},
+		{"scanned rows over limit", QueryGuardConfig{MaxScannedRows: 1000}, limited, ErrQueryTooExpensive},
+		{"scanned rows under limit", QueryGuardConfig{MaxScannedRows: 10000000}, limited, nil},
+		{"cost over limit", QueryGuardConfig{MaxEstimatedCost: 50}, limited, ErrQueryTooExpensive},
+		{"no limits", QueryGuardConfig{}, full, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkPlanLimits(&tt.guard, tt.plan)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("checkPlanLimits() = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("checkPlanLimits() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+)
+
+func TestRoutineListEntries(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	// Rows of the two overloads of add are interleaved, as without an ORDER BY
+	if _, err := db.Exec(`CREATE TABLE args (routine TEXT, pkg TEXT, sn TEXT, pos INT, name TEXT, mode TEXT, dt TEXT, def TEXT, hd TEXT, rt TEXT);
+		INSERT INTO args VALUES
+			('add', NULL, '101', 1, 'a', 'IN', 'integer', NULL, 'NO', 'integer'),
+			('add', NULL, '102', 1, 'a', 'IN', 'numeric', NULL, 'NO', 'numeric'),
+			('add', NULL, '101', 2, 'b', 'IN', 'integer', '0', 'YES', 'integer'),
+			('add', NULL, '102', 2, 'total', 'OUT', 'numeric', NULL, 'NO', 'numeric'),
+			('now', NULL, '103', NULL, NULL, NULL, NULL, NULL, 'NO', 'timestamp'),
+			('add', 'math', '104', 1, 'x', 'IN', 'text', NULL, 'NO', 'text')`); err != nil {
+		t.Fatal(err)
+	}
+
+	var listQueries []string
+	s := &DbMCPServer{db: db, queryBuilder: NewQueryBuilder("sqlite3")}
+	queries := routineQueries{
+		arguments: func(schema, packageName, name string) (string, []interface{}) {
+			return "SELECT * FROM args WHERE COALESCE(pkg, '') = ? AND routine = ?", []interface{}{packageName, name}
+		},
+		listArguments: func(schema string) (string, []interface{}) {
+			listQueries = append(listQueries, schema)
+			return "SELECT * FROM args", nil
+		},
+	}
+
+	overloads, err := s.fetchRoutineOverloads(context.Background(), queries, "public", "", "add")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(overloads) != 2 || routineSignature("add", overloads[0]) != "add(a integer, b integer)" ||
+		routineSignature("add", overloads[1]) != "add(a numeric, OUT total numeric)" {
+		t.Fatalf("fetchRoutineOverloads() = %+v, want the two overloads of add", overloads)
+	}
+
+	routine := func(packageName, name, specificName string) routineRow {
+		return routineRow{
+			schema:       "public",
+			name:         name,
+			packageName:  sql.NullString{String: packageName, Valid: packageName != ""},
+			specificName: sql.NullString{String: specificName, Valid: true},
+		}
+	}
+	entries, err := s.routineListEntries(context.Background(), queries, []routineRow{
+		routine("", "add", "102"), routine("", "add", "101"), routine("", "now", "103"), routine("math", "add", "104"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listQueries) != 1 {
+		t.Fatalf("routineListEntries() ran %d argument queries, want 1", len(listQueries))
+	}
+
+	want := []struct {
+		signature string
+		overload  interface{}
+	}{
+		{"add(a numeric, OUT total numeric)", 2},
+		{"add(a integer, b integer)", 1},
+		{"now()", nil},
+		{"add(x text)", nil},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("routineListEntries() returned %d entries, want %d", len(entries), len(want))
+	}
+	for i, entry := range entries {
+		if entry["signature"] != want[i].signature || entry["overload"] != want[i].overload {
+			t.Errorf("entry %d = %v %v, want %v %v", i, entry["signature"], entry["overload"], want[i].signature, want[i].overload)
+		}
+	}
+}
@@ -0,0 +1,484 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"gopkg.in/yaml.v3"
+)
+
+var reSavedQueryName = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// savedQueryTypes maps the parameter types of saved queries to JSON schema types
+var savedQueryTypes = map[string]string{
+	"string":   "string",
+	"integer":  "integer",
+	"number":   "number",
+	"boolean":  "boolean",
+	"date":     "string",
+	"datetime": "string",
+}
+
+// SavedQuery is a vetted query read from a .sql file of the saved queries directory.
+// The file starts with YAML front-matter between "---" lines; the rest is the SQL,
+// referencing the parameters as :name.
+type SavedQuery struct {
+	Name        string                `yaml:"name"`
+	Description string                `yaml:"description"`
+	Datasource  string                `yaml:"datasource"` // empty runs on any datasource
+	MaxRows     int                   `yaml:"max_rows"`
+	Parameters  []SavedQueryParameter `yaml:"parameters"`
+	SQL         string                `yaml:"-"`
+	File        string                `yaml:"-"`
+}
+
+// SavedQueryParameter is a typed parameter of a saved query
+type SavedQueryParameter struct {
+	Name        string      `yaml:"name"`
+	Type        string      `yaml:"type"` // string, integer, number, boolean, date or datetime (default: string)
+	Description string      `yaml:"description"`
+	Required    bool        `yaml:"required"`
+	Default     interface{} `yaml:"default"`
+}
+
+// ParseSavedQuery reads a saved query file; without a name in the front-matter the
+// file name is used
+func ParseSavedQuery(file string, data []byte) (*SavedQuery, error) {
+	query := &SavedQuery{File: file}
+
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	if rest, ok := strings.CutPrefix(text, "---\n"); ok {
+		header, body, found := strings.Cut(rest, "\n---\n")
+		if !found {
+			return nil, fmt.Errorf("%w: %s: front-matter is not closed by ---", ErrInvalidSavedQuery, file)
+		}
+		decoder := yaml.NewDecoder(bytes.NewReader([]byte(header)))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(query); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidSavedQuery, file, err)
+		}
+		text = body
+	}
+	query.SQL = strings.TrimRight(strings.TrimSpace(text), ";")
+
+	if query.Name == "" {
+		query.Name = strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+	}
+	if !reSavedQueryName.MatchString(query.Name) {
+		return nil, fmt.Errorf("%w: %s: name %q must be lowercase letters, digits and underscores", ErrInvalidSavedQuery, file, query.Name)
+	}
+	if query.SQL == "" {
+		return nil, fmt.Errorf("%w: %s: no SQL", ErrInvalidSavedQuery, file)
+	}
+	if query.MaxRows <= 0 || query.MaxRows > 10000 {
+		query.MaxRows = 100
+	}
+
+	declared := make(map[string]bool)
+	for i := range query.Parameters {
+		p := &query.Parameters[i]
+		if p.Type == "" {
+			p.Type = "string"
+		}
+		if _, ok := savedQueryTypes[p.Type]; !ok {
+			return nil, fmt.Errorf("%w: %s: parameter %q has unknown type %q", ErrInvalidSavedQuery, file, p.Name, p.Type)
+		}
+		if !reValidIdentifier.MatchString(p.Name) || declared[p.Name] {
+			return nil, fmt.Errorf("%w: %s: invalid or repeated parameter %q", ErrInvalidSavedQuery, file, p.Name)
+		}
+		if _, reserved := resultBudgetProperties(map[string]interface{}{})[p.Name]; reserved {
+			return nil, fmt.Errorf("%w: %s: parameter %q is a result size argument of the tool", ErrInvalidSavedQuery, file, p.Name)
+		}
+		if p.Default != nil {
+			if _, err := p.convert(p.Default); err != nil {
+				return nil, fmt.Errorf("%w: %s: default: %v", ErrInvalidSavedQuery, file, err)
+			}
+		}
+		declared[p.Name] = true
+	}
+
+	for _, name := range savedQueryReferences(query.SQL) {
+		if !declared[name] {
+			return nil, fmt.Errorf("%w: %s: :%s is not a declared parameter", ErrInvalidSavedQuery, file, name)
+		}
+	}
+	return query, nil
+}
+
+// savedQueryReferences returns the :name references of a query in order, skipping
+// string literals, quoted identifiers, comments and :: casts
+func savedQueryReferences(sql string) []string {
+	var names []string
+	scanSavedQuery(sql, func(name string) string {
+		names = append(names, name)
+		return ""
+	})
+	return names
+}
+
+// bindSavedQuery replaces the :name references of a query with driver placeholders,
+// returning the parameter names in placeholder order
+func bindSavedQuery(sql string, qb *QueryBuilder) (string, []string) {
+	var names []string
+	bound := scanSavedQuery(sql, func(name string) string {
+		names = append(names, name)
+		return qb.Placeholder(len(names))
+	})
+	return bound, names
+}
+
+// scanSavedQuery rewrites every :name reference of sql with replace
+func scanSavedQuery(sql string, replace func(name string) string) string {
+	var out strings.Builder
+	isName := func(c byte, first bool) bool {
+		return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (!first && c >= '0' && c <= '9')
+	}
+
+	for i := 0; i < len(sql); {
+		c := sql[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end := i + 1
+			for end < len(sql) {
+				if sql[end] == c {
+					// A doubled quote is an escaped quote
+					if end+1 < len(sql) && sql[end+1] == c {
+						end += 2
+						continue
+					}
+					break
+				}
+				end++
+			}
+			end = min(end+1, len(sql))
+			out.WriteString(sql[i:end])
+			i = end
+		case strings.HasPrefix(sql[i:], "--"):
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				end = len(sql) - i
+			}
+			out.WriteString(sql[i : i+end])
+			i += end
+		case strings.HasPrefix(sql[i:], "/*"):
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				end = len(sql) - i - 2
+			} else {
+				end += 2
+			}
+			out.WriteString(sql[i : i+2+end])
+			i += 2 + end
+		case strings.HasPrefix(sql[i:], "::"):
+			out.WriteString("::")
+			i += 2
+		case c == ':' && i+1 < len(sql) && isName(sql[i+1], true):
+			end := i + 1
+			for end < len(sql) && isName(sql[end], false) {
+				end++
+			}
+			out.WriteString(replace(sql[i+1 : end]))
+			i = end
+		default:
+			out.WriteByte(c)
+			i++
+		}
+	}
+	return out.String()
+}
+
+// convert checks a parameter value against the parameter type
+func (p *SavedQueryParameter) convert(value interface{}) (interface{}, error) {
+	invalid := fmt.Errorf("%w: %s must be a %s", ErrInvalidSavedQueryParameter, p.Name, p.Type)
+	switch p.Type {
+	case "integer":
+		switch v := value.(type) {
+		case float64:
+			if v != float64(int64(v)) {
+				return nil, invalid
+			}
+			return int64(v), nil
+		case int:
+			return int64(v), nil
+		}
+	case "number":
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case int:
+			return float64(v), nil
+		}
+	case "boolean":
+		if v, ok := value.(bool); ok {
+			return v, nil
+		}
+	case "date", "datetime":
+		v, ok := value.(string)
+		if !ok {
+			break
+		}
+		layouts := []string{"2006-01-02"}
+		if p.Type == "datetime" {
+			layouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02T15:04:05"}
+		}
+		for _, layout := range layouts {
+			if _, err := time.Parse(layout, v); err == nil {
+				// Passed as text; the database converts it
+				return v, nil
+			}
+		}
+	default:
+		if v, ok := value.(string); ok {
+			return v, nil
+		}
+	}
+	return nil, invalid
+}
+
+// schema returns the JSON schema property of the parameter
+func (p *SavedQueryParameter) schema() map[string]interface{} {
+	property := map[string]interface{}{
+		"type":        savedQueryTypes[p.Type],
+		"description": p.Description,
+	}
+	switch p.Type {
+	case "date":
+		property["format"] = "date"
+	case "datetime":
+		property["format"] = "date-time"
+	}
+	if p.Default != nil {
+		property["default"] = p.Default
+	}
+	return property
+}
+
+// Tool returns the saved_<name> tool of the query
+func (q *SavedQuery) Tool() mcp.Tool {
+	properties := make(map[string]interface{})
+	var required []string
+	for i := range q.Parameters {
+		p := &q.Parameters[i]
+		properties[p.Name] = p.schema()
+		if p.Required {
+			required = append(required, p.Name)
+		}
+	}
+
+	description := q.Description
+	if description == "" {
+		description = "Runs the saved query " + q.Name
+	}
+	if q.Datasource != "" {
+		description += fmt.Sprintf(" (datasource: %s)", q.Datasource)
+	}
+
+	return mcp.Tool{
+		Name:        SavedQueryToolPrefix + q.Name,
+		Description: description,
+		InputSchema: mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: resultBudgetProperties(properties),
+			Required:   required,
+		},
+	}
+}
+
+// LoadSavedQueries reads the .sql files of a directory. Invalid files are skipped and
+// returned as errors; a name used by two files keeps the first in file name order.
+func LoadSavedQueries(dir string) ([]*SavedQuery, []error, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrReadingSavedQueries, err)
+	}
+	files, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrReadingSavedQueries, err)
+	}
+	sort.Strings(files)
+
+	var queries []*SavedQuery
+	var problems []error
+	names := make(map[string]string)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("%w: %v", ErrReadingSavedQueries, err))
+			continue
+		}
+		query, err := ParseSavedQuery(file, data)
+		if err != nil {
+			problems = append(problems, err)
+			continue
+		}
+		if other, ok := names[query.Name]; ok {
+			problems = append(problems, fmt.Errorf("%w: %s: name %q already used by %s", ErrInvalidSavedQuery, file, query.Name, other))
+			continue
+		}
+		names[query.Name] = file
+		queries = append(queries, query)
+	}
+	return queries, problems, nil
+}
+
+// savedQueriesSignature summarizes the names, sizes and modification times of the
+// .sql files of a directory, to detect changes
+func savedQueriesSignature(dir string) string {
+	files, _ := filepath.Glob(filepath.Join(dir, "*.sql"))
+	sort.Strings(files)
+
+	hash := sha256.New()
+	for _, file := range files {
+		if info, err := os.Stat(file); err == nil {
+			fmt.Fprintf(hash, "%s\x00%d\x00%d\n", file, info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// savedQueryCatalog tracks the saved query tools registered on the server
+type savedQueryCatalog struct {
+	mu        sync.Mutex
+	dir       string
+	signature string
+	tools     map[string]bool
+	stop      chan struct{}
+}
+
+// loadSavedQueries registers the tools of the saved queries directory, replacing those
+// of a previous load
+func (s *DbMCPServer) loadSavedQueries() error {
+	catalog := s.savedQueries
+	catalog.mu.Lock()
+	defer catalog.mu.Unlock()
+
+	signature := savedQueriesSignature(catalog.dir)
+	queries, problems, err := LoadSavedQueries(catalog.dir)
+	if err != nil {
+		return err
+	}
+	for _, problem := range problems {
+		log.Printf("Saved queries: skipped %v\n", problem)
+	}
+
+	tools := make(map[string]bool, len(queries))
+	var serverTools []server.ServerTool
+	for _, query := range queries {
+		tool := query.Tool()
+		tools[tool.Name] = true
+		serverTools = append(serverTools, server.ServerTool{Tool: tool, Handler: s.savedQueryHandler(query)})
+	}
+
+	var removed []string
+	for name := range catalog.tools {
+		if !tools[name] {
+			removed = append(removed, name)
+		}
+	}
+	if len(removed) > 0 {
+		s.server.DeleteTools(removed...)
+	}
+	if len(serverTools) > 0 {
+		s.server.AddTools(serverTools...)
+	}
+
+	catalog.tools, catalog.signature = tools, signature
+	log.Printf("Saved queries: %d tools loaded from %s\n", len(tools), catalog.dir)
+	return nil
+}
+
+// watchSavedQueries reloads the saved query tools whenever the directory changes
+func (s *DbMCPServer) watchSavedQueries(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.savedQueries.stop:
+			return
+		case <-ticker.C:
+		}
+
+		s.savedQueries.mu.Lock()
+		changed := savedQueriesSignature(s.savedQueries.dir) != s.savedQueries.signature
+		s.savedQueries.mu.Unlock()
+		if !changed {
+			continue
+		}
+		if err := s.loadSavedQueries(); err != nil {
+			log.Printf("Saved queries: could not reload: %v\n", err)
+		}
+	}
+}
+
+// savedQueryHandler runs a saved query through the execute_query path, binding the
+// tool arguments to its parameters
+func (s *DbMCPServer) savedQueryHandler(query *SavedQuery) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := s.requireConnection(); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if query.Datasource != "" && query.Datasource != s.datasource {
+			return mcp.NewToolResultError(fmt.Sprintf("%v: %s runs on '%s', active datasource is '%s'",
+				ErrSavedQueryDatasource, query.Name, query.Datasource, s.datasource)), nil
+		}
+
+		args, ok := getArgs(request.Params.Arguments)
+		if !ok && request.Params.Arguments != nil {
+			return mcp.NewToolResultError(ErrInvalidArguments.Error()), nil
+		}
+
+		values := make(map[string]interface{}, len(query.Parameters))
+		for i := range query.Parameters {
+			p := &query.Parameters[i]
+			value, given := args[p.Name]
+			if !given || value == nil {
+				if p.Required {
+					return mcp.NewToolResultError(fmt.Sprintf("%v: %s is required", ErrInvalidSavedQueryParameter, p.Name)), nil
+				}
+				value = p.Default
+			}
+			if value != nil {
+				converted, err := p.convert(value)
+				if err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				}
+				value = converted
+			}
+			values[p.Name] = value
+		}
+
+		bound, names := bindSavedQuery(query.SQL, s.queryBuilder)
+		params := make([]interface{}, len(names))
+		for i, name := range names {
+			params[i] = values[name]
+		}
+
+		response, err := s.runReadQuery(ctx, bound, params, query.MaxRows, newResultBudget(args))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		response["saved_query"] = query.Name
+		response["parameters"] = values
+
+		jsonData, err := json.MarshalIndent(response, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonData)), nil
+	}
+}
@@ -0,0 +1,713 @@
+package mcp
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// SchemaDiff holds the objects added, removed and changed from one schema catalog to another
+type SchemaDiff struct {
+	Added   []DiffObject   `json:"added"`
+	Removed []DiffObject   `json:"removed"`
+	Changed []ObjectChange `json:"changed"`
+}
+
+// DiffObject is a table, view, procedure or function
+type DiffObject struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+// ObjectChange is an object present on both sides with differences: the changes of
+// a table, or the unified diff of the source of a view or routine
+type ObjectChange struct {
+	Type    string       `json:"type"`
+	Name    string       `json:"name"`
+	Changes []DiffChange `json:"changes,omitempty"`
+	Diff    string       `json:"diff,omitempty"`
+}
+
+// DiffChange is a column, primary key, index or foreign key added, removed or changed
+type DiffChange struct {
+	Kind     string      `json:"kind"`
+	Name     string      `json:"name,omitempty"`
+	Change   string      `json:"change"`
+	Property string      `json:"property,omitempty"`
+	From     interface{} `json:"from"`
+	To       interface{} `json:"to"`
+}
+
+// reCreatePrefix matches the CREATE [OR REPLACE | OR ALTER] starting a statement
+var reCreatePrefix = regexp.MustCompile(`(?is)^\s*CREATE\s+(OR\s+(REPLACE|ALTER)\s+)?`)
+
+// DiffSchemas compares two schema catalogs; added objects are those of to missing from from
+func DiffSchemas(from, to *SchemaCatalog) *SchemaDiff {
+	diff := &SchemaDiff{Added: []DiffObject{}, Removed: []DiffObject{}, Changed: []ObjectChange{}}
+
+	fromTables := make(map[string]*CatalogTable)
+	for i := range from.Tables {
+		fromTables[strings.ToLower(from.Tables[i].Name)] = &from.Tables[i]
+	}
+	toTables := make(map[string]bool)
+	for i := range to.Tables {
+		table := &to.Tables[i]
+		toTables[strings.ToLower(table.Name)] = true
+		old, ok := fromTables[strings.ToLower(table.Name)]
+		if !ok {
+			diff.Added = append(diff.Added, DiffObject{Type: "table", Name: table.Name})
+			continue
+		}
+		if changes := diffTables(old, table); len(changes) > 0 {
+			diff.Changed = append(diff.Changed, ObjectChange{Type: "table", Name: table.Name, Changes: changes})
+		}
+	}
+	for _, table := range from.Tables {
+		if !toTables[strings.ToLower(table.Name)] {
+			diff.Removed = append(diff.Removed, DiffObject{Type: "table", Name: table.Name})
+		}
+	}
+
+	diff.diffCode("view", from.Views, to.Views)
+	diff.diffCode("procedure", from.Procedures, to.Procedures)
+	diff.diffCode("function", from.Functions, to.Functions)
+	return diff
+}
+
+// IsEmpty reports whether both catalogs are the same
+func (d *SchemaDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// diffCode compares views or routines by their source
+func (d *SchemaDiff) diffCode(objectType string, from, to []CatalogCode) {
+	old := make(map[string]CatalogCode)
+	for _, object := range from {
+		old[strings.ToLower(object.Name)] = object
+	}
+	seen := make(map[string]bool)
+	for _, object := range to {
+		key := strings.ToLower(object.Name)
+		seen[key] = true
+		previous, ok := old[key]
+		if !ok {
+			d.Added = append(d.Added, DiffObject{Type: objectType, Name: object.Name})
+			continue
+		}
+		if normalizeSource(previous.Definition) != normalizeSource(object.Definition) {
+			d.Changed = append(d.Changed, ObjectChange{
+				Type: objectType,
+				Name: object.Name,
+				Diff: unifiedDiff(normalizeSource(previous.Definition), normalizeSource(object.Definition),
+					"from/"+objectType+"/"+object.Name, "to/"+objectType+"/"+object.Name),
+			})
+		}
+	}
+	for _, object := range from {
+		if !seen[strings.ToLower(object.Name)] {
+			d.Removed = append(d.Removed, DiffObject{Type: objectType, Name: object.Name})
+		}
+	}
+}
+
+// diffTables returns the changes of the columns, primary key, indexes and foreign keys of a table
+func diffTables(from, to *CatalogTable) []DiffChange {
+	var changes []DiffChange
+
+	oldColumns := make(map[string]CatalogColumn)
+	for _, col := range from.Columns {
+		oldColumns[strings.ToLower(col.Name)] = col
+	}
+	for _, col := range to.Columns {
+		old, ok := oldColumns[strings.ToLower(col.Name)]
+		if !ok {
+			changes = append(changes, DiffChange{Kind: "column", Name: col.Name, Change: "added", To: col})
+			continue
+		}
+		if old.Type != col.Type {
+			changes = append(changes, DiffChange{Kind: "column", Name: col.Name, Change: "changed", Property: "type", From: old.Type, To: col.Type})
+		}
+		if old.Nullable != col.Nullable {
+			changes = append(changes, DiffChange{Kind: "column", Name: col.Name, Change: "changed", Property: "nullable", From: old.Nullable, To: col.Nullable})
+		}
+		if old.Default != col.Default {
+			changes = append(changes, DiffChange{Kind: "column", Name: col.Name, Change: "changed", Property: "default", From: old.Default, To: col.Default})
+		}
+	}
+	for _, col := range from.Columns {
+		if !containsFold(to.columnNames(), col.Name) {
+			changes = append(changes, DiffChange{Kind: "column", Name: col.Name, Change: "removed", From: col})
+		}
+	}
+
+	switch {
+	case len(from.PrimaryKey) == 0 && len(to.PrimaryKey) > 0:
+		changes = append(changes, DiffChange{Kind: "primary_key", Change: "added", To: to.PrimaryKey})
+	case len(from.PrimaryKey) > 0 && len(to.PrimaryKey) == 0:
+		changes = append(changes, DiffChange{Kind: "primary_key", Change: "removed", From: from.PrimaryKey})
+	case !equalFoldSlices(from.PrimaryKey, to.PrimaryKey):
+		changes = append(changes, DiffChange{Kind: "primary_key", Change: "changed", Property: "columns", From: from.PrimaryKey, To: to.PrimaryKey})
+	}
+
+	oldIndexes := make(map[string]CatalogIndex)
+	for _, index := range from.Indexes {
+		oldIndexes[strings.ToLower(index.Name)] = index
+	}
+	newIndexes := make(map[string]bool)
+	for _, index := range to.Indexes {
+		newIndexes[strings.ToLower(index.Name)] = true
+		old, ok := oldIndexes[strings.ToLower(index.Name)]
+		switch {
+		case !ok:
+			changes = append(changes, DiffChange{Kind: "index", Name: index.Name, Change: "added", To: index})
+		case old.Unique != index.Unique || !equalFoldSlices(old.Columns, index.Columns):
+			changes = append(changes, DiffChange{Kind: "index", Name: index.Name, Change: "changed", From: old, To: index})
+		}
+	}
+	for _, index := range from.Indexes {
+		if !newIndexes[strings.ToLower(index.Name)] {
+			changes = append(changes, DiffChange{Kind: "index", Name: index.Name, Change: "removed", From: index})
+		}
+	}
+
+	oldKeys := make(map[string]CatalogForeignKey)
+	for _, fk := range from.ForeignKeys {
+		oldKeys[strings.ToLower(fk.Name)] = fk
+	}
+	newKeys := make(map[string]bool)
+	for _, fk := range to.ForeignKeys {
+		newKeys[strings.ToLower(fk.Name)] = true
+		old, ok := oldKeys[strings.ToLower(fk.Name)]
+		switch {
+		case !ok:
+			changes = append(changes, DiffChange{Kind: "foreign_key", Name: fk.Name, Change: "added", To: fk})
+		case !reflect.DeepEqual(old, fk):
+			changes = append(changes, DiffChange{Kind: "foreign_key", Name: fk.Name, Change: "changed", From: old, To: fk})
+		}
+	}
+	for _, fk := range from.ForeignKeys {
+		if !newKeys[strings.ToLower(fk.Name)] {
+			changes = append(changes, DiffChange{Kind: "foreign_key", Name: fk.Name, Change: "removed", From: fk})
+		}
+	}
+	return changes
+}
+
+// equalFoldSlices reports whether two lists hold the same names in the same order
+func equalFoldSlices(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !strings.EqualFold(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeSource removes the differences of line endings and trailing blanks
+func normalizeSource(source string) string {
+	lines := strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// unifiedDiff returns the changes between two texts in unified diff format
+func unifiedDiff(a, b, fromName, toName string) string {
+	aLines, bLines := strings.Split(a, "\n"), strings.Split(b, "\n")
+
+	type op struct {
+		kind byte // ' ', '-' or '+'
+		text string
+	}
+	var ops []op
+
+	// Common lines at both ends are kept out of the comparison
+	prefix := 0
+	for prefix < len(aLines) && prefix < len(bLines) && aLines[prefix] == bLines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(aLines)-prefix && suffix < len(bLines)-prefix &&
+		aLines[len(aLines)-1-suffix] == bLines[len(bLines)-1-suffix] {
+		suffix++
+	}
+	for _, line := range aLines[:prefix] {
+		ops = append(ops, op{' ', line})
+	}
+	midA, midB := aLines[prefix:len(aLines)-suffix], bLines[prefix:len(bLines)-suffix]
+
+	if len(midA) > MaxDiffLines || len(midB) > MaxDiffLines {
+		for _, line := range midA {
+			ops = append(ops, op{'-', line})
+		}
+		for _, line := range midB {
+			ops = append(ops, op{'+', line})
+		}
+	} else {
+		// Longest common subsequence of the remaining lines
+		n, m := len(midA), len(midB)
+		lcs := make([][]int32, n+1)
+		for i := range lcs {
+			lcs[i] = make([]int32, m+1)
+		}
+		for i := n - 1; i >= 0; i-- {
+			for j := m - 1; j >= 0; j-- {
+				if midA[i] == midB[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else if lcs[i+1][j] >= lcs[i][j+1] {
+					lcs[i][j] = lcs[i+1][j]
+				} else {
+					lcs[i][j] = lcs[i][j+1]
+				}
+			}
+		}
+		i, j := 0, 0
+		for i < n || j < m {
+			switch {
+			case i < n && j < m && midA[i] == midB[j]:
+				ops = append(ops, op{' ', midA[i]})
+				i++
+				j++
+			case i < n && (j == m || lcs[i+1][j] >= lcs[i][j+1]):
+				ops = append(ops, op{'-', midA[i]})
+				i++
+			default:
+				ops = append(ops, op{'+', midB[j]})
+				j++
+			}
+		}
+	}
+	for _, line := range aLines[len(aLines)-suffix:] {
+		ops = append(ops, op{' ', line})
+	}
+
+	var b2 strings.Builder
+	fmt.Fprintf(&b2, "--- %s\n+++ %s\n", fromName, toName)
+	aLine, bLine := make([]int, len(ops)+1), make([]int, len(ops)+1)
+	for k, o := range ops {
+		aLine[k+1], bLine[k+1] = aLine[k], bLine[k]
+		if o.kind != '+' {
+			aLine[k+1]++
+		}
+		if o.kind != '-' {
+			bLine[k+1]++
+		}
+	}
+	for k := 0; k < len(ops); {
+		if ops[k].kind == ' ' {
+			k++
+			continue
+		}
+		// A hunk runs from the context before a change to the context after the last
+		// change less than two contexts away
+		start := k - DiffContextLines
+		if start < 0 {
+			start = 0
+		}
+		end := k
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			next := end
+			for next < len(ops) && ops[next].kind == ' ' {
+				next++
+			}
+			if next == len(ops) || next-end > 2*DiffContextLines {
+				break
+			}
+			end = next
+		}
+		stop := end + DiffContextLines
+		if stop > len(ops) {
+			stop = len(ops)
+		}
+
+		aStart, aLen := aLine[start]+1, aLine[stop]-aLine[start]
+		bStart, bLen := bLine[start]+1, bLine[stop]-bLine[start]
+		if aLen == 0 {
+			aStart--
+		}
+		if bLen == 0 {
+			bStart--
+		}
+		fmt.Fprintf(&b2, "@@ -%d,%d +%d,%d @@\n", aStart, aLen, bStart, bLen)
+		for _, o := range ops[start:stop] {
+			b2.WriteByte(o.kind)
+			b2.WriteString(o.text)
+			b2.WriteByte('\n')
+		}
+		k = stop
+	}
+	return b2.String()
+}
+
+// migrationPlan collects the statements turning one schema into another, in the
+// order they can run
+type migrationPlan struct {
+	qb       *QueryBuilder
+	schema   string
+	drops    []string
+	tables   []string
+	creates  []string
+	alters   []string
+	indexes  []string
+	keys     []string
+	code     []string
+	warnings []string
+}
+
+// MigrationDDL returns the statements turning the schema of from into the schema of
+// to, both of the same database type, with warnings for the changes left to do by hand
+func MigrationDDL(from, to *SchemaCatalog, diff *SchemaDiff) ([]string, []string) {
+	p := &migrationPlan{qb: NewQueryBuilder(string(from.Driver)), schema: from.Schema}
+
+	for _, object := range diff.Removed {
+		switch object.Type {
+		case "table":
+			p.tables = append(p.tables, fmt.Sprintf("DROP TABLE %s;", p.table(object.Name)))
+		case "view":
+			p.drops = append(p.drops, fmt.Sprintf("DROP VIEW %s;", p.table(object.Name)))
+		case "procedure", "function":
+			p.drops = append(p.drops, fmt.Sprintf("DROP %s %s;", strings.ToUpper(object.Type), p.table(object.Name)))
+		}
+	}
+
+	for _, object := range diff.Added {
+		switch object.Type {
+		case "table":
+			p.createTable(to.table(object.Name))
+		case "view":
+			p.createCode(object.Type, object.Name, findCode(to.Views, object.Name), false)
+		case "procedure":
+			p.createCode(object.Type, object.Name, findCode(to.Procedures, object.Name), false)
+		case "function":
+			p.createCode(object.Type, object.Name, findCode(to.Functions, object.Name), false)
+		}
+	}
+
+	for _, object := range diff.Changed {
+		switch object.Type {
+		case "table":
+			p.alterTable(object.Name, to.table(object.Name), object.Changes)
+		case "view":
+			p.createCode(object.Type, object.Name, findCode(to.Views, object.Name), true)
+		case "procedure":
+			p.createCode(object.Type, object.Name, findCode(to.Procedures, object.Name), true)
+		case "function":
+			p.createCode(object.Type, object.Name, findCode(to.Functions, object.Name), true)
+		}
+	}
+
+	var statements []string
+	for _, group := range [][]string{p.drops, p.tables, p.creates, p.alters, p.indexes, p.keys, p.code} {
+		statements = append(statements, group...)
+	}
+	return statements, p.warnings
+}
+
+// findCode returns the source of a view or routine
+func findCode(objects []CatalogCode, name string) string {
+	for _, object := range objects {
+		if strings.EqualFold(object.Name, name) {
+			return object.Definition
+		}
+	}
+	return ""
+}
+
+// table returns the qualified name of a table of the migrated schema
+func (p *migrationPlan) table(name string) string {
+	return p.qb.QualifyTable(p.schema, name)
+}
+
+// quoteList returns quoted column names separated by commas
+func (p *migrationPlan) quoteList(columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = p.qb.QuoteIdentifier(col)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// columnDefinition returns the definition of a column in CREATE and ALTER TABLE
+func (p *migrationPlan) columnDefinition(col CatalogColumn) string {
+	definition := p.qb.QuoteIdentifier(col.Name) + " " + col.Type
+	if col.Default != "" {
+		definition += " DEFAULT " + col.Default
+	}
+	if !col.Nullable {
+		definition += " NOT NULL"
+	}
+	return definition
+}
+
+// createTable adds the CREATE TABLE of a new table; foreign keys are added once every
+// table exists, except on SQLite where they can only be declared inline
+func (p *migrationPlan) createTable(table *CatalogTable) {
+	if table == nil {
+		return
+	}
+	t := &ddlTable{schema: p.schema, name: table.Name}
+	for _, col := range table.Columns {
+		t.columns = append(t.columns, ddlColumn{Name: col.Name, Type: col.Type, Nullable: col.Nullable, Default: col.Default})
+	}
+	if len(table.PrimaryKey) > 0 {
+		t.constraints = append(t.constraints, ddlConstraint{
+			Name:       "pk_" + table.Name,
+			Type:       "PRIMARY KEY",
+			Definition: fmt.Sprintf("PRIMARY KEY (%s)", p.quoteList(table.PrimaryKey)),
+		})
+	}
+	for _, fk := range table.ForeignKeys {
+		t.foreignKeys = append(t.foreignKeys, ddlConstraint{Name: fk.Name, Type: "FOREIGN KEY", Definition: p.foreignKeyDefinition(fk)})
+	}
+	for _, index := range table.Indexes {
+		t.indexes = append(t.indexes, p.createIndex(table.Name, index))
+	}
+
+	statements, deferred := renderTable(p.qb, t, func(string, string) bool { return p.qb.IsSQLite() })
+	p.creates = append(p.creates, statements...)
+	p.keys = append(p.keys, deferred...)
+}
+
+// foreignKeyDefinition returns the FOREIGN KEY clause of a foreign key
+func (p *migrationPlan) foreignKeyDefinition(fk CatalogForeignKey) string {
+	refSchema := fk.RefSchema
+	if refSchema == "" {
+		refSchema = p.schema
+	}
+	definition := fmt.Sprintf("FOREIGN KEY (%s) REFERENCES %s (%s)",
+		p.quoteList(fk.Columns), p.qb.QualifyTable(refSchema, fk.RefTable), p.quoteList(fk.RefColumns))
+	if fk.OnDelete != "" {
+		definition += " ON DELETE " + fk.OnDelete
+	}
+	if fk.OnUpdate != "" {
+		definition += " ON UPDATE " + fk.OnUpdate
+	}
+	return definition
+}
+
+// createIndex returns the CREATE INDEX statement of an index
+func (p *migrationPlan) createIndex(table string, index CatalogIndex) string {
+	unique := ""
+	if index.Unique {
+		unique = "UNIQUE "
+	}
+	return fmt.Sprintf("CREATE %sINDEX %s ON %s (%s);", unique, p.qb.QuoteIdentifier(index.Name), p.table(table), p.quoteList(index.Columns))
+}
+
+// dropIndex returns the DROP INDEX statement of an index
+func (p *migrationPlan) dropIndex(table, name string) string {
+	if p.qb.IsSQLServer() || p.qb.IsMySQL() {
+		return fmt.Sprintf("DROP INDEX %s ON %s;", p.qb.QuoteIdentifier(name), p.table(table))
+	}
+	if p.qb.IsSQLite() {
+		return fmt.Sprintf("DROP INDEX %s;", p.qb.QuoteIdentifier(name))
+	}
+	return fmt.Sprintf("DROP INDEX %s;", p.table(name))
+}
+
+// alterTable adds the statements applying the changes of a table
+func (p *migrationPlan) alterTable(name string, table *CatalogTable, changes []DiffChange) {
+	qualified := p.table(name)
+	alter := func(clause string) {
+		p.alters = append(p.alters, fmt.Sprintf("ALTER TABLE %s %s;", qualified, clause))
+	}
+	modified := make(map[string]bool)
+
+	for _, change := range changes {
+		switch change.Kind {
+		case "column":
+			switch change.Change {
+			case "added":
+				col := change.To.(CatalogColumn)
+				switch {
+				case p.qb.IsSQLServer():
+					alter("ADD " + p.columnDefinition(col))
+				case p.qb.IsOracle():
+					alter("ADD (" + p.columnDefinition(col) + ")")
+				default:
+					alter("ADD COLUMN " + p.columnDefinition(col))
+				}
+			case "removed":
+				alter("DROP COLUMN " + p.qb.QuoteIdentifier(change.Name))
+			case "changed":
+				if !modified[strings.ToLower(change.Name)] {
+					modified[strings.ToLower(change.Name)] = true
+					p.modifyColumn(name, table, change.Name, changes, alter)
+				}
+			}
+
+		case "primary_key":
+			if change.Change != "added" {
+				switch {
+				case p.qb.IsMySQL() || p.qb.IsOracle():
+					alter("DROP PRIMARY KEY")
+				default:
+					p.warnings = append(p.warnings, fmt.Sprintf("%s: drop the primary key constraint by name before adding the new one", name))
+				}
+			}
+			if change.Change != "removed" {
+				if p.qb.IsSQLite() {
+					p.warnings = append(p.warnings, fmt.Sprintf("%s: SQLite cannot change a primary key, rebuild the table", name))
+					continue
+				}
+				alter(fmt.Sprintf("ADD PRIMARY KEY (%s)", p.quoteList(table.PrimaryKey)))
+			}
+
+		case "index":
+			if change.Change != "added" {
+				p.drops = append(p.drops, p.dropIndex(name, change.Name))
+			}
+			if change.Change != "removed" {
+				p.indexes = append(p.indexes, p.createIndex(name, change.To.(CatalogIndex)))
+			}
+
+		case "foreign_key":
+			if p.qb.IsSQLite() {
+				p.warnings = append(p.warnings, fmt.Sprintf("%s: SQLite cannot add or drop foreign key %s, rebuild the table", name, change.Name))
+				continue
+			}
+			if change.Change != "added" {
+				if p.qb.IsMySQL() {
+					p.drops = append(p.drops, fmt.Sprintf("ALTER TABLE %s DROP FOREIGN KEY %s;", qualified, p.qb.QuoteIdentifier(change.Name)))
+				} else {
+					p.drops = append(p.drops, fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;", qualified, p.qb.QuoteIdentifier(change.Name)))
+				}
+			}
+			if change.Change != "removed" {
+				p.keys = append(p.keys, fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s %s;",
+					qualified, p.qb.QuoteIdentifier(change.Name), p.foreignKeyDefinition(change.To.(CatalogForeignKey))))
+			}
+		}
+	}
+}
+
+// modifyColumn adds the statements changing the type, nullability or default of a column
+func (p *migrationPlan) modifyColumn(tableName string, table *CatalogTable, name string, changes []DiffChange, alter func(string)) {
+	var col CatalogColumn
+	for _, c := range table.Columns {
+		if strings.EqualFold(c.Name, name) {
+			col = c
+		}
+	}
+	properties := make(map[string]bool)
+	for _, change := range changes {
+		if change.Kind == "column" && change.Change == "changed" && strings.EqualFold(change.Name, name) {
+			properties[change.Property] = true
+		}
+	}
+	quoted := p.qb.QuoteIdentifier(col.Name)
+
+	switch p.qb.GetDriver() {
+	case DriverPostgresSQL:
+		if properties["type"] {
+			alter(fmt.Sprintf("ALTER COLUMN %s TYPE %s", quoted, col.Type))
+		}
+		if properties["nullable"] {
+			if col.Nullable {
+				alter(fmt.Sprintf("ALTER COLUMN %s DROP NOT NULL", quoted))
+			} else {
+				alter(fmt.Sprintf("ALTER COLUMN %s SET NOT NULL", quoted))
+			}
+		}
+		if properties["default"] {
+			if col.Default == "" {
+				alter(fmt.Sprintf("ALTER COLUMN %s DROP DEFAULT", quoted))
+			} else {
+				alter(fmt.Sprintf("ALTER COLUMN %s SET DEFAULT %s", quoted, col.Default))
+			}
+		}
+	case DriverSQLServer:
+		if properties["type"] || properties["nullable"] {
+			null := "NULL"
+			if !col.Nullable {
+				null = "NOT NULL"
+			}
+			alter(fmt.Sprintf("ALTER COLUMN %s %s %s", quoted, col.Type, null))
+		}
+		if properties["default"] {
+			p.warnings = append(p.warnings, fmt.Sprintf("%s.%s: replace the default constraint by name to set the default to %q", tableName, col.Name, col.Default))
+		}
+	case DriverMySQL:
+		alter("MODIFY COLUMN " + p.columnDefinition(col))
+	case DriverOracle:
+		definition := quoted
+		if properties["type"] {
+			definition += " " + col.Type
+		}
+		if properties["default"] {
+			if col.Default == "" {
+				definition += " DEFAULT NULL"
+			} else {
+				definition += " DEFAULT " + col.Default
+			}
+		}
+		if properties["nullable"] {
+			if col.Nullable {
+				definition += " NULL"
+			} else {
+				definition += " NOT NULL"
+			}
+		}
+		alter("MODIFY (" + definition + ")")
+	default:
+		p.warnings = append(p.warnings, fmt.Sprintf("%s.%s: SQLite cannot alter a column, rebuild the table", tableName, col.Name))
+	}
+}
+
+// createCode adds the statement creating, or replacing when replace is set, a view or routine
+func (p *migrationPlan) createCode(objectType, name, definition string, replace bool) {
+	definition = strings.TrimSpace(definition)
+	if definition == "" {
+		p.warnings = append(p.warnings, fmt.Sprintf("%s %s: the source is not available", objectType, name))
+		return
+	}
+
+	var statement string
+	switch {
+	case reCreatePrefix.MatchString(definition):
+		statement = definition
+	case objectType == "view":
+		statement = fmt.Sprintf("CREATE VIEW %s AS\n%s", p.table(name), strings.TrimRight(definition, "; \n"))
+	case p.qb.IsOracle():
+		statement = "CREATE " + definition
+	default:
+		// MySQL keeps the body of routines without their parameters
+		p.warnings = append(p.warnings, fmt.Sprintf("%s %s: only the body is stored, recreate it by hand", objectType, name))
+		return
+	}
+
+	if replace {
+		switch {
+		case p.qb.IsSQLServer():
+			statement = reCreatePrefix.ReplaceAllString(statement, "CREATE OR ALTER ")
+		case p.qb.IsSQLite():
+			p.code = append(p.code, fmt.Sprintf("DROP VIEW %s;", p.table(name)))
+		case p.qb.IsMySQL() && objectType != "view":
+			p.code = append(p.code, fmt.Sprintf("DROP %s %s;", strings.ToUpper(objectType), p.table(name)))
+		default:
+			statement = reCreatePrefix.ReplaceAllString(statement, "CREATE OR REPLACE ")
+		}
+	} else if p.qb.IsOracle() {
+		statement = reCreatePrefix.ReplaceAllString(statement, "CREATE OR REPLACE ")
+	}
+
+	switch {
+	case p.qb.IsOracle() && objectType != "view":
+		// PL/SQL blocks end with a slash in scripts
+		p.code = append(p.code, strings.TrimRight(statement, " \n")+"\n/")
+	case p.qb.IsSQLServer():
+		// CREATE VIEW, PROCEDURE and FUNCTION must start a batch
+		p.code = append(p.code, "GO\n"+terminateStatement(statement)+"\nGO")
+	default:
+		p.code = append(p.code, terminateStatement(statement))
+	}
+}
@@ -1,14 +1,23 @@
 package mcp
 
 import (
+	"os"
+	"time"
+
 	"github.com/mark3labs/mcp-go/server"
 )
 
 // NewMcpServer creates a new MCP server instance.
 // If DB_CONNECTION_STRING is not set, the server starts without a database connection.
 // Use the configure_datasource tool to connect to a database dynamically.
+// If DB_MCP_CONFIG is set, the configuration file is read from that path.
 func NewMcpServer() (*DbMCPServer, error) {
-	db, driver, err := newDbConnection()
+	config, err := LoadConfig(os.Getenv("DB_MCP_CONFIG"))
+	if err != nil {
+		return nil, err
+	}
+
+	db, driver, err := newDbConnection(config.Secrets)
 	if err != nil {
 		return nil, err
 	}
@@ -19,18 +28,50 @@ func NewMcpServer() (*DbMCPServer, error) {
 	}
 
 	dbMCPServer := &DbMCPServer{
-		server: server.NewMCPServer(
-			"Database MCP",
-			"1.0.0",
-			server.WithToolCapabilities(true),
-		),
 		db:           db,
 		queryBuilder: queryBuilder,
+		config:       config,
+		limiter:      NewLimiter(),
+	}
+	if db != nil {
+		dbMCPServer.datasource, dbMCPServer.profile = DefaultDataSourceName, DefaultDataSourceName
+	}
+
+	if config.Audit != nil {
+		if dbMCPServer.audit, err = OpenAuditLog(config.Audit); err != nil {
+			return nil, err
+		}
 	}
 
+	if dbMCPServer.history, err = NewHistoryStore(config.History); err != nil {
+		return nil, err
+	}
+
+	dbMCPServer.server = server.NewMCPServer(
+		"Database MCP",
+		"1.0.0",
+		server.WithToolCapabilities(true),
+		server.WithToolHandlerMiddleware(dbMCPServer.auditMiddleware),
+		server.WithToolHandlerMiddleware(dbMCPServer.limitsMiddleware),
+		server.WithToolHandlerMiddleware(dbMCPServer.historyMiddleware),
+	)
+
 	// Register tools
 	dbMCPServer.registerTools()
 
+	// Register the saved queries and reload them when their directory changes
+	if config.SavedQueries != nil {
+		dbMCPServer.savedQueries = &savedQueryCatalog{dir: config.SavedQueries.Dir, stop: make(chan struct{})}
+		if err := dbMCPServer.loadSavedQueries(); err != nil {
+			return nil, err
+		}
+		interval := DefaultSavedQueriesReload
+		if config.SavedQueries.ReloadIntervalSeconds > 0 {
+			interval = time.Duration(config.SavedQueries.ReloadIntervalSeconds) * time.Second
+		}
+		go dbMCPServer.watchSavedQueries(interval)
+	}
+
 	return dbMCPServer, nil
 }
 
@@ -39,8 +80,17 @@ func (s *DbMCPServer) Start() error {
 	return server.ServeStdio(s.server)
 }
 
-// Close closes the database connection if it exists
+// Close closes the database connection, the audit log and the query history if they exist
 func (s *DbMCPServer) Close() error {
+	if s.savedQueries != nil {
+		close(s.savedQueries.stop)
+	}
+	if s.audit != nil {
+		s.audit.Close()
+	}
+	if s.history != nil {
+		s.history.Close()
+	}
 	if s.db != nil {
 		return s.db.Close()
 	}
@@ -0,0 +1,248 @@
+package mcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SnapshotInfo describes a snapshot file of the snapshots directory
+type SnapshotInfo struct {
+	Name       string     `json:"name"`
+	Datasource string     `json:"datasource,omitempty"`
+	Driver     DriverType `json:"driver,omitempty"`
+	Schema     string     `json:"schema,omitempty"`
+	TakenAt    string     `json:"taken_at,omitempty"`
+	Hash       string     `json:"hash,omitempty"`
+	Tables     int        `json:"tables"`
+	Views      int        `json:"views"`
+	Procedures int        `json:"procedures"`
+	Functions  int        `json:"functions"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// reSnapshotName matches the snapshot file names accepted by the tools
+var reSnapshotName = regexp.MustCompile(`^[A-Za-z0-9_-][A-Za-z0-9_.-]*$`)
+
+// reSnapshotNameChars matches the characters replaced in a default snapshot name
+var reSnapshotNameChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// snapshotInfo summarizes a snapshot
+func snapshotInfo(name string, catalog *SchemaCatalog) *SnapshotInfo {
+	return &SnapshotInfo{
+		Name:       name,
+		Datasource: catalog.Datasource,
+		Driver:     catalog.Driver,
+		Schema:     catalog.Schema,
+		TakenAt:    catalog.TakenAt,
+		Hash:       catalog.Hash,
+		Tables:     len(catalog.Tables),
+		Views:      len(catalog.Views),
+		Procedures: len(catalog.Procedures),
+		Functions:  len(catalog.Functions),
+	}
+}
+
+// contentHash returns the SHA-256 of the catalog without its hash, time and
+// datasource, so identical schemas have the same hash wherever they were taken
+func (c *SchemaCatalog) contentHash() (string, error) {
+	content := *c
+	content.Hash, content.TakenAt, content.Datasource = "", "", ""
+	data, err := json.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// snapshotPath returns the file of a snapshot in the snapshots directory
+func (s *DbMCPServer) snapshotPath(name string) (string, string, error) {
+	if s.config == nil || s.config.Snapshots == nil {
+		return "", "", ErrSnapshotsDisabled
+	}
+	if !reSnapshotName.MatchString(name) {
+		return "", "", fmt.Errorf("%w: '%s'", ErrInvalidSnapshotName, name)
+	}
+	name = strings.TrimSuffix(name, ".json")
+	return name, filepath.Join(s.config.Snapshots.Dir, name+".json"), nil
+}
+
+// readSnapshot loads a snapshot file of the snapshots directory
+func (s *DbMCPServer) readSnapshot(name string) (*SchemaCatalog, error) {
+	name, path, err := s.snapshotPath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrSnapshotNotFound, name)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrReadingSnapshot, err)
+	}
+	catalog := &SchemaCatalog{}
+	if err := json.Unmarshal(data, catalog); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrReadingSnapshot, name, err)
+	}
+	if catalog.Version < 1 || catalog.Version > SchemaCatalogVersion {
+		return nil, fmt.Errorf("%w: %s has version %d, this server reads up to %d", ErrReadingSnapshot, name, catalog.Version, SchemaCatalogVersion)
+	}
+	return catalog, nil
+}
+
+// takeSnapshot saves the catalog of a schema of a datasource (default: the active
+// one) as a snapshot. The default name is the datasource and schema, with the time
+// appended when timestamped. A snapshot whose content has not changed is left as
+// it is, so the file only changes when the schema does; written reports which.
+func (s *DbMCPServer) takeSnapshot(ctx context.Context, datasource, schema, name string, timestamped bool) (*SnapshotInfo, bool, error) {
+	if s.config == nil || s.config.Snapshots == nil {
+		return nil, false, ErrSnapshotsDisabled
+	}
+	if name != "" {
+		if _, _, err := s.snapshotPath(name); err != nil {
+			return nil, false, err
+		}
+	}
+
+	reader, closeReader, err := s.catalogReader(ctx, datasource)
+	if err != nil {
+		return nil, false, err
+	}
+	defer closeReader()
+
+	catalog, err := reader.loadCatalog(ctx, schema)
+	if err != nil {
+		return nil, false, err
+	}
+	if catalog.Hash, err = catalog.contentHash(); err != nil {
+		return nil, false, fmt.Errorf("%w: %v", ErrWritingSnapshot, err)
+	}
+	now := time.Now().UTC()
+	catalog.TakenAt = now.Format(time.RFC3339)
+
+	if name == "" {
+		source := catalog.Datasource
+		if source == "" {
+			source = "connection"
+		}
+		name = reSnapshotNameChars.ReplaceAllString(source+"_"+catalog.Schema, "_")
+		name = strings.TrimLeft(name, ".")
+	}
+	if timestamped {
+		name = strings.TrimSuffix(name, ".json") + "_" + now.Format("20060102T150405Z")
+	}
+	name, path, err := s.snapshotPath(name)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if previous, err := s.readSnapshot(name); err == nil && previous.Hash == catalog.Hash {
+		return snapshotInfo(name, previous), false, nil
+	}
+
+	data, err := json.MarshalIndent(catalog, "", "  ")
+	if err != nil {
+		return nil, false, fmt.Errorf("%w: %v", ErrWritingSnapshot, err)
+	}
+	if err := writeFileAtomic(path, append(data, '\n')); err != nil {
+		return nil, false, fmt.Errorf("%w: %v", ErrWritingSnapshot, err)
+	}
+	return snapshotInfo(name, catalog), true, nil
+}
+
+// listSnapshots returns the snapshots of the snapshots directory by name; files
+// that cannot be read are listed with the error
+func (s *DbMCPServer) listSnapshots() ([]*SnapshotInfo, error) {
+	if s.config == nil || s.config.Snapshots == nil {
+		return nil, ErrSnapshotsDisabled
+	}
+
+	entries, err := os.ReadDir(s.config.Snapshots.Dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*SnapshotInfo{}, nil
+		}
+		return nil, fmt.Errorf("%w: %v", ErrListingSnapshots, err)
+	}
+
+	snapshots := []*SnapshotInfo{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") || !reSnapshotName.MatchString(entry.Name()) {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), ".json")
+		catalog, err := s.readSnapshot(name)
+		if err != nil {
+			snapshots = append(snapshots, &SnapshotInfo{Name: name, Error: err.Error()})
+			continue
+		}
+		snapshots = append(snapshots, snapshotInfo(name, catalog))
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Name < snapshots[j].Name })
+	return snapshots, nil
+}
+
+// writeFileAtomic replaces a file through a temporary file in the same directory,
+// so readers never see a partial file
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, ".snapshot-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// SnapshotSchema saves a snapshot of a schema from the command line, connecting as
+// the server would: to the DB_CONNECTION_STRING database, or to a datasource
+// declared in the DB_MCP_CONFIG file
+func SnapshotSchema(datasource, schema, name string, timestamped bool) (*SnapshotInfo, bool, error) {
+	config, err := LoadConfig(os.Getenv("DB_MCP_CONFIG"))
+	if err != nil {
+		return nil, false, err
+	}
+	if schema != "" && !isValidIdentifier(schema) {
+		return nil, false, fmt.Errorf("%w: %s", ErrInvalidSchemaName, schema)
+	}
+
+	s := &DbMCPServer{config: config}
+	if datasource == "" || datasource == DefaultDataSourceName {
+		db, driver, err := newDbConnection(config.Secrets)
+		if err != nil {
+			return nil, false, err
+		}
+		if db != nil {
+			defer db.Close()
+			s.db, s.queryBuilder, s.datasource, s.profile = db, NewQueryBuilder(driver), DefaultDataSourceName, DefaultDataSourceName
+		}
+		datasource = ""
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultQueryTimeout)
+	defer cancel()
+	return s.takeSnapshot(ctx, datasource, schema, name, timestamped)
+}
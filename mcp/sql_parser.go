@@ -0,0 +1,747 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// sqlTokenKind classifies the tokens of a SQL statement
+type sqlTokenKind int
+
+const (
+	sqlTokenWord      sqlTokenKind = iota // bare identifier or keyword
+	sqlTokenQuoted                        // quoted identifier: "x", [x] or `x`
+	sqlTokenString                        // string literal
+	sqlTokenNumber                        // numeric literal
+	sqlTokenParameter                     // bind parameter or variable: ?, $1, :name, @name
+	sqlTokenSymbol                        // punctuation and operators
+)
+
+type sqlToken struct {
+	kind sqlTokenKind
+	// text is the word or symbol as written, or the content of a quoted identifier or literal
+	text string
+	// start and end are the rune offsets of the token in the statement
+	start, end int
+}
+
+// is reports whether the token is the given keyword or symbol (case-insensitive)
+func (t sqlToken) is(text string) bool {
+	return (t.kind == sqlTokenWord || t.kind == sqlTokenSymbol) && strings.EqualFold(t.text, text)
+}
+
+// isIdentifier reports whether the token can name a table, column or alias
+func (t sqlToken) isIdentifier() bool {
+	return t.kind == sqlTokenQuoted || (t.kind == sqlTokenWord && !sqlKeywords[strings.ToUpper(t.text)])
+}
+
+// sqlKeywords are the reserved words that never name a column or an alias
+var sqlKeywords = map[string]bool{
+	"ALL": true, "AND": true, "ANY": true, "APPLY": true, "ARRAY": true, "AS": true, "ASC": true,
+	"AT": true, "BETWEEN": true, "BINARY": true, "BOTH": true, "BY": true, "CASE": true, "CAST": true,
+	"COLLATE": true, "CROSS": true, "CURRENT": true, "DATE": true, "DESC": true, "DISTINCT": true,
+	"DIV": true, "ELSE": true, "END": true, "ESCAPE": true, "EXCEPT": true, "EXISTS": true,
+	"FALSE": true, "FETCH": true, "FILTER": true, "FIRST": true, "FOLLOWING": true, "FOR": true,
+	"FROM": true, "FULL": true, "GLOB": true, "GROUP": true, "HAVING": true, "ILIKE": true,
+	"IN": true, "INNER": true, "INTERSECT": true, "INTERVAL": true, "IS": true, "ISNULL": true,
+	"JOIN": true, "LAST": true, "LATERAL": true, "LEADING": true, "LEFT": true, "LIKE": true,
+	"LIMIT": true, "MATCH": true, "MINUS": true, "MOD": true, "NATURAL": true, "NEXT": true,
+	"NOT": true, "NOTNULL": true, "NULL": true, "NULLS": true, "OF": true, "OFFSET": true,
+	"ON": true, "ONLY": true, "OR": true, "ORDER": true, "OUTER": true, "OVER": true,
+	"OVERLAPS": true, "PARTITION": true, "PERCENT": true, "PRECEDING": true, "PRIOR": true,
+	"RANGE": true, "RECURSIVE": true, "REGEXP": true, "RIGHT": true, "RLIKE": true, "ROW": true,
+	"ROWS": true, "SELECT": true, "SIMILAR": true, "SOME": true, "SOUNDS": true, "TABLE": true,
+	"THEN": true, "TIES": true, "TIME": true, "TIMESTAMP": true, "TO": true, "TOP": true,
+	"TRAILING": true, "TRUE": true, "UNBOUNDED": true, "UNION": true, "UNIQUE": true,
+	"USING": true, "VALUES": true, "WHEN": true, "WHERE": true, "WINDOW": true, "WITH": true,
+	"WITHIN": true, "XOR": true, "ZONE": true,
+}
+
+// sqlSelectModifiers are the MySQL words allowed between SELECT and the first select list item
+var sqlSelectModifiers = map[string]bool{
+	"DISTINCTROW": true, "HIGH_PRIORITY": true, "STRAIGHT_JOIN": true, "SQL_SMALL_RESULT": true,
+	"SQL_BIG_RESULT": true, "SQL_BUFFER_RESULT": true, "SQL_CACHE": true, "SQL_NO_CACHE": true,
+	"SQL_CALC_FOUND_ROWS": true,
+}
+
+// sqlBuiltinWords are the unreserved words that do not name a column when no referenced
+// table has a column of that name: niladic functions and pseudo-columns, date parts,
+// type names and table hints
+var sqlBuiltinWords = map[string]bool{
+	"CURRENT_DATE": true, "CURRENT_TIME": true, "CURRENT_TIMESTAMP": true, "CURRENT_USER": true,
+	"CURRENT_SCHEMA": true, "CURRENT_CATALOG": true, "CURRENT_ROLE": true, "SESSION_USER": true,
+	"SYSTEM_USER": true, "USER": true, "LOCALTIME": true, "LOCALTIMESTAMP": true, "SYSDATE": true,
+	"SYSTIMESTAMP": true, "ROWNUM": true, "LEVEL": true, "UNKNOWN": true,
+	"YEAR": true, "QUARTER": true, "MONTH": true, "WEEK": true, "DAY": true, "HOUR": true,
+	"MINUTE": true, "SECOND": true, "MILLISECOND": true, "MICROSECOND": true, "EPOCH": true,
+	"DOW": true, "DOY": true, "ISODOW": true, "ISOYEAR": true, "DAYOFWEEK": true, "DAYOFYEAR": true,
+	"WEEKDAY": true, "DECADE": true, "CENTURY": true, "MILLENNIUM": true, "TIMEZONE_HOUR": true,
+	"TIMEZONE_MINUTE": true, "YY": true, "YYYY": true, "QQ": true, "MM": true, "DD": true,
+	"WK": true, "HH": true, "MI": true, "SS": true, "MS": true,
+	"INT": true, "INTEGER": true, "SMALLINT": true, "BIGINT": true, "TINYINT": true,
+	"DECIMAL": true, "NUMERIC": true, "NUMBER": true, "FLOAT": true, "REAL": true, "DOUBLE": true,
+	"PRECISION": true, "MONEY": true, "BIT": true, "BOOLEAN": true, "CHAR": true, "NCHAR": true,
+	"CHARACTER": true, "VARYING": true, "VARCHAR": true, "NVARCHAR": true, "VARCHAR2": true,
+	"NVARCHAR2": true, "TEXT": true, "NTEXT": true, "CLOB": true, "BLOB": true, "DATETIME": true,
+	"DATETIME2": true, "DATETIMEOFFSET": true, "SMALLDATETIME": true, "UUID": true,
+	"UNIQUEIDENTIFIER": true, "JSON": true, "JSONB": true, "SIGNED": true, "UNSIGNED": true,
+	"NOLOCK": true, "READUNCOMMITTED": true, "READCOMMITTED": true, "REPEATABLEREAD": true,
+	"SERIALIZABLE": true, "HOLDLOCK": true, "UPDLOCK": true, "ROWLOCK": true, "PAGLOCK": true,
+	"TABLOCK": true, "TABLOCKX": true, "NOWAIT": true, "READPAST": true, "XLOCK": true,
+}
+
+// sqlClauses are the reserved words that start a clause of a SELECT statement
+var sqlClauses = map[string]bool{
+	"SELECT": true, "FROM": true, "WHERE": true, "GROUP": true, "HAVING": true, "ORDER": true,
+	"LIMIT": true, "OFFSET": true, "FETCH": true, "UNION": true, "INTERSECT": true, "EXCEPT": true,
+}
+
+// tokenizeSQL splits a statement into tokens, dropping whitespace and comments.
+// Only PostgreSQL E'...' strings take backslash escapes.
+func tokenizeSQL(query string) ([]sqlToken, error) {
+	return tokenizeSQLStrings(query, false)
+}
+
+// tokenizeSQLStrings splits a statement into tokens. With backslashEscapes, a backslash
+// escapes the next character of '...' and "..." strings, as in MySQL by default.
+func tokenizeSQLStrings(query string, backslashEscapes bool) ([]sqlToken, error) {
+	src := []rune(query)
+	var tokens []sqlToken
+
+	// readUntil returns the text up to the closing delimiter, where a doubled delimiter
+	// escapes it, and so does a backslash when backslash is set
+	readUntil := func(start int, closing rune, backslash bool) (string, int, error) {
+		var text strings.Builder
+		for i := start; i < len(src); i++ {
+			if backslash && src[i] == '\\' && i+1 < len(src) {
+				text.WriteRune(src[i+1])
+				i++
+				continue
+			}
+			if src[i] == closing {
+				if i+1 < len(src) && src[i+1] == closing {
+					text.WriteRune(closing)
+					i++
+					continue
+				}
+				return text.String(), i + 1, nil
+			}
+			text.WriteRune(src[i])
+		}
+		return "", 0, fmt.Errorf("unterminated %c", closing)
+	}
+
+	isWordRune := func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '$' || r == '#'
+	}
+
+	for i := 0; i < len(src); {
+		pos, count := i, len(tokens)
+		r := src[i]
+		next := rune(0)
+		if i+1 < len(src) {
+			next = src[i+1]
+		}
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '-' && next == '-':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+
+		case r == '/' && next == '*':
+			end := strings.Index(string(src[i+2:]), "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated comment")
+			}
+			i += 2 + len([]rune(string(src[i+2:])[:end])) + 2
+
+		case r == '\'':
+			text, end, err := readUntil(i+1, '\'', backslashEscapes)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, sqlToken{kind: sqlTokenString, text: text})
+			i = end
+
+		case (r == 'N' || r == 'n' || r == 'E' || r == 'e' || r == 'X' || r == 'x' || r == 'B' || r == 'b') && next == '\'':
+			// Prefixed string literal: N'...', E'...', X'...', B'...'
+			text, end, err := readUntil(i+2, '\'', backslashEscapes || r == 'E' || r == 'e')
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, sqlToken{kind: sqlTokenString, text: text})
+			i = end
+
+		case r == '"' || r == '`':
+			text, end, err := readUntil(i+1, r, backslashEscapes && r == '"')
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, sqlToken{kind: sqlTokenQuoted, text: text})
+			i = end
+
+		case r == '[':
+			text, end, err := readUntil(i+1, ']', false)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, sqlToken{kind: sqlTokenQuoted, text: text})
+			i = end
+
+		case r == '$' && (next == '$' || unicode.IsLetter(next) || next == '_'):
+			// PostgreSQL dollar-quoted string: $tag$...$tag$
+			tagEnd := i + 1
+			for tagEnd < len(src) && src[tagEnd] != '$' && isWordRune(src[tagEnd]) {
+				tagEnd++
+			}
+			if tagEnd >= len(src) || src[tagEnd] != '$' {
+				return nil, fmt.Errorf("invalid dollar-quoted string")
+			}
+			tag := string(src[i : tagEnd+1])
+			rest := string(src[tagEnd+1:])
+			end := strings.Index(rest, tag)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated dollar-quoted string")
+			}
+			tokens = append(tokens, sqlToken{kind: sqlTokenString, text: rest[:end]})
+			i = tagEnd + 1 + len([]rune(rest[:end])) + len([]rune(tag))
+
+		case r == '?' || (r == '$' && unicode.IsDigit(next)) || r == '@' || (r == ':' && (unicode.IsLetter(next) || unicode.IsDigit(next))):
+			start := i
+			i++
+			for i < len(src) && (isWordRune(src[i]) || src[i] == '@') {
+				i++
+			}
+			tokens = append(tokens, sqlToken{kind: sqlTokenParameter, text: string(src[start:i])})
+
+		case unicode.IsDigit(r) || (r == '.' && unicode.IsDigit(next)):
+			start := i
+			for i < len(src) && (unicode.IsDigit(src[i]) || src[i] == '.' || src[i] == 'e' || src[i] == 'E' ||
+				((src[i] == '+' || src[i] == '-') && (src[i-1] == 'e' || src[i-1] == 'E'))) {
+				i++
+			}
+			tokens = append(tokens, sqlToken{kind: sqlTokenNumber, text: string(src[start:i])})
+
+		case unicode.IsLetter(r) || r == '_' || r == '#':
+			start := i
+			for i < len(src) && isWordRune(src[i]) {
+				i++
+			}
+			tokens = append(tokens, sqlToken{kind: sqlTokenWord, text: string(src[start:i])})
+
+		case r == ':' && next == ':':
+			tokens = append(tokens, sqlToken{kind: sqlTokenSymbol, text: "::"})
+			i += 2
+
+		default:
+			tokens = append(tokens, sqlToken{kind: sqlTokenSymbol, text: string(r)})
+			i++
+		}
+		if len(tokens) > count {
+			tokens[count].start, tokens[count].end = pos, i
+		}
+	}
+
+	return tokens, nil
+}
+
+// TableReference is a table read by a query
+type TableReference struct {
+	Schema string
+	Name   string
+	Alias  string
+}
+
+// ColumnReference is a column read by a query. Qualifier is the table name or alias
+// it is qualified with (empty when unqualified) and Schema is set for schema.table.column
+// references. Column is "*" for star projections.
+type ColumnReference struct {
+	Schema    string
+	Qualifier string
+	Column    string
+
+	// pos and end are the token range of the reference
+	pos, end int
+}
+
+// SelectItem is an item of a select list of the outer query
+type SelectItem struct {
+	// Branch numbers the SELECTs combined by UNION, INTERSECT or EXCEPT, from 0
+	Branch int
+	// Alias is the name given to the item, empty when none
+	Alias string
+	// Columns are the columns the item reads, those of its subqueries included
+	Columns []ColumnReference
+	// Plain is set when the item is a single column reference, such as a.b or t.*
+	Plain bool
+
+	start, exprEnd, end int
+}
+
+// SQLReferences are the tables and columns referenced by a query
+type SQLReferences struct {
+	Tables  []TableReference
+	Columns []ColumnReference
+	// Items are the select list items of the outer query, in order
+	Items []SelectItem
+	// derived holds CTE names and derived table aliases (lower case)
+	derived map[string]bool
+	// aliases holds select list aliases and the column names given to CTEs and
+	// derived tables (lower case)
+	aliases map[string]bool
+	natural bool
+}
+
+// IsDerived reports whether name is a CTE or a derived table alias rather than a table
+func (r *SQLReferences) IsDerived(name string) bool {
+	return r.derived[strings.ToLower(name)]
+}
+
+// IsAlias reports whether name is a column alias defined by the query
+func (r *SQLReferences) IsAlias(name string) bool {
+	return r.aliases[strings.ToLower(name)]
+}
+
+// HasNaturalJoin reports whether the query joins tables on their common columns
+func (r *SQLReferences) HasNaturalJoin() bool {
+	return r.natural
+}
+
+// sqlScope is the parse state of one parenthesis level
+type sqlScope struct {
+	clause string
+	// selectSeen is set once the level has a SELECT, so that FROM starts a table list
+	selectSeen bool
+	// itemStart is the index of the first select list item, after the SELECT modifiers
+	itemStart int
+	// derived is set for parentheses opened where a table was expected
+	derived bool
+}
+
+// ParseSQLReferences extracts the tables and columns referenced by a SELECT statement
+func ParseSQLReferences(query string) (*SQLReferences, error) {
+	tokens, err := tokenizeSQL(query)
+	if err != nil {
+		return nil, err
+	}
+	return parseSQLTokens(tokens), nil
+}
+
+// parseSQLReadings parses a statement as its string literals read with and without
+// backslash escapes, which depends on the database and its settings (MySQL
+// NO_BACKSLASH_ESCAPES, PostgreSQL standard_conforming_strings). It returns one
+// reading when the query has no backslash, and fails only when no reading parses.
+func parseSQLReadings(query string) ([]*SQLReferences, error) {
+	modes := []bool{false}
+	if strings.ContainsRune(query, '\\') {
+		modes = append(modes, true)
+	}
+
+	var readings []*SQLReferences
+	var firstErr error
+	for _, backslashEscapes := range modes {
+		tokens, err := tokenizeSQLStrings(query, backslashEscapes)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		readings = append(readings, parseSQLTokens(tokens))
+	}
+	if len(readings) == 0 {
+		return nil, firstErr
+	}
+	return readings, nil
+}
+
+// parseSQLTokens extracts the tables and columns referenced by a tokenized statement
+func parseSQLTokens(tokens []sqlToken) *SQLReferences {
+
+	refs := &SQLReferences{derived: make(map[string]bool), aliases: make(map[string]bool)}
+	scopes := []sqlScope{{itemStart: -1}}
+	expectTable := false
+	branch, openItem := -1, -1
+
+	at := func(i int) sqlToken {
+		if i >= 0 && i < len(tokens) {
+			return tokens[i]
+		}
+		return sqlToken{kind: sqlTokenSymbol}
+	}
+
+	// readName reads a dotted name starting at i, returning its parts and the next index.
+	// A trailing ".*" is returned as a "*" part.
+	readName := func(i int) ([]string, int) {
+		parts := []string{tokens[i].text}
+		i++
+		for at(i).is(".") {
+			if at(i + 1).is("*") {
+				return append(parts, "*"), i + 2
+			}
+			if at(i+1).kind != sqlTokenWord && at(i+1).kind != sqlTokenQuoted {
+				break
+			}
+			parts = append(parts, at(i+1).text)
+			i += 2
+		}
+		return parts, i
+	}
+
+	// openItemAt starts a select list item of the outer query at i; closeItem ends
+	// the current one before i
+	openItemAt := func(i int) {
+		refs.Items = append(refs.Items, SelectItem{Branch: branch, start: i})
+		openItem = len(refs.Items) - 1
+	}
+	closeItem := func(i int) {
+		if openItem < 0 {
+			return
+		}
+		item := &refs.Items[openItem]
+		item.end = i
+		if item.exprEnd == 0 {
+			item.exprEnd = i
+		}
+		openItem = -1
+	}
+
+	// readColumnAliases records the column names of a CTE or derived table listed in the
+	// parentheses at i, returning the index after them
+	readColumnAliases := func(i int) int {
+		end := skipSQLParens(tokens, i)
+		for j := i + 1; j < end; j++ {
+			if tokens[j].isIdentifier() {
+				refs.aliases[strings.ToLower(tokens[j].text)] = true
+			}
+		}
+		return end
+	}
+
+	// readAlias reads an optional [AS] alias at i, returning it and the next index
+	readAlias := func(i int) (string, int) {
+		if at(i).is("AS") && at(i+1).isIdentifier() {
+			return at(i + 1).text, i + 2
+		}
+		if at(i).isIdentifier() {
+			return at(i).text, i + 1
+		}
+		return "", i
+	}
+
+	for i := 0; i < len(tokens); {
+		tok := tokens[i]
+		scope := &scopes[len(scopes)-1]
+		word := strings.ToUpper(tok.text)
+
+		// Table position: after FROM, JOIN, APPLY or a comma of the FROM list
+		if expectTable {
+			switch {
+			case tok.is("LATERAL") || tok.is("ONLY"):
+				i++
+				continue
+			case tok.is("("):
+				expectTable = false
+				inner := at(i + 1)
+				if inner.is("SELECT") || inner.is("WITH") || inner.is("VALUES") {
+					scopes = append(scopes, sqlScope{itemStart: -1, derived: true})
+				} else {
+					// Parenthesized join
+					scopes = append(scopes, sqlScope{clause: "FROM", itemStart: -1, derived: true})
+					expectTable = true
+				}
+				i++
+				continue
+			case tok.kind == sqlTokenWord || tok.kind == sqlTokenQuoted:
+				expectTable = false
+				parts, next := readName(i)
+				if at(next).is("(") {
+					// Table-valued function: its arguments are parsed as expressions
+					scopes = append(scopes, sqlScope{itemStart: -1, derived: true})
+					i = next + 1
+					continue
+				}
+				ref := TableReference{Name: parts[len(parts)-1]}
+				if len(parts) > 1 {
+					ref.Schema = parts[len(parts)-2]
+				}
+				ref.Alias, next = readAlias(next)
+				if ref.Alias != "" && at(next).is("(") {
+					next = readColumnAliases(next)
+				}
+				if ref.Schema == "" && refs.IsDerived(ref.Name) {
+					// Reference to a CTE
+					if ref.Alias != "" {
+						refs.derived[strings.ToLower(ref.Alias)] = true
+					}
+				} else {
+					refs.Tables = append(refs.Tables, ref)
+				}
+				i = next
+				continue
+			}
+			expectTable = false
+		}
+
+		switch {
+		case tok.is("("):
+			scopes = append(scopes, sqlScope{itemStart: -1})
+			i++
+
+		case tok.is(")"):
+			closed := *scope
+			if len(scopes) > 1 {
+				scopes = scopes[:len(scopes)-1]
+			}
+			i++
+			if closed.derived {
+				alias, next := readAlias(i)
+				if alias != "" {
+					refs.derived[strings.ToLower(alias)] = true
+					if at(next).is("(") {
+						next = readColumnAliases(next)
+					}
+				}
+				i = next
+			}
+
+		case tok.is("WITH") && (i == 0 || at(i-1).is("(")):
+			// Common table expressions: WITH [RECURSIVE] name [(columns)] AS [[NOT] MATERIALIZED] (...), ...
+			scope.clause = "WITH"
+			i++
+			if at(i).is("RECURSIVE") {
+				i++
+			}
+
+		case scope.clause == "WITH" && tok.isIdentifier() && (at(i+1).is("AS") || at(i+1).is("(")):
+			refs.derived[strings.ToLower(tok.text)] = true
+			i++
+			if at(i).is("(") {
+				i = readColumnAliases(i)
+			}
+			for at(i).is("AS") || at(i).is("NOT") || strings.EqualFold(at(i).text, "MATERIALIZED") {
+				i++
+			}
+
+		case tok.is("FROM"):
+			// FROM lists tables only after a SELECT on the same level:
+			// not in EXTRACT(x FROM y) nor in IS [NOT] DISTINCT FROM
+			if scope.selectSeen && !(at(i-1).is("DISTINCT") && (at(i-2).is("IS") || at(i-2).is("NOT"))) {
+				scope.clause = "FROM"
+				expectTable = true
+				if len(scopes) == 1 {
+					closeItem(i)
+				}
+			}
+			i++
+
+		case tok.kind == sqlTokenWord && sqlClauses[word]:
+			scope.clause = word
+			if len(scopes) == 1 {
+				closeItem(i)
+			}
+			if word == "SELECT" {
+				scope.selectSeen = true
+				scope.itemStart = selectListStart(tokens, i+1)
+				if len(scopes) == 1 {
+					branch++
+					openItemAt(scope.itemStart)
+				}
+			}
+			i++
+
+		case tok.is("JOIN") || tok.is("APPLY"):
+			expectTable = true
+			if tok.is("JOIN") && isNaturalJoin(tokens, i) {
+				refs.natural = true
+			}
+			i++
+
+		case tok.is("TABLE") && at(i+1).isIdentifier() && at(i-1).is("("):
+			// PostgreSQL TABLE name shorthand for SELECT * FROM name
+			expectTable = true
+			i++
+
+		case tok.is(",") && scope.clause == "FROM":
+			expectTable = true
+			i++
+
+		case tok.is(",") && scope.clause == "SELECT" && len(scopes) == 1 && openItem >= 0:
+			closeItem(i)
+			openItemAt(i + 1)
+			i++
+
+		case tok.is("*"):
+			if scope.clause == "SELECT" && (i == scope.itemStart || isSelectItemStart(tokens, i)) {
+				refs.Columns = append(refs.Columns, ColumnReference{Column: "*", pos: i, end: i + 1})
+			}
+			i++
+
+		case tok.isIdentifier():
+			if scope.clause == "SELECT" && i < scope.itemStart && sqlSelectModifiers[word] {
+				i++
+				continue
+			}
+			parts, next := readName(i)
+			if at(next).is("(") {
+				// Function call
+				i = next
+				continue
+			}
+			if len(parts) == 1 && isAliasPosition(tokens, i, scope.clause, scope.itemStart) {
+				if scope.clause == "SELECT" {
+					refs.aliases[strings.ToLower(tok.text)] = true
+					if len(scopes) == 1 && openItem >= 0 {
+						item := &refs.Items[openItem]
+						item.Alias, item.exprEnd = tok.text, i
+						if at(i - 1).is("AS") {
+							item.exprEnd = i - 1
+						}
+					}
+				}
+				i = next
+				continue
+			}
+			ref := ColumnReference{Column: parts[len(parts)-1], pos: i, end: next}
+			if len(parts) > 1 {
+				ref.Qualifier = parts[len(parts)-2]
+			}
+			if len(parts) > 2 {
+				ref.Schema = parts[len(parts)-3]
+			}
+			refs.Columns = append(refs.Columns, ref)
+			i = next
+
+		default:
+			i++
+		}
+	}
+
+	closeItem(len(tokens))
+	for i := range refs.Items {
+		item := &refs.Items[i]
+		for _, ref := range refs.Columns {
+			if ref.pos >= item.start && ref.pos < item.end {
+				item.Columns = append(item.Columns, ref)
+			}
+		}
+		item.Plain = len(item.Columns) == 1 && item.Columns[0].pos == item.start && item.Columns[0].end == item.exprEnd
+	}
+
+	return refs
+}
+
+// skipSQLParens skips the parenthesized list starting at i, returning the index after it
+func skipSQLParens(tokens []sqlToken, i int) int {
+	depth := 0
+	for ; i < len(tokens); i++ {
+		if tokens[i].is("(") {
+			depth++
+		} else if tokens[i].is(")") {
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return i
+}
+
+// selectListStart returns the index of the first select list item of the SELECT ending
+// at i, skipping ALL, DISTINCT [ON (...)], TOP n [PERCENT] [WITH TIES] and the MySQL modifiers
+func selectListStart(tokens []sqlToken, i int) int {
+	for i < len(tokens) {
+		tok := tokens[i]
+		switch {
+		case tok.is("ALL") || tok.is("UNIQUE") || tok.is("DISTINCTROW"):
+			i++
+		case tok.is("DISTINCT"):
+			i++
+			if i+1 < len(tokens) && tokens[i].is("ON") && tokens[i+1].is("(") {
+				i = skipSQLParens(tokens, i+1)
+			}
+		case tok.is("TOP"):
+			i++
+			if i < len(tokens) && tokens[i].is("(") {
+				i = skipSQLParens(tokens, i)
+			} else if i < len(tokens) && (tokens[i].kind == sqlTokenNumber || tokens[i].kind == sqlTokenParameter) {
+				i++
+			}
+			if i < len(tokens) && tokens[i].is("PERCENT") {
+				i++
+			}
+			if i+1 < len(tokens) && tokens[i].is("WITH") && tokens[i+1].is("TIES") {
+				i += 2
+			}
+		case tok.kind == sqlTokenWord && sqlSelectModifiers[strings.ToUpper(tok.text)]:
+			i++
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// isNaturalJoin reports whether the JOIN at i is a NATURAL [INNER | LEFT | RIGHT | FULL] [OUTER] JOIN
+func isNaturalJoin(tokens []sqlToken, i int) bool {
+	for j := i - 1; j >= 0; j-- {
+		switch {
+		case tokens[j].is("NATURAL"):
+			return true
+		case tokens[j].is("INNER") || tokens[j].is("LEFT") || tokens[j].is("RIGHT") ||
+			tokens[j].is("FULL") || tokens[j].is("OUTER"):
+			continue
+		}
+		return false
+	}
+	return false
+}
+
+// isSelectItemStart reports whether the "*" at i starts a select list item
+// (SELECT *, SELECT DISTINCT *, SELECT a, *, SELECT TOP 10 *) rather than a multiplication
+func isSelectItemStart(tokens []sqlToken, i int) bool {
+	if i == 0 {
+		return false
+	}
+	prev := tokens[i-1]
+	switch {
+	case prev.is("SELECT") || prev.is("DISTINCT") || prev.is("ALL") || prev.is("UNIQUE") || prev.is(","):
+		return true
+	case prev.is("PERCENT") || prev.is("TIES"):
+		return true
+	case prev.kind == sqlTokenNumber || prev.kind == sqlTokenParameter:
+		return i >= 2 && tokens[i-2].is("TOP")
+	case prev.is(")"):
+		return i >= 4 && tokens[i-4].is("TOP")
+	}
+	return false
+}
+
+// isAliasPosition reports whether the identifier at i is an alias, a type name or a
+// window name rather than a column: it follows AS, a cast operator, OVER or WINDOW, or,
+// in the select list and the FROM clause, the end of an expression. The first select
+// list item, at itemStart, is never an alias.
+func isAliasPosition(tokens []sqlToken, i int, clause string, itemStart int) bool {
+	if i == 0 || i == itemStart {
+		return false
+	}
+	prev := tokens[i-1]
+	switch {
+	case prev.is("AS") || prev.is("::") || prev.is("OVER") || prev.is("WINDOW"):
+		return true
+	case clause != "SELECT" && clause != "FROM":
+		return false
+	case prev.is("END") || prev.is(")"):
+		return true
+	case prev.kind == sqlTokenString || prev.kind == sqlTokenNumber || prev.kind == sqlTokenQuoted:
+		return true
+	case prev.kind == sqlTokenWord && !sqlKeywords[strings.ToUpper(prev.text)]:
+		return true
+	}
+	return false
+}
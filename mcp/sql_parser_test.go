@@ -0,0 +1,127 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestParseSQLReferencesSelectModifiers(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		columns []string
+	}{
+		{"top", "SELECT TOP 1 password_hash FROM users", []string{"password_hash"}},
+		{"top parenthesized", "SELECT TOP (5) PERCENT WITH TIES password_hash FROM users", []string{"password_hash"}},
+		{"top star", "SELECT TOP 10 * FROM users", []string{"*"}},
+		{"distinct on", "SELECT DISTINCT ON (id) password_hash FROM users", []string{"id", "password_hash"}},
+		{"distinct on star", "SELECT DISTINCT ON (id) * FROM users", []string{"id", "*"}},
+		{"mysql modifier", "SELECT SQL_NO_CACHE password_hash FROM users", []string{"password_hash"}},
+		{"mysql modifiers star", "SELECT HIGH_PRIORITY SQL_CALC_FOUND_ROWS * FROM users", []string{"*"}},
+		{"alias", "SELECT name AS n, id i FROM users", []string{"name", "id"}},
+		{"cast type", "SELECT CAST(id AS varchar) FROM users", []string{"id"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refs, err := ParseSQLReferences(tt.query)
+			if err != nil {
+				t.Fatalf("ParseSQLReferences(%q) error: %v", tt.query, err)
+			}
+			var columns []string
+			for _, ref := range refs.Columns {
+				columns = append(columns, ref.Column)
+			}
+			if len(columns) != len(tt.columns) {
+				t.Fatalf("ParseSQLReferences(%q) columns = %v, want %v", tt.query, columns, tt.columns)
+			}
+			for i := range columns {
+				if columns[i] != tt.columns[i] {
+					t.Fatalf("ParseSQLReferences(%q) columns = %v, want %v", tt.query, columns, tt.columns)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckQueryPolicy(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	for _, statement := range []string{
+		`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, password_hash TEXT)`,
+		`CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER, total REAL)`,
+		`CREATE TABLE credentials (user_id INTEGER, password_hash TEXT)`,
+		`CREATE TABLE audit_log (id INTEGER PRIMARY KEY, msg TEXT)`,
+	} {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config := &Config{Datasources: map[string]*DataSourceConfig{
+		"default": {Policy: &PolicyConfig{Deny: []string{"*.users.password_hash", "*.audit_*"}}},
+	}}
+	s := &DbMCPServer{db: db, queryBuilder: NewQueryBuilder("sqlite3"), config: config, datasource: "default", profile: "default"}
+
+	tests := []struct {
+		name  string
+		query string
+		want  error
+	}{
+		{"visible columns", "SELECT id, name FROM users", nil},
+		{"hidden column", "SELECT password_hash FROM users", ErrObjectNotAllowed},
+		{"qualified hidden column", "SELECT u.password_hash FROM users u", ErrObjectNotAllowed},
+		{"hidden table", "SELECT msg FROM audit_log", ErrObjectNotAllowed},
+		{"star", "SELECT * FROM users", ErrObjectNotAllowed},
+		{"top", "SELECT TOP 1 password_hash FROM users", ErrObjectNotAllowed},
+		{"distinct on", "SELECT DISTINCT ON (id) password_hash FROM users", ErrObjectNotAllowed},
+		{"distinct on star", "SELECT DISTINCT ON (id) * FROM users", ErrObjectNotAllowed},
+		{"mysql modifier", "SELECT SQL_NO_CACHE password_hash FROM users", ErrObjectNotAllowed},
+		{"mysql modifier star", "SELECT SQL_NO_CACHE * FROM users", ErrObjectNotAllowed},
+		{"bare table alias", "SELECT u FROM users u", ErrObjectNotAllowed},
+		{"table alias as argument", "SELECT row_to_json(u) FROM users u", ErrObjectNotAllowed},
+		{"table name as value", "SELECT users FROM users", ErrObjectNotAllowed},
+		{"natural join", "SELECT c.user_id FROM credentials c NATURAL JOIN users", ErrObjectNotAllowed},
+		{"natural left join", "SELECT 1 FROM users NATURAL LEFT OUTER JOIN credentials", ErrObjectNotAllowed},
+		{"natural join on visible columns", "SELECT o.total FROM orders o NATURAL JOIN credentials", nil},
+		{"natural join with derived table", "SELECT 1 FROM users NATURAL JOIN (SELECT 'x' AS password_hash) t", ErrObjectNotAllowed},
+		{"unknown word", "SELECT SQL_NEW_MODIFIER password_hash FROM users", ErrUnresolvedReference},
+		{"unknown qualifier", "SELECT x.password_hash FROM users u", ErrUnresolvedReference},
+		{"select alias in order by", "SELECT name AS n FROM users ORDER BY n", nil},
+		{"derived table", "SELECT t.n FROM (SELECT name AS n FROM users) t", nil},
+		{"derived table hidden column", "SELECT t.p FROM (SELECT password_hash AS p FROM users) t", ErrObjectNotAllowed},
+		{"cte column list", "WITH t(n) AS (SELECT name FROM users) SELECT n FROM t", nil},
+		{"builtin", "SELECT CURRENT_TIMESTAMP, name FROM users", nil},
+		{"join", "SELECT o.total FROM orders o JOIN users u ON u.id = o.user_id WHERE u.name = 'a'", nil},
+		{"backslash escaped quote", `SELECT 'x\' FROM users -- ', password_hash FROM users`, ErrObjectNotAllowed},
+		{"backslash in escape string", `SELECT E'x\' FROM users -- ', password_hash FROM users`, ErrObjectNotAllowed},
+		{"backslash in double quotes", `SELECT "x\" FROM users -- ", password_hash FROM users`, ErrUnresolvedReference},
+		{"backslash without escapes", `SELECT 'a\', password_hash FROM users -- '`, ErrObjectNotAllowed},
+		{"backslash escaped literal", `SELECT name FROM users WHERE name = 'it\'s'`, nil},
+		{"join on hidden column", "SELECT o.total FROM orders o JOIN users u ON u.id = o.user_id WHERE u.password_hash LIKE 'a%'", ErrObjectNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.checkQueryPolicy(context.Background(), tt.query)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("checkQueryPolicy(%q) = %v, want nil", tt.query, err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("checkQueryPolicy(%q) = %v, want %v", tt.query, err, tt.want)
+			}
+		})
+	}
+}
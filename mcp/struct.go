@@ -14,6 +14,13 @@ type DbMCPServer struct {
 	server       *server.MCPServer
 	db           *sql.DB
 	queryBuilder *QueryBuilder
+	config       *Config
+	datasource   string // name of the active datasource
+	profile      string // configuration entry whose settings apply to the active datasource
+	audit        *AuditLog
+	limiter      *Limiter
+	history      HistoryStore
+	savedQueries *savedQueryCatalog
 }
 
 // ConnectionManager handles dynamic database connections
@@ -65,14 +72,142 @@ type SQLValidator struct {
 
 // SelectQueryParams holds parameters for building a SELECT query
 type SelectQueryParams struct {
-	Schema         string
-	Table          string
-	Columns        []string
-	WhereClause    string
-	OrderBy        string
-	OrderDirection string
-	Limit          int
-	Offset         int
+	Schema      string
+	Table       string
+	Columns     []string
+	WhereClause string
+	OrderBy     []OrderByColumn
+	Limit       int
+	Offset      int
+}
+
+// AggregateQueryParams holds parameters for building a GROUP BY query
+type AggregateQueryParams struct {
+	Schema      string
+	Table       string
+	GroupBy     []GroupByTerm
+	Aggregates  []AggregateTerm
+	WhereClause string
+	OrderBy     []OrderByColumn // Column refers to a GroupBy or Aggregates alias
+	Limit       int
+	Offset      int
+}
+
+// GroupByTerm holds one GROUP BY column, optionally truncated to a date bucket
+type GroupByTerm struct {
+	Qualifier string // table alias, empty for single-table queries
+	Column    string
+	Bucket    string // "", "day", "week" or "month"
+	Alias     string
+}
+
+// AggregateTerm holds one aggregate function call
+type AggregateTerm struct {
+	Function  string // count, count_distinct, sum, avg, min or max
+	Qualifier string // table alias, empty for single-table queries
+	Column    string // empty for COUNT(*)
+	Alias     string
+}
+
+// JoinQueryParams holds parameters for building a SELECT over joined tables
+type JoinQueryParams struct {
+	Schema      string
+	Table       string
+	Alias       string
+	Joins       []JoinClause
+	Columns     []SelectTerm
+	GroupBy     []GroupByTerm
+	Aggregates  []AggregateTerm
+	WhereClause string
+	OrderBy     []OrderByColumn // Column refers to an output column alias
+	Limit       int
+	Offset      int
+}
+
+// JoinClause holds one JOIN along a foreign key
+type JoinClause struct {
+	Type        string // INNER or LEFT
+	Schema      string
+	Table       string
+	Alias       string
+	FromAlias   string   // alias of the table already in the query
+	FromColumns []string // columns of FromAlias, paired with Columns
+	Columns     []string // columns of Alias
+}
+
+// SelectTerm holds one projected column
+type SelectTerm struct {
+	Qualifier string
+	Column    string
+	Alias     string
+}
+
+// ForeignKey holds a foreign key constraint; Columns and RefColumns are paired by position.
+// OnDelete, OnUpdate and Match are empty when the database does not report them.
+type ForeignKey struct {
+	Name              string   `json:"name"`
+	Schema            string   `json:"-"`
+	Table             string   `json:"-"`
+	Columns           []string `json:"columns"`
+	RefSchema         string   `json:"referenced_schema,omitempty"`
+	RefTable          string   `json:"referenced_table"`
+	RefColumns        []string `json:"referenced_columns"`
+	OnDelete          string   `json:"on_delete,omitempty"`
+	OnUpdate          string   `json:"on_update,omitempty"`
+	Match             string   `json:"match,omitempty"`
+	Deferrable        bool     `json:"deferrable"`
+	InitiallyDeferred bool     `json:"initially_deferred"`
+}
+
+// TableIndex holds an index; Columns are in key order and hold the expression
+// text for the keys of an expression index
+type TableIndex struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type,omitempty"`
+	Unique  bool     `json:"is_unique"`
+	Columns []string `json:"columns"`
+}
+
+// CheckConstraint holds a check constraint. Column is set when it checks a single
+// column, and Domain when it comes from the domain type of the column.
+type CheckConstraint struct {
+	Name       string `json:"name"`
+	Column     string `json:"column,omitempty"`
+	Definition string `json:"definition"`
+	Domain     string `json:"domain,omitempty"`
+}
+
+// UniqueConstraint holds a unique constraint; Columns are in key order
+type UniqueConstraint struct {
+	Name    string   `json:"name"`
+	Columns []string `json:"columns"`
+}
+
+// DefaultConstraint holds a named default constraint
+type DefaultConstraint struct {
+	Name       string `json:"name"`
+	Column     string `json:"column"`
+	Definition string `json:"definition"`
+}
+
+// ExclusionConstraint holds an exclusion constraint
+type ExclusionConstraint struct {
+	Name       string `json:"name"`
+	Definition string `json:"definition"`
+}
+
+// OrderByColumn holds one ORDER BY term
+type OrderByColumn struct {
+	Column     string
+	Descending bool
+	Nulls      string // "", "first" or "last"
+}
+
+// TableColumn holds basic column metadata
+type TableColumn struct {
+	Name      string
+	DataType  string
+	MaxLength int64
 }
 
 // PaginationParams holds pagination parameters
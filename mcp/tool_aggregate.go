@@ -0,0 +1,365 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// aggregateFunctions lists the aggregate functions accepted by aggregate_table
+var aggregateFunctions = map[string]bool{
+	"count":          true,
+	"count_distinct": true,
+	"sum":            true,
+	"avg":            true,
+	"min":            true,
+	"max":            true,
+}
+
+// dateBuckets lists the date truncation units accepted in group_by
+var dateBuckets = map[string]bool{
+	"day":   true,
+	"week":  true,
+	"month": true,
+}
+
+func (s *DbMCPServer) toolAggregateTable() (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.Tool{
+		Name:        "aggregate_table",
+		Description: "Aggregates the rows of a table (count, count_distinct, sum, avg, min, max) grouped by columns or date buckets, without writing SQL",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"table_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Table name",
+				},
+				"schema": map[string]interface{}{
+					"type":        "string",
+					"description": "Schema name (optional)",
+				},
+				"group_by": map[string]interface{}{
+					"type": "array",
+					"description": "Columns to group by. Each item is a column name or " +
+						"{\"column\": \"created_at\", \"bucket\": \"day|week|month\", \"alias\": \"month\"}. " +
+						"Weeks start on Monday",
+				},
+				"aggregates": map[string]interface{}{
+					"type": "array",
+					"description": "Aggregates to compute. Each item is " +
+						"{\"function\": \"count|count_distinct|sum|avg|min|max\", \"column\": \"amount\", \"alias\": \"total\"}. " +
+						"'column' is optional for count (COUNT(*)). Default: [{\"function\": \"count\"}]",
+				},
+				"filters": filterInputSchema(),
+				"order_by": map[string]interface{}{
+					"description": "Sorting by output column (group_by or aggregate alias). A name, or an array of names or " +
+						"{\"column\": \"total\", \"direction\": \"asc|desc\", \"nulls\": \"first|last\"} objects (default: group_by columns)",
+				},
+				"order_direction": map[string]interface{}{
+					"type":        "string",
+					"description": "Default sorting direction: ASC or DESC (default: ASC)",
+				},
+				"page": map[string]interface{}{
+					"type":        "number",
+					"description": "Page number (default: 1)",
+				},
+				"page_size": map[string]interface{}{
+					"type":        "number",
+					"description": "Groups per page (default: 100, maximum: 1000)",
+				},
+			},
+			Required: []string{"table_name"},
+		},
+	}, s.handleAggregateTable
+}
+
+func (s *DbMCPServer) handleAggregateTable(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	args, ok := getArgs(request.Params.Arguments)
+	if !ok {
+		return mcp.NewToolResultError(ErrInvalidArguments.Error()), nil
+	}
+
+	tableName, ok := getStringArg(args, "table_name")
+	if !ok || !isValidIdentifier(tableName) {
+		return mcp.NewToolResultError(ErrInvalidTableName.Error()), nil
+	}
+
+	defaultSchema := getDefaultSchema(s.queryBuilder.GetDriver())
+	schema, err := getValidSchema(args, defaultSchema)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, DefaultQueryTimeout)
+	defer cancel()
+
+	// Check if table exists
+	if exists, err := s.tableExists(ctx, schema, tableName); err != nil {
+		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrCheckingTable, err).Error()), nil
+	} else if !exists {
+		return mcp.NewToolResultError(fmt.Errorf("%w: %s.%s", ErrTableNotFound, schema, tableName).Error()), nil
+	}
+
+	columns, err := s.getTableColumns(ctx, schema, tableName)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrRetrievingColumns, err).Error()), nil
+	}
+	if len(columns) == 0 {
+		return mcp.NewToolResultError(ErrNoColumnsFound.Error()), nil
+	}
+
+	lookup := s.tableColumnLookup(columns)
+
+	groupBy, err := s.parseGroupBy(args["group_by"], lookup)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	aggregates, err := s.parseAggregates(args["aggregates"], lookup, len(groupBy) == 0)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	orderBy, err := s.parseOutputOrderBy(args, nil, groupBy, aggregates)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	whereClause, queryParams, err := s.buildWhereClause(args, columns)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	pagination := GetPaginationParams(args, DefaultPageSize, MaxRowsPageSize)
+
+	query := s.queryBuilder.BuildAggregateQuery(AggregateQueryParams{
+		Schema:      schema,
+		Table:       tableName,
+		GroupBy:     groupBy,
+		Aggregates:  aggregates,
+		WhereClause: whereClause,
+		OrderBy:     orderBy,
+		Limit:       pagination.PageSize,
+		Offset:      pagination.Offset,
+	})
+
+	auditStatement(ctx, query, queryParams)
+	dbRows, err := s.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrAggregatingRows, err).Error()), nil
+	}
+	defer dbRows.Close()
+
+	resultColumns, rows, err := scanRows(dbRows)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrAggregatingRows, err).Error()), nil
+	}
+
+	auditRowCount(ctx, len(rows))
+	sources := termMaskSources(func(string) (string, string) { return schema, tableName }, nil, groupBy, aggregates)
+	masked := s.maskRows(ctx, resultColumns, sources, rows)
+
+	response := map[string]interface{}{
+		"rows":    rows,
+		"columns": resultColumns,
+		"query":   query,
+		"pagination": map[string]interface{}{
+			"page":      pagination.Page,
+			"page_size": pagination.PageSize,
+			"count":     len(rows),
+		},
+		"table": map[string]interface{}{
+			"schema": schema,
+			"name":   tableName,
+		},
+	}
+	if len(masked) > 0 {
+		response["masked_columns"] = masked
+	}
+
+	jsonData, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// parseOutputOrderBy checks that output column names are unique and parses the
+// "order_by" argument against them. Defaults to the group_by columns, or to the
+// first projected column when there is no grouping.
+func (s *DbMCPServer) parseOutputOrderBy(args map[string]interface{}, projection []SelectTerm, groupBy []GroupByTerm, aggregates []AggregateTerm) ([]OrderByColumn, error) {
+	var outputColumns []TableColumn
+	for _, c := range projection {
+		outputColumns = append(outputColumns, TableColumn{Name: c.Alias})
+	}
+	for _, g := range groupBy {
+		outputColumns = append(outputColumns, TableColumn{Name: g.Alias})
+	}
+	for _, a := range aggregates {
+		outputColumns = append(outputColumns, TableColumn{Name: a.Alias})
+	}
+
+	seen := make(map[string]bool)
+	for _, col := range outputColumns {
+		key := strings.ToLower(col.Name)
+		if seen[key] {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateAlias, col.Name)
+		}
+		seen[key] = true
+	}
+
+	if args["order_by"] != nil {
+		return s.parseOrderBy(args, outputColumns)
+	}
+
+	descending := false
+	if dir, ok := getStringArg(args, "order_direction"); ok {
+		descending = strings.EqualFold(dir, "DESC")
+	}
+
+	var orderBy []OrderByColumn
+	for _, g := range groupBy {
+		orderBy = append(orderBy, OrderByColumn{Column: g.Alias, Descending: descending})
+	}
+	if len(groupBy) == 0 && len(projection) > 0 {
+		orderBy = append(orderBy, OrderByColumn{Column: projection[0].Alias, Descending: descending})
+	}
+	return orderBy, nil
+}
+
+// columnLookup resolves a column reference to the table qualifier (alias) and column name
+type columnLookup func(name string) (qualifier, column string, err error)
+
+// tableColumnLookup returns a columnLookup for a single table (no qualifier)
+func (s *DbMCPServer) tableColumnLookup(columns []TableColumn) columnLookup {
+	return func(name string) (string, string, error) {
+		col, ok := s.resolveColumn(columns, name)
+		if !ok {
+			return "", "", fmt.Errorf("%w: %s", ErrColumnNotExists, name)
+		}
+		return "", col, nil
+	}
+}
+
+// parseGroupBy parses the "group_by" argument
+func (s *DbMCPServer) parseGroupBy(raw interface{}, lookup columnLookup) ([]GroupByTerm, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	items, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: expected an array", ErrInvalidGroupBy)
+	}
+	if len(items) > MaxGroupByColumns {
+		return nil, fmt.Errorf("%w (maximum %d group_by columns)", ErrTooManyAggregateTerms, MaxGroupByColumns)
+	}
+
+	var terms []GroupByTerm
+	for _, item := range items {
+		var term GroupByTerm
+		var name string
+
+		switch v := item.(type) {
+		case string:
+			name = v
+		case map[string]interface{}:
+			name, _ = v["column"].(string)
+			term.Bucket, _ = v["bucket"].(string)
+			term.Bucket = strings.ToLower(term.Bucket)
+			term.Alias, _ = v["alias"].(string)
+		default:
+			return nil, fmt.Errorf("%w: expected a column name or an object", ErrInvalidGroupBy)
+		}
+
+		qualifier, col, err := lookup(name)
+		if err != nil {
+			return nil, err
+		}
+		term.Qualifier = qualifier
+		term.Column = col
+
+		if term.Bucket != "" && !dateBuckets[term.Bucket] {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidDateBucket, term.Bucket)
+		}
+
+		if term.Alias == "" {
+			term.Alias = col
+			if term.Bucket != "" {
+				term.Alias = col + "_" + term.Bucket
+			}
+		}
+		if !isValidIdentifier(term.Alias) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidAlias, term.Alias)
+		}
+
+		terms = append(terms, term)
+	}
+	return terms, nil
+}
+
+// parseAggregates parses the "aggregates" argument.
+// When it is missing, COUNT(*) is used; an error is returned if there is nothing to compute.
+func (s *DbMCPServer) parseAggregates(raw interface{}, lookup columnLookup, noGroupBy bool) ([]AggregateTerm, error) {
+	if raw == nil {
+		return []AggregateTerm{{Function: "count", Alias: "count"}}, nil
+	}
+	items, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: expected an array", ErrInvalidAggregate)
+	}
+	if len(items) == 0 && noGroupBy {
+		return nil, ErrAggregateRequired
+	}
+	if len(items) > MaxAggregates {
+		return nil, fmt.Errorf("%w (maximum %d aggregates)", ErrTooManyAggregateTerms, MaxAggregates)
+	}
+
+	var terms []AggregateTerm
+	for _, item := range items {
+		v, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: expected an object", ErrInvalidAggregate)
+		}
+
+		function, _ := v["function"].(string)
+		function = strings.ToLower(function)
+		if !aggregateFunctions[function] {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidAggregateFunction, function)
+		}
+
+		term := AggregateTerm{Function: function}
+		if name, _ := v["column"].(string); name != "" {
+			qualifier, col, err := lookup(name)
+			if err != nil {
+				return nil, err
+			}
+			term.Qualifier = qualifier
+			term.Column = col
+		} else if function != "count" {
+			return nil, fmt.Errorf("%w: '%s' requires a column", ErrInvalidAggregate, function)
+		}
+
+		term.Alias, _ = v["alias"].(string)
+		if term.Alias == "" {
+			term.Alias = function
+			if term.Column != "" {
+				term.Alias = function + "_" + term.Column
+			}
+		}
+		if !isValidIdentifier(term.Alias) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidAlias, term.Alias)
+		}
+
+		terms = append(terms, term)
+	}
+	return terms, nil
+}
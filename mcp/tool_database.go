@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -14,7 +15,7 @@ import (
 func (s *DbMCPServer) toolSearchObjects() (mcp.Tool, server.ToolHandlerFunc) {
 	return mcp.Tool{
 		Name:        "search_objects",
-		Description: "Search for objects (tables, views, procedures, functions) by name or in the source code",
+		Description: "Search for objects (tables, views, procedures, functions) by name, in the source code or in their comments",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
 			Properties: map[string]interface{}{
@@ -26,6 +27,10 @@ func (s *DbMCPServer) toolSearchObjects() (mcp.Tool, server.ToolHandlerFunc) {
 					"type":        "boolean",
 					"description": "If true, also search in the source code of procedures/functions/views (default: false)",
 				},
+				"search_in_comments": map[string]interface{}{
+					"type":        "boolean",
+					"description": "If true, also search in the table and column comments/descriptions (default: false). SQLite has no comments and ignores it",
+				},
 				"object_types": map[string]interface{}{
 					"type":        "array",
 					"description": "Object types: 'table', 'view', 'procedure', 'function' (default: all)",
@@ -55,6 +60,7 @@ func (s *DbMCPServer) handleSearchObjects(ctx context.Context, request mcp.CallT
 	}
 
 	searchInCode := getBoolArg(args, "search_in_code", false)
+	searchInComments := getBoolArg(args, "search_in_comments", false)
 
 	var objectTypes []string
 	if objectTypesArg, ok := args["object_types"].([]interface{}); ok && len(objectTypesArg) > 0 {
@@ -65,24 +71,30 @@ func (s *DbMCPServer) handleSearchObjects(ctx context.Context, request mcp.CallT
 		}
 	}
 
-	query, queryArgs := s.queryBuilder.SearchObjectsQuery(searchTerm, searchInCode, objectTypes)
+	query, queryArgs := s.queryBuilder.SearchObjectsQuery(searchTerm, searchInCode, searchInComments, objectTypes)
 
 	ctx, cancel := context.WithTimeout(ctx, DefaultQueryTimeout)
 	defer cancel()
 
+	auditStatement(ctx, query, queryArgs)
 	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrSearchingObjects, err).Error()), nil
 	}
 	defer rows.Close()
 
+	policy := s.policy()
 	var results []map[string]interface{}
 	for rows.Next() {
 		var schemaName, objectName, objectType string
 		var createDate, modifyDate sql.NullTime
 		var hasCode bool
+		var description sql.NullString
 
-		if err = rows.Scan(&schemaName, &objectName, &objectType, &createDate, &modifyDate, &hasCode); err != nil {
+		if err = rows.Scan(&schemaName, &objectName, &objectType, &createDate, &modifyDate, &hasCode, &description); err != nil {
+			continue
+		}
+		if !policy.TableAllowed(schemaName, objectName) {
 			continue
 		}
 
@@ -98,15 +110,40 @@ func (s *DbMCPServer) handleSearchObjects(ctx context.Context, request mcp.CallT
 		if modifyDate.Valid {
 			result["last_altered"] = modifyDate.Time.Format("2006-01-02 15:04:05")
 		}
+		if description.String != "" {
+			result["description"] = description.String
+		}
 		results = append(results, result)
 	}
 
+	rows.Close()
+
+	if searchInComments && !policy.IsEmpty() {
+		// The query also matches the comments of hidden columns, which must not reveal
+		// the table: keep the tables that match otherwise
+		var visible []map[string]interface{}
+		for _, result := range results {
+			name, _ := result["name"].(string)
+			description, _ := result["description"].(string)
+			hasCode, _ := result["has_code"].(bool)
+			if !matchedOutsideColumnComments(searchTerm, name, description, searchInCode && hasCode) {
+				schemaName, _ := result["schema"].(string)
+				if ok, err := s.visibleColumnCommentMatches(ctx, schemaName, name, searchTerm); err != nil || !ok {
+					continue
+				}
+			}
+			visible = append(visible, result)
+		}
+		results = visible
+	}
+
 	response := map[string]interface{}{
 		"results": results,
 		"search": map[string]interface{}{
-			"term":    searchTerm,
-			"in_code": searchInCode,
-			"count":   len(results),
+			"term":        searchTerm,
+			"in_code":     searchInCode,
+			"in_comments": searchInComments,
+			"count":       len(results),
 		},
 	}
 
@@ -118,6 +155,36 @@ func (s *DbMCPServer) handleSearchObjects(ctx context.Context, request mcp.CallT
 	return mcp.NewToolResultText(string(jsonData)), nil
 }
 
+// matchedOutsideColumnComments reports whether a search result matches the term by its
+// name, its own comment or, when it was searched, its code
+func matchedOutsideColumnComments(term, name, description string, codeSearched bool) bool {
+	term = strings.ToLower(term)
+	return codeSearched || strings.Contains(strings.ToLower(name), term) || strings.Contains(strings.ToLower(description), term)
+}
+
+// visibleColumnCommentMatches reports whether the comment of a column of a table that
+// the policy shows contains the term
+func (s *DbMCPServer) visibleColumnCommentMatches(ctx context.Context, schema, tableName, term string) (bool, error) {
+	query, args := s.queryBuilder.DescribeTableQuery(schema, tableName)
+	auditStatement(ctx, query, args)
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	policy := s.policy()
+	term = strings.ToLower(term)
+	for _, col := range s.parseStandardDescribeTable(rows) {
+		name, _ := col["name"].(string)
+		description, _ := col["description"].(string)
+		if policy.ColumnAllowed(schema, tableName, name) && strings.Contains(strings.ToLower(description), term) {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
+
 func (s *DbMCPServer) toolGetDatabaseInfo() (mcp.Tool, server.ToolHandlerFunc) {
 	return mcp.Tool{
 		Name:        "get_database_info",
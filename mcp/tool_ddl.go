@@ -0,0 +1,693 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// ddlCatalogSQL holds the catalog queries a table is reconstructed from, on the
+// databases that do not write DDL themselves. Every query takes the schema and table.
+type ddlCatalogSQL struct {
+	// Columns returns name, type, nullable (YES/NO), default, generated clause and comment
+	Columns string
+	// Constraints returns name, type, definition, referenced schema and referenced table
+	Constraints string
+	// Indexes returns the name and CREATE INDEX statement of the indexes not backing a constraint
+	Indexes string
+	// TableComment returns the comment of the table
+	TableComment string
+	// ViewDefinition returns the query of a view or materialized view
+	ViewDefinition string
+}
+
+var ddlCatalogQueries = map[DriverType]ddlCatalogSQL{
+	DriverPostgresSQL: {
+		Columns: `
+			SELECT
+				a.attname,
+				format_type(a.atttypid, a.atttypmod),
+				CASE WHEN a.attnotnull THEN 'NO' ELSE 'YES' END,
+				CASE WHEN a.attgenerated = '' THEN pg_get_expr(d.adbin, d.adrelid) END,
+				CASE
+					WHEN a.attidentity = 'a' THEN 'GENERATED ALWAYS AS IDENTITY'
+					WHEN a.attidentity = 'd' THEN 'GENERATED BY DEFAULT AS IDENTITY'
+					WHEN a.attgenerated = 's' THEN 'GENERATED ALWAYS AS (' || pg_get_expr(d.adbin, d.adrelid) || ') STORED'
+				END,
+				col_description(a.attrelid, a.attnum)
+			FROM pg_attribute a
+			JOIN pg_class c ON c.oid = a.attrelid
+			JOIN pg_namespace n ON n.oid = c.relnamespace
+			LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+			WHERE n.nspname = $1 AND c.relname = $2 AND a.attnum > 0 AND NOT a.attisdropped
+			ORDER BY a.attnum`,
+		Constraints: `
+			SELECT
+				con.conname,
+				CASE con.contype WHEN 'p' THEN 'PRIMARY KEY' WHEN 'u' THEN 'UNIQUE' WHEN 'c' THEN 'CHECK' ELSE 'FOREIGN KEY' END,
+				pg_get_constraintdef(con.oid),
+				COALESCE(rn.nspname, ''),
+				COALESCE(rc.relname, '')
+			FROM pg_constraint con
+			JOIN pg_class c ON c.oid = con.conrelid
+			JOIN pg_namespace n ON n.oid = c.relnamespace
+			LEFT JOIN pg_class rc ON rc.oid = con.confrelid
+			LEFT JOIN pg_namespace rn ON rn.oid = rc.relnamespace
+			WHERE n.nspname = $1 AND c.relname = $2 AND con.contype IN ('p', 'u', 'c', 'f')
+			ORDER BY con.conname`,
+		Indexes: `
+			SELECT i.relname, pg_get_indexdef(ix.indexrelid)
+			FROM pg_index ix
+			JOIN pg_class i ON i.oid = ix.indexrelid
+			JOIN pg_class t ON t.oid = ix.indrelid
+			JOIN pg_namespace n ON n.oid = t.relnamespace
+			WHERE n.nspname = $1 AND t.relname = $2
+				AND NOT EXISTS (SELECT 1 FROM pg_constraint con WHERE con.conindid = ix.indexrelid AND con.contype IN ('p', 'u', 'x'))
+			ORDER BY i.relname`,
+		TableComment: `
+			SELECT obj_description(c.oid, 'pg_class')
+			FROM pg_class c
+			JOIN pg_namespace n ON n.oid = c.relnamespace
+			WHERE n.nspname = $1 AND c.relname = $2`,
+		ViewDefinition: `
+			SELECT pg_get_viewdef(c.oid, true)
+			FROM pg_class c
+			JOIN pg_namespace n ON n.oid = c.relnamespace
+			WHERE n.nspname = $1 AND c.relname = $2 AND c.relkind IN ('v', 'm')`,
+	},
+	DriverSQLServer: {
+		Columns: `
+			SELECT
+				c.name,
+				CASE
+					WHEN cc.definition IS NOT NULL THEN ''
+					WHEN t.name IN ('varchar', 'char', 'varbinary', 'binary')
+						THEN t.name + '(' + CASE WHEN c.max_length = -1 THEN 'max' ELSE CAST(c.max_length AS varchar(10)) END + ')'
+					WHEN t.name IN ('nvarchar', 'nchar')
+						THEN t.name + '(' + CASE WHEN c.max_length = -1 THEN 'max' ELSE CAST(c.max_length / 2 AS varchar(10)) END + ')'
+					WHEN t.name IN ('decimal', 'numeric')
+						THEN t.name + '(' + CAST(c.precision AS varchar(10)) + ', ' + CAST(c.scale AS varchar(10)) + ')'
+					WHEN t.name IN ('datetime2', 'time', 'datetimeoffset')
+						THEN t.name + '(' + CAST(c.scale AS varchar(10)) + ')'
+					ELSE t.name
+				END,
+				CASE WHEN c.is_nullable = 1 THEN 'YES' ELSE 'NO' END,
+				dc.definition,
+				CASE
+					WHEN ic.column_id IS NOT NULL
+						THEN 'IDENTITY(' + CAST(ic.seed_value AS varchar(40)) + ', ' + CAST(ic.increment_value AS varchar(40)) + ')'
+					WHEN cc.definition IS NOT NULL
+						THEN 'AS ' + cc.definition + CASE WHEN cc.is_persisted = 1 THEN ' PERSISTED' ELSE '' END
+				END,
+				CAST(ep.value AS nvarchar(4000))
+			FROM sys.columns c
+			JOIN sys.types t ON t.user_type_id = c.user_type_id
+			JOIN sys.tables tb ON tb.object_id = c.object_id
+			JOIN sys.schemas s ON s.schema_id = tb.schema_id
+			LEFT JOIN sys.default_constraints dc ON dc.parent_object_id = c.object_id AND dc.parent_column_id = c.column_id
+			LEFT JOIN sys.identity_columns ic ON ic.object_id = c.object_id AND ic.column_id = c.column_id
+			LEFT JOIN sys.computed_columns cc ON cc.object_id = c.object_id AND cc.column_id = c.column_id
+			LEFT JOIN sys.extended_properties ep
+				ON ep.class = 1 AND ep.major_id = c.object_id AND ep.minor_id = c.column_id AND ep.name = 'MS_Description'
+			WHERE s.name = @p1 AND tb.name = @p2
+			ORDER BY c.column_id`,
+		Constraints: `
+			SELECT
+				kc.name,
+				CASE kc.type WHEN 'PK' THEN 'PRIMARY KEY' ELSE 'UNIQUE' END,
+				CASE kc.type WHEN 'PK' THEN 'PRIMARY KEY' ELSE 'UNIQUE' END
+					+ CASE WHEN i.type = 1 THEN ' CLUSTERED' ELSE ' NONCLUSTERED' END
+					+ ' (' + STRING_AGG(QUOTENAME(col.name) + CASE WHEN ixc.is_descending_key = 1 THEN ' DESC' ELSE '' END, ', ')
+						WITHIN GROUP (ORDER BY ixc.key_ordinal) + ')',
+				'',
+				''
+			FROM sys.key_constraints kc
+			JOIN sys.tables t ON t.object_id = kc.parent_object_id
+			JOIN sys.schemas s ON s.schema_id = t.schema_id
+			JOIN sys.indexes i ON i.object_id = kc.parent_object_id AND i.index_id = kc.unique_index_id
+			JOIN sys.index_columns ixc ON ixc.object_id = i.object_id AND ixc.index_id = i.index_id
+			JOIN sys.columns col ON col.object_id = ixc.object_id AND col.column_id = ixc.column_id
+			WHERE s.name = @p1 AND t.name = @p2
+			GROUP BY kc.name, kc.type, i.type
+			UNION ALL
+			SELECT ck.name, 'CHECK', 'CHECK ' + ck.definition, '', ''
+			FROM sys.check_constraints ck
+			JOIN sys.tables t ON t.object_id = ck.parent_object_id
+			JOIN sys.schemas s ON s.schema_id = t.schema_id
+			WHERE s.name = @p1 AND t.name = @p2
+			UNION ALL
+			SELECT
+				fk.name,
+				'FOREIGN KEY',
+				'FOREIGN KEY (' + STRING_AGG(QUOTENAME(pc.name), ', ') WITHIN GROUP (ORDER BY fkc.constraint_column_id)
+					+ ') REFERENCES ' + QUOTENAME(rs.name) + '.' + QUOTENAME(rt.name)
+					+ ' (' + STRING_AGG(QUOTENAME(rc.name), ', ') WITHIN GROUP (ORDER BY fkc.constraint_column_id) + ')'
+					+ CASE fk.delete_referential_action WHEN 1 THEN ' ON DELETE CASCADE' WHEN 2 THEN ' ON DELETE SET NULL' WHEN 3 THEN ' ON DELETE SET DEFAULT' ELSE '' END
+					+ CASE fk.update_referential_action WHEN 1 THEN ' ON UPDATE CASCADE' WHEN 2 THEN ' ON UPDATE SET NULL' WHEN 3 THEN ' ON UPDATE SET DEFAULT' ELSE '' END,
+				rs.name,
+				rt.name
+			FROM sys.foreign_keys fk
+			JOIN sys.foreign_key_columns fkc ON fkc.constraint_object_id = fk.object_id
+			JOIN sys.tables t ON t.object_id = fk.parent_object_id
+			JOIN sys.schemas s ON s.schema_id = t.schema_id
+			JOIN sys.columns pc ON pc.object_id = fkc.parent_object_id AND pc.column_id = fkc.parent_column_id
+			JOIN sys.tables rt ON rt.object_id = fk.referenced_object_id
+			JOIN sys.schemas rs ON rs.schema_id = rt.schema_id
+			JOIN sys.columns rc ON rc.object_id = fkc.referenced_object_id AND rc.column_id = fkc.referenced_column_id
+			WHERE s.name = @p1 AND t.name = @p2
+			GROUP BY fk.name, rs.name, rt.name, fk.delete_referential_action, fk.update_referential_action`,
+		Indexes: `
+			SELECT
+				i.name,
+				'CREATE ' + CASE WHEN i.is_unique = 1 THEN 'UNIQUE ' ELSE '' END
+					+ CASE WHEN i.type = 1 THEN 'CLUSTERED' ELSE 'NONCLUSTERED' END
+					+ ' INDEX ' + QUOTENAME(i.name) + ' ON ' + QUOTENAME(s.name) + '.' + QUOTENAME(t.name)
+					+ ' (' + STRING_AGG(CASE WHEN ic.is_included_column = 0
+						THEN QUOTENAME(c.name) + CASE WHEN ic.is_descending_key = 1 THEN ' DESC' ELSE '' END END, ', ')
+						WITHIN GROUP (ORDER BY ic.key_ordinal) + ')'
+					+ COALESCE(' INCLUDE (' + STRING_AGG(CASE WHEN ic.is_included_column = 1 THEN QUOTENAME(c.name) END, ', ') + ')', '')
+					+ COALESCE(' WHERE ' + i.filter_definition, '')
+			FROM sys.indexes i
+			JOIN sys.tables t ON t.object_id = i.object_id
+			JOIN sys.schemas s ON s.schema_id = t.schema_id
+			JOIN sys.index_columns ic ON ic.object_id = i.object_id AND ic.index_id = i.index_id
+			JOIN sys.columns c ON c.object_id = ic.object_id AND c.column_id = ic.column_id
+			WHERE s.name = @p1 AND t.name = @p2
+				AND i.is_primary_key = 0 AND i.is_unique_constraint = 0 AND i.type IN (1, 2)
+			GROUP BY i.name, i.is_unique, i.type, i.filter_definition, s.name, t.name
+			ORDER BY i.name`,
+		TableComment: `
+			SELECT CAST(ep.value AS nvarchar(4000))
+			FROM sys.extended_properties ep
+			JOIN sys.tables t ON t.object_id = ep.major_id
+			JOIN sys.schemas s ON s.schema_id = t.schema_id
+			WHERE ep.class = 1 AND ep.minor_id = 0 AND ep.name = 'MS_Description'
+				AND s.name = @p1 AND t.name = @p2`,
+	},
+}
+
+// Tool: Generate DDL
+func (s *DbMCPServer) toolGenerateDDL() (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.Tool{
+		Name: "generate_ddl",
+		Description: "Generates runnable DDL for a table, a view or a whole schema: columns with types, defaults and identity, " +
+			"primary key, unique and check constraints, indexes, foreign keys and comments. A schema is returned in dependency order. " +
+			"MySQL, Oracle and SQLite return the database's own DDL; PostgreSQL and SQL Server tables are reconstructed from the catalog",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"table_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Table to generate (optional)",
+				},
+				"view_name": map[string]interface{}{
+					"type":        "string",
+					"description": "View to generate (optional)",
+				},
+				"schema": map[string]interface{}{
+					"type":        "string",
+					"description": "Schema name; without table_name or view_name, every table and view of the schema is generated",
+				},
+			},
+		},
+	}, s.handleGenerateDDL
+}
+
+func (s *DbMCPServer) handleGenerateDDL(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	args, ok := getArgs(request.Params.Arguments)
+	if !ok {
+		return mcp.NewToolResultError(ErrInvalidArguments.Error()), nil
+	}
+
+	tableName, _ := getStringArg(args, "table_name")
+	viewName, _ := getStringArg(args, "view_name")
+	if tableName != "" && viewName != "" {
+		return mcp.NewToolResultError(fmt.Errorf("%w: give table_name or view_name, not both", ErrInvalidArguments).Error()), nil
+	}
+	if tableName != "" && !isValidIdentifier(tableName) {
+		return mcp.NewToolResultError(ErrInvalidTableName.Error()), nil
+	}
+	if viewName != "" && !isValidIdentifier(viewName) {
+		return mcp.NewToolResultError(ErrInvalidViewName.Error()), nil
+	}
+
+	schema, err := getValidSchema(args, getDefaultSchema(s.queryBuilder.GetDriver()))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, DefaultQueryTimeout)
+	defer cancel()
+
+	if schema == "" {
+		if schema, err = s.currentSchema(ctx); err != nil {
+			return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrGeneratingDDL, err).Error()), nil
+		}
+	}
+
+	var tables, views []string
+	switch {
+	case tableName != "":
+		if exists, err := s.tableExists(ctx, schema, tableName); err != nil || !exists {
+			return mcp.NewToolResultError(fmt.Errorf("%w: %s.%s", ErrTableNotFound, schema, tableName).Error()), nil
+		}
+		tables = []string{tableName}
+	case viewName != "":
+		if !s.objectVisible(ctx, schema, viewName) {
+			return mcp.NewToolResultError(fmt.Errorf("%w: %s.%s", ErrViewNotFound, schema, viewName).Error()), nil
+		}
+		views = []string{viewName}
+	default:
+		if tables, err = s.listSchemaTables(ctx, schema); err != nil {
+			return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrListingTables, err).Error()), nil
+		}
+		if s.queryBuilder.SupportsViews() {
+			if views, err = s.listSchemaViews(ctx, schema); err != nil {
+				return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrListingViews, err).Error()), nil
+			}
+		}
+	}
+
+	var warnings []string
+	var tableObjects, viewObjects []*DDLObject
+	for _, name := range tables {
+		hidden, err := s.hasHiddenColumns(ctx, schema, name)
+		if err != nil {
+			if tableName != "" {
+				return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrGeneratingDDL, err).Error()), nil
+			}
+			warnings = append(warnings, fmt.Sprintf("%s.%s skipped: %v", schema, name, err))
+			continue
+		}
+		if hidden {
+			warnings = append(warnings, fmt.Sprintf("%s.%s skipped: it has columns hidden by the policy", schema, name))
+			continue
+		}
+		object, err := s.tableDDL(ctx, schema, name)
+		if err != nil {
+			if tableName != "" {
+				return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrGeneratingDDL, err).Error()), nil
+			}
+			warnings = append(warnings, fmt.Sprintf("%s.%s skipped: %v", schema, name, err))
+			continue
+		}
+		tableObjects = append(tableObjects, object)
+	}
+	for _, name := range views {
+		object, err := s.viewDDL(ctx, schema, name)
+		if err != nil {
+			if viewName != "" {
+				return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrGeneratingDDL, err).Error()), nil
+			}
+			warnings = append(warnings, fmt.Sprintf("%s.%s skipped: %v", schema, name, err))
+			continue
+		}
+		viewObjects = append(viewObjects, object)
+	}
+	if len(tableObjects) == 0 && len(viewObjects) == 0 && (tableName != "" || viewName != "") {
+		return mcp.NewToolResultError(fmt.Sprintf("%v: %s", ErrGeneratingDDL, strings.Join(warnings, "; "))), nil
+	}
+
+	// Tables first, each after the tables it references, then views
+	objects := append(orderDDLObjects(tableObjects), orderDDLObjects(viewObjects)...)
+	created := make(map[string]bool)
+	isCreated := func(refSchema, refTable string) bool {
+		return (refSchema != "" && !strings.EqualFold(refSchema, schema)) || created[strings.ToLower(refTable)]
+	}
+
+	var sections, deferred []string
+	for _, object := range objects {
+		statements := object.statements
+		created[strings.ToLower(object.Name)] = true
+		if object.table != nil {
+			var later []string
+			statements, later = renderTable(s.queryBuilder, object.table, isCreated)
+			deferred = append(deferred, later...)
+		} else if object.Type == "table" {
+			for _, ref := range object.references {
+				if !isCreated("", ref) && isDDLTable(ref, objects) {
+					warnings = append(warnings, fmt.Sprintf("%s references %s, created later: the native DDL declares its foreign keys inline", object.Name, ref))
+				}
+			}
+		}
+		sections = append(sections, fmt.Sprintf("-- %s: %s.%s\n%s", strings.ToUpper(object.Type[:1])+object.Type[1:], schema, object.Name, strings.Join(statements, "\n")))
+	}
+	if len(deferred) > 0 {
+		sections = append(sections, "-- Foreign keys between tables referencing each other\n"+strings.Join(deferred, "\n"))
+	}
+
+	response := map[string]interface{}{
+		"schema":  schema,
+		"driver":  s.queryBuilder.GetDriver(),
+		"objects": objects,
+		"ddl":     strings.Join(sections, "\n\n") + "\n",
+	}
+	if len(warnings) > 0 {
+		response["warnings"] = warnings
+	}
+
+	jsonData, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// isDDLTable reports whether name is one of the tables being generated
+func isDDLTable(name string, objects []*DDLObject) bool {
+	for _, object := range objects {
+		if object.Type == "table" && strings.EqualFold(object.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasHiddenColumns reports whether the policy hides some of the columns of a table,
+// whose DDL would reveal them
+func (s *DbMCPServer) hasHiddenColumns(ctx context.Context, schema, tableName string) (bool, error) {
+	all, err := s.fetchTableColumns(ctx, schema, tableName)
+	if err != nil {
+		return false, err
+	}
+	visible, err := s.getTableColumns(ctx, schema, tableName)
+	if err != nil {
+		return false, err
+	}
+	return len(visible) < len(all), nil
+}
+
+// tableDDL returns the DDL of a table, native where the database writes it
+func (s *DbMCPServer) tableDDL(ctx context.Context, schema, tableName string) (*DDLObject, error) {
+	object := &DDLObject{Name: tableName, Type: "table", Source: "native"}
+
+	foreignKeys, err := s.getForeignKeys(ctx, schema, tableName)
+	if err != nil {
+		return nil, err
+	}
+	for _, fk := range foreignKeys {
+		if (fk.RefSchema == "" || strings.EqualFold(fk.RefSchema, schema)) && !strings.EqualFold(fk.RefTable, tableName) {
+			object.references = append(object.references, fk.RefTable)
+		}
+	}
+
+	switch s.queryBuilder.GetDriver() {
+	case DriverMySQL:
+		statement, err := s.queryDDLText(ctx, fmt.Sprintf("SHOW CREATE TABLE %s", s.queryBuilder.QualifyTable(schema, tableName)))
+		if err != nil {
+			return nil, err
+		}
+		object.statements = []string{terminateStatement(statement)}
+	case DriverSQLite:
+		object.statements, err = s.sqliteDDL(ctx, "table", tableName)
+	case DriverOracle:
+		object.statements, err = s.oracleDDL(ctx, "TABLE", schema, tableName)
+	default:
+		object.Source = "reconstructed"
+		object.table, err = s.reconstructTable(ctx, schema, tableName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+// viewDDL returns the DDL of a view, with the views it reads as references
+func (s *DbMCPServer) viewDDL(ctx context.Context, schema, viewName string) (*DDLObject, error) {
+	object := &DDLObject{Name: viewName, Type: "view", Source: "native"}
+
+	// SQL Server indexed views are created as plain views
+	_, _, materialized, err := s.materializedView(ctx, schema, viewName)
+	if err != nil {
+		return nil, err
+	}
+	materialized = materialized && !s.queryBuilder.IsSQLServer()
+	if materialized {
+		object.Type = "materialized view"
+	}
+
+	switch s.queryBuilder.GetDriver() {
+	case DriverMySQL:
+		var statement string
+		statement, err = s.queryDDLText(ctx, fmt.Sprintf("SHOW CREATE VIEW %s", s.queryBuilder.QualifyTable(schema, viewName)))
+		object.statements = []string{terminateStatement(statement)}
+	case DriverSQLite:
+		object.statements, err = s.sqliteDDL(ctx, "view", viewName)
+	case DriverOracle:
+		objectType := "VIEW"
+		if materialized {
+			objectType = "MATERIALIZED_VIEW"
+		}
+		object.statements, err = s.oracleDDL(ctx, objectType, schema, viewName)
+	case DriverSQLServer:
+		// sys.sql_modules keeps the CREATE VIEW statement as written
+		query, args := s.queryBuilder.GetViewDefinitionQuery(schema, viewName)
+		var statement string
+		statement, err = s.queryDDLText(ctx, query, args...)
+		object.statements = []string{terminateStatement(statement)}
+	default:
+		object.Source = "reconstructed"
+		var definition string
+		definition, err = s.queryDDLText(ctx, ddlCatalogQueries[DriverPostgresSQL].ViewDefinition, schema, viewName)
+		keyword := "VIEW"
+		if materialized {
+			keyword = "MATERIALIZED VIEW"
+		}
+		object.statements = []string{fmt.Sprintf("CREATE %s %s AS\n%s;",
+			keyword, s.queryBuilder.QualifyTable(schema, viewName), strings.TrimRight(strings.TrimSpace(definition), ";"))}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if refs, err := ParseSQLReferences(strings.Join(object.statements, "\n")); err == nil {
+		for _, ref := range refs.Tables {
+			if (ref.Schema == "" || strings.EqualFold(ref.Schema, schema)) && !strings.EqualFold(ref.Name, viewName) {
+				object.references = append(object.references, ref.Name)
+			}
+		}
+	}
+	return object, nil
+}
+
+// queryDDLText runs a query returning DDL text in its last column, such as SHOW CREATE TABLE
+func (s *DbMCPServer) queryDDLText(ctx context.Context, query string, args ...interface{}) (string, error) {
+	auditStatement(ctx, query, args)
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", err
+	}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return "", err
+		}
+		return "", sql.ErrNoRows
+	}
+
+	values := make([]sql.NullString, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	if err := rows.Scan(pointers...); err != nil {
+		return "", err
+	}
+	// SHOW CREATE TABLE returns the name first; SHOW CREATE VIEW adds character sets after the statement
+	if len(columns) >= 2 {
+		return values[1].String, nil
+	}
+	return values[0].String, nil
+}
+
+// sqliteDDL returns the statement of a table or view from sqlite_master, and those of
+// the indexes of a table
+func (s *DbMCPServer) sqliteDDL(ctx context.Context, objectType, name string) ([]string, error) {
+	statement, err := s.queryDDLText(ctx, "SELECT sql FROM sqlite_master WHERE type = ? AND name = ?", objectType, name)
+	if err != nil {
+		return nil, err
+	}
+	statements := []string{terminateStatement(statement)}
+	if objectType != "table" {
+		return statements, nil
+	}
+
+	query := "SELECT sql FROM sqlite_master WHERE type = 'index' AND tbl_name = ? AND sql IS NOT NULL ORDER BY name"
+	auditStatement(ctx, query, []interface{}{name})
+	rows, err := s.db.QueryContext(ctx, query, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var index string
+		if err := rows.Scan(&index); err != nil {
+			return nil, err
+		}
+		statements = append(statements, terminateStatement(index))
+	}
+	return statements, rows.Err()
+}
+
+// oracleDDL returns the DBMS_METADATA DDL of a table or view, with the indexes not
+// backing a constraint and the comments of a table
+func (s *DbMCPServer) oracleDDL(ctx context.Context, objectType, schema, name string) ([]string, error) {
+	// Transform parameters are per session: keep them on one connection and restore them
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	setup := `BEGIN
+		DBMS_METADATA.SET_TRANSFORM_PARAM(DBMS_METADATA.SESSION_TRANSFORM, 'SQLTERMINATOR', TRUE);
+		DBMS_METADATA.SET_TRANSFORM_PARAM(DBMS_METADATA.SESSION_TRANSFORM, 'SEGMENT_ATTRIBUTES', FALSE);
+	END;`
+	if _, err := conn.ExecContext(ctx, setup); err != nil {
+		return nil, err
+	}
+	defer conn.ExecContext(context.Background(), "BEGIN DBMS_METADATA.SET_TRANSFORM_PARAM(DBMS_METADATA.SESSION_TRANSFORM, 'DEFAULT', TRUE); END;")
+
+	schema, name = strings.ToUpper(schema), strings.ToUpper(name)
+	texts := func(query string, args ...interface{}) ([]string, error) {
+		auditStatement(ctx, query, args)
+		rows, err := conn.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		var statements []string
+		for rows.Next() {
+			var text sql.NullString
+			if err := rows.Scan(&text); err != nil {
+				return nil, err
+			}
+			if statement := strings.TrimSpace(text.String); statement != "" {
+				statements = append(statements, statement)
+			}
+		}
+		return statements, rows.Err()
+	}
+
+	statements, err := texts("SELECT DBMS_METADATA.GET_DDL(:1, :2, :3) FROM DUAL", objectType, name, schema)
+	if err != nil {
+		return nil, err
+	}
+	if objectType != "TABLE" {
+		return statements, nil
+	}
+
+	indexes, err := texts(`
+		SELECT DBMS_METADATA.GET_DDL('INDEX', i.index_name, i.owner)
+		FROM all_indexes i
+		WHERE i.table_owner = :1 AND i.table_name = :2 AND i.index_type <> 'LOB'
+			AND NOT EXISTS (
+				SELECT 1 FROM all_constraints c
+				WHERE c.owner = i.owner AND c.index_name = i.index_name AND c.constraint_type IN ('P', 'U'))
+		ORDER BY i.index_name`, schema, name)
+	if err != nil {
+		return nil, err
+	}
+	statements = append(statements, indexes...)
+
+	// ORA-31608 when the table has no comments
+	if comments, err := texts("SELECT DBMS_METADATA.GET_DEPENDENT_DDL('COMMENT', :1, :2) FROM DUAL", name, schema); err == nil {
+		statements = append(statements, comments...)
+	}
+	return statements, nil
+}
+
+// reconstructTable reads the catalog metadata a table's DDL is rendered from
+func (s *DbMCPServer) reconstructTable(ctx context.Context, schema, tableName string) (*ddlTable, error) {
+	catalog, ok := ddlCatalogQueries[s.queryBuilder.GetDriver()]
+	if !ok {
+		return nil, ErrFeatureNotSupported
+	}
+	table := &ddlTable{schema: schema, name: tableName}
+
+	auditStatement(ctx, catalog.Columns, []interface{}{schema, tableName})
+	rows, err := s.db.QueryContext(ctx, catalog.Columns, schema, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var col ddlColumn
+		var nullable string
+		var defaultValue, generated, comment sql.NullString
+		if err := rows.Scan(&col.Name, &col.Type, &nullable, &defaultValue, &generated, &comment); err != nil {
+			return nil, err
+		}
+		col.Nullable = strings.EqualFold(nullable, "YES")
+		col.Default, col.Generated, col.Comment = defaultValue.String, generated.String, comment.String
+		table.columns = append(table.columns, col)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(table.columns) == 0 {
+		return nil, fmt.Errorf("%w: %s.%s", ErrTableNotFound, schema, tableName)
+	}
+
+	auditStatement(ctx, catalog.Constraints, []interface{}{schema, tableName})
+	constraintRows, err := s.db.QueryContext(ctx, catalog.Constraints, schema, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer constraintRows.Close()
+	for constraintRows.Next() {
+		var c ddlConstraint
+		if err := constraintRows.Scan(&c.Name, &c.Type, &c.Definition, &c.RefSchema, &c.RefTable); err != nil {
+			return nil, err
+		}
+		if c.Type == "FOREIGN KEY" {
+			table.foreignKeys = append(table.foreignKeys, c)
+		} else {
+			table.constraints = append(table.constraints, c)
+		}
+	}
+	if err := constraintRows.Err(); err != nil {
+		return nil, err
+	}
+
+	auditStatement(ctx, catalog.Indexes, []interface{}{schema, tableName})
+	indexRows, err := s.db.QueryContext(ctx, catalog.Indexes, schema, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer indexRows.Close()
+	for indexRows.Next() {
+		var name, definition string
+		if err := indexRows.Scan(&name, &definition); err != nil {
+			return nil, err
+		}
+		table.indexes = append(table.indexes, definition)
+	}
+	if err := indexRows.Err(); err != nil {
+		return nil, err
+	}
+
+	var comment sql.NullString
+	auditStatement(ctx, catalog.TableComment, []interface{}{schema, tableName})
+	if err := s.db.QueryRowContext(ctx, catalog.TableComment, schema, tableName).Scan(&comment); err != nil && err != sql.ErrNoRows {
+		log.Printf("DDL: could not read the comment of %s.%s: %v\n", schema, tableName, err)
+	}
+	table.comment = comment.String
+	return table, nil
+}
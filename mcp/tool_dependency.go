@@ -0,0 +1,221 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// dependencyToolProperties are the arguments of get_dependencies and get_dependents
+func dependencyToolProperties() map[string]interface{} {
+	return map[string]interface{}{
+		"object_name": map[string]interface{}{
+			"type":        "string",
+			"description": "Table, view, procedure, function or trigger name",
+		},
+		"schema": map[string]interface{}{
+			"type":        "string",
+			"description": "Schema name (optional)",
+		},
+		"depth": map[string]interface{}{
+			"type":        "number",
+			"description": fmt.Sprintf("Levels followed transitively (default: %d, max: %d)", DefaultDependencyDepth, MaxDependencyDepth),
+		},
+		"object_types": map[string]interface{}{
+			"type":        "array",
+			"description": "Object types followed: 'table', 'view', 'materialized view', 'procedure', 'function', 'trigger', 'package', ... (default: all)",
+			"items": map[string]interface{}{
+				"type": "string",
+			},
+		},
+		"format": map[string]interface{}{
+			"type":        "string",
+			"description": "Output format: the tree as JSON, or also as a Mermaid flowchart (default: json)",
+			"enum":        []string{DependencyFormatJSON, DependencyFormatMermaid},
+		},
+	}
+}
+
+// Tool: Get Dependencies
+func (s *DbMCPServer) toolGetDependencies() (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.Tool{
+		Name: "get_dependencies",
+		Description: "Returns the objects an object depends on (the tables and views a view reads, the tables a trigger is on, ...) " +
+			"as a transitive tree, from the database's own dependency catalog",
+		InputSchema: mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: dependencyToolProperties(),
+			Required:   []string{"object_name"},
+		},
+	}, s.handleDependencyTree(false)
+}
+
+// Tool: Get Dependents
+func (s *DbMCPServer) toolGetDependents() (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.Tool{
+		Name: "get_dependents",
+		Description: "Returns the objects depending on an object (the views, procedures, functions and triggers that use a table, ...) " +
+			"as a transitive tree, from the database's own dependency catalog. Use it to find what breaks before changing an object",
+		InputSchema: mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: dependencyToolProperties(),
+			Required:   []string{"object_name"},
+		},
+	}, s.handleDependencyTree(true)
+}
+
+// handleDependencyTree returns the handler of get_dependencies, or of get_dependents
+func (s *DbMCPServer) handleDependencyTree(dependents bool) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := s.requireConnection(); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		args, ok := getArgs(request.Params.Arguments)
+		if !ok {
+			return mcp.NewToolResultError(ErrInvalidArguments.Error()), nil
+		}
+
+		name, ok := getStringArg(args, "object_name")
+		if !ok || name == "" {
+			return mcp.NewToolResultError(fmt.Errorf("%w: object_name is required", ErrInvalidArguments).Error()), nil
+		}
+		if !isValidIdentifier(name) {
+			return mcp.NewToolResultError(fmt.Errorf("%w: %s", ErrInvalidIdentifier, name).Error()), nil
+		}
+		depth := getIntArg(args, "depth", DefaultDependencyDepth)
+		if depth < 1 || depth > MaxDependencyDepth {
+			return mcp.NewToolResultError(fmt.Sprintf("%v: depth must be between 1 and %d", ErrInvalidArguments, MaxDependencyDepth)), nil
+		}
+		types, _ := getStringSliceArg(args, "object_types")
+		format, _ := getStringArg(args, "format")
+		if format == "" {
+			format = DependencyFormatJSON
+		}
+		if format != DependencyFormatJSON && format != DependencyFormatMermaid {
+			return mcp.NewToolResultError(fmt.Sprintf("%v: format must be %s or %s", ErrInvalidArguments, DependencyFormatJSON, DependencyFormatMermaid)), nil
+		}
+
+		schema, err := getValidSchema(args, getDefaultSchema(s.queryBuilder.GetDriver()))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, DefaultQueryTimeout)
+		defer cancel()
+
+		if schema == "" {
+			if schema, err = s.currentSchema(ctx); err != nil {
+				return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrListingDependencies, err).Error()), nil
+			}
+		}
+		if !s.objectVisible(ctx, schema, name) {
+			return mcp.NewToolResultError(fmt.Errorf("%w: %s.%s", ErrObjectNotFound, schema, name).Error()), nil
+		}
+
+		graph, err := s.loadDependencyGraph(ctx)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrListingDependencies, err).Error()), nil
+		}
+
+		root := graph.find(schema, name)
+		if root == nil {
+			// Objects without recorded dependencies are not in the graph
+			exists, err := s.tableExists(ctx, schema, name)
+			if err != nil || !exists {
+				return mcp.NewToolResultError(fmt.Errorf("%w: %s.%s has no recorded dependencies", ErrObjectNotFound, schema, name).Error()), nil
+			}
+			root = &DependencyObject{Schema: schema, Name: name, Type: "table"}
+		}
+
+		policy := s.policy()
+		tree, count, truncated := graph.Tree(root, dependents, depth, types, func(o DependencyObject) bool {
+			return policy.TableAllowed(o.Schema, o.Name)
+		})
+
+		response := map[string]interface{}{
+			"object": root,
+			"depth":  depth,
+			"count":  count - 1,
+			"tree":   tree,
+			"format": format,
+		}
+		if dependents {
+			response["direction"] = "dependents"
+		} else {
+			response["direction"] = "dependencies"
+		}
+		if len(types) > 0 {
+			response["object_types"] = types
+		}
+		if truncated {
+			response["truncated"] = true
+			response["warning"] = fmt.Sprintf("the tree was cut at %d objects; lower depth or filter object_types", MaxDependencyNodes)
+		}
+		if format == DependencyFormatMermaid {
+			response["diagram"] = tree.Mermaid(dependents)
+		}
+		if s.queryBuilder.IsSQLite() {
+			response["source"] = "parsed from sqlite_master.sql"
+		}
+
+		jsonData, err := json.MarshalIndent(response, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
+		}
+
+		return mcp.NewToolResultText(string(jsonData)), nil
+	}
+}
+
+// loadDependencyGraph reads the dependencies between the objects of the database
+func (s *DbMCPServer) loadDependencyGraph(ctx context.Context) (*DependencyGraph, error) {
+	if query, ok := s.queryBuilder.ListDependenciesQuery(); ok {
+		auditStatement(ctx, query, nil)
+		rows, err := s.db.QueryContext(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		graph := newDependencyGraph()
+		for rows.Next() {
+			var object, referenced DependencyObject
+			var schema, refSchema sql.NullString
+			if err := rows.Scan(&schema, &object.Name, &object.Type, &refSchema, &referenced.Name, &referenced.Type); err != nil {
+				return nil, err
+			}
+			object.Schema, referenced.Schema = schema.String, refSchema.String
+			graph.add(object, referenced)
+		}
+		return graph, rows.Err()
+	}
+
+	query, ok := s.queryBuilder.ListObjectSQLQuery()
+	if !ok {
+		return nil, fmt.Errorf("not supported by %s", s.queryBuilder.GetDriver())
+	}
+	auditStatement(ctx, query, nil)
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var objects [][4]string
+	for rows.Next() {
+		var typ, name, table, definition sql.NullString
+		if err := rows.Scan(&typ, &name, &table, &definition); err != nil {
+			return nil, err
+		}
+		objects = append(objects, [4]string{typ.String, name.String, table.String, definition.String})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return parseSQLiteDependencies(objects), nil
+}
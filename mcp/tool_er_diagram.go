@@ -0,0 +1,301 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Tool: Export ER Diagram
+func (s *DbMCPServer) toolExportERDiagram() (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.Tool{
+		Name: "export_er_diagram",
+		Description: "Renders the foreign key relationships of a schema as an entity-relationship diagram (Mermaid erDiagram, PlantUML or Graphviz DOT), " +
+			"with primary and foreign key markers and cardinality inferred from nullability and unique constraints. " +
+			"With table_name, only that table and the tables within depth foreign key hops of it are included",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"schema": map[string]interface{}{
+					"type":        "string",
+					"description": "Schema name (optional)",
+				},
+				"table_name": map[string]interface{}{
+					"type":        "string",
+					"description": "Table the diagram starts from (optional, default: every table of the schema)",
+				},
+				"depth": map[string]interface{}{
+					"type":        "number",
+					"description": fmt.Sprintf("Foreign key hops followed from table_name, in both directions (default: %d, max: %d)", DefaultERDiagramDepth, MaxERDiagramDepth),
+				},
+				"format": map[string]interface{}{
+					"type":        "string",
+					"description": "Diagram format (default: mermaid)",
+					"enum":        []string{ERFormatMermaid, ERFormatPlantUML, ERFormatDOT},
+				},
+				"keys_only": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Only show primary and foreign key columns, for large schemas (default: false)",
+				},
+			},
+		},
+	}, s.handleExportERDiagram
+}
+
+func (s *DbMCPServer) handleExportERDiagram(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	args, ok := getArgs(request.Params.Arguments)
+	if !ok {
+		return mcp.NewToolResultError(ErrInvalidArguments.Error()), nil
+	}
+
+	format, _ := getStringArg(args, "format")
+	if format == "" {
+		format = ERFormatMermaid
+	}
+	if format != ERFormatMermaid && format != ERFormatPlantUML && format != ERFormatDOT {
+		return mcp.NewToolResultError(fmt.Sprintf("%v: format must be %s, %s or %s", ErrInvalidArguments, ERFormatMermaid, ERFormatPlantUML, ERFormatDOT)), nil
+	}
+
+	seed, _ := getStringArg(args, "table_name")
+	if seed != "" && !isValidIdentifier(seed) {
+		return mcp.NewToolResultError(ErrInvalidTableName.Error()), nil
+	}
+	depth := getIntArg(args, "depth", DefaultERDiagramDepth)
+	if depth < 0 || depth > MaxERDiagramDepth {
+		return mcp.NewToolResultError(fmt.Sprintf("%v: depth must be between 0 and %d", ErrInvalidArguments, MaxERDiagramDepth)), nil
+	}
+	keysOnly := getBoolArg(args, "keys_only", false)
+
+	schema, err := getValidSchema(args, getDefaultSchema(s.queryBuilder.GetDriver()))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, DefaultQueryTimeout)
+	defer cancel()
+
+	if schema == "" {
+		if schema, err = s.currentSchema(ctx); err != nil {
+			return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrListingTables, err).Error()), nil
+		}
+	}
+
+	tables, err := s.listSchemaTables(ctx, schema)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrListingTables, err).Error()), nil
+	}
+	inSchema := make(map[string]string, len(tables))
+	for _, table := range tables {
+		inSchema[strings.ToLower(table)] = table
+	}
+
+	if seed != "" {
+		name, ok := inSchema[strings.ToLower(seed)]
+		if !ok {
+			return mcp.NewToolResultError(fmt.Errorf("%w: %s.%s", ErrTableNotFound, schema, seed).Error()), nil
+		}
+		seed = name
+	}
+
+	// Foreign keys between tables of the schema, keyed by referencing table
+	foreignKeys := make(map[string][]ForeignKey)
+	neighbours := make(map[string][]string)
+	for _, table := range tables {
+		fks, err := s.getForeignKeys(ctx, schema, table)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrRetrievingForeignKeys, err).Error()), nil
+		}
+		for _, fk := range fks {
+			refTable, ok := inSchema[strings.ToLower(fk.RefTable)]
+			if !ok || (fk.RefSchema != "" && !strings.EqualFold(fk.RefSchema, schema)) {
+				continue
+			}
+			fk.RefTable = refTable
+			foreignKeys[table] = append(foreignKeys[table], fk)
+			neighbours[table] = append(neighbours[table], refTable)
+			neighbours[refTable] = append(neighbours[refTable], table)
+		}
+	}
+
+	included := make(map[string]bool)
+	if seed == "" {
+		for _, table := range tables {
+			included[table] = true
+		}
+	} else {
+		included[seed] = true
+		frontier := []string{seed}
+		for hop := 0; hop < depth && len(frontier) > 0; hop++ {
+			var next []string
+			for _, table := range frontier {
+				for _, neighbour := range neighbours[table] {
+					if !included[neighbour] {
+						included[neighbour] = true
+						next = append(next, neighbour)
+					}
+				}
+			}
+			frontier = next
+		}
+	}
+
+	diagram := &ERDiagram{Relationships: []*ERRelationship{}}
+	entities := make(map[string]*EREntity)
+	uniqueKeys := make(map[string][][]string)
+	for _, table := range tables {
+		if !included[table] {
+			continue
+		}
+		query, queryArgs := s.queryBuilder.GetTableSchemaFullQuery(schema, table)
+		columns, err := s.fetchSchemaColumns(ctx, query, queryArgs)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrRetrievingColumns, err).Error()), nil
+		}
+		entity := &EREntity{Name: table}
+		var primaryKey []string
+		for _, col := range s.visibleColumnMaps(ctx, schema, table, columns, "name") {
+			column := ERColumn{}
+			column.Name, _ = col["name"].(string)
+			column.Type, _ = col["type"].(string)
+			column.Nullable, _ = col["nullable"].(bool)
+			column.PrimaryKey, _ = col["is_primary_key"].(bool)
+			if column.PrimaryKey {
+				// SQLite reports INTEGER PRIMARY KEY columns as nullable
+				column.Nullable = false
+				primaryKey = append(primaryKey, column.Name)
+			}
+			entity.Columns = append(entity.Columns, column)
+		}
+		keys, _ := s.getUniqueKeys(ctx, schema, table)
+		if len(primaryKey) > 0 {
+			keys = append(keys, primaryKey)
+		}
+		uniqueKeys[table] = keys
+		entities[table] = entity
+		diagram.Entities = append(diagram.Entities, entity)
+	}
+
+	for _, entity := range diagram.Entities {
+		for _, fk := range foreignKeys[entity.Name] {
+			refEntity := entities[fk.RefTable]
+			if refEntity == nil || !hasERColumns(entity, fk.Columns) || !hasERColumns(refEntity, fk.RefColumns) {
+				continue
+			}
+			relationship := &ERRelationship{
+				Name:       fk.Name,
+				Table:      entity.Name,
+				Columns:    fk.Columns,
+				RefTable:   refEntity.Name,
+				RefColumns: fk.RefColumns,
+				Unique:     isUniqueKey(uniqueKeys[entity.Name], fk.Columns),
+			}
+			for i := range entity.Columns {
+				if containsFold(fk.Columns, entity.Columns[i].Name) {
+					entity.Columns[i].ForeignKey = true
+					relationship.Optional = relationship.Optional || entity.Columns[i].Nullable
+				}
+			}
+			relationship.Cardinality = relationship.cardinality()
+			diagram.Relationships = append(diagram.Relationships, relationship)
+		}
+	}
+
+	if keysOnly {
+		for _, entity := range diagram.Entities {
+			var keys []ERColumn
+			for _, col := range entity.Columns {
+				if col.PrimaryKey || col.ForeignKey {
+					keys = append(keys, col)
+				}
+			}
+			entity.Columns = keys
+		}
+	}
+
+	rendered, err := diagram.Render(format)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	names := make([]string, 0, len(diagram.Entities))
+	for _, entity := range diagram.Entities {
+		names = append(names, entity.Name)
+	}
+	response := map[string]interface{}{
+		"schema":        schema,
+		"format":        format,
+		"tables":        names,
+		"relationships": diagram.Relationships,
+		"diagram":       rendered,
+	}
+	if seed != "" {
+		response["table_name"] = seed
+		response["depth"] = depth
+	}
+	if len(tables) == MaxSchemaObjects {
+		response["warning"] = fmt.Sprintf("only the first %d tables of the schema were read", MaxSchemaObjects)
+	}
+
+	jsonData, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// hasERColumns reports whether the entity shows every given column, which
+// the policy may have hidden
+func hasERColumns(entity *EREntity, columns []string) bool {
+	for _, name := range columns {
+		found := false
+		for _, col := range entity.Columns {
+			if strings.EqualFold(col.Name, name) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return len(columns) > 0
+}
+
+// isUniqueKey reports whether columns are exactly the columns of one of the unique keys
+func isUniqueKey(keys [][]string, columns []string) bool {
+	for _, key := range keys {
+		if len(key) != len(columns) {
+			continue
+		}
+		matches := true
+		for _, col := range columns {
+			if !containsFold(key, col) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+// containsFold reports whether values holds value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,257 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func (s *DbMCPServer) toolExplainQuery() (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.Tool{
+		Name: "explain_query",
+		Description: "Returns the execution plan of a SELECT query as a tree of operators with estimated rows and cost, " +
+			"flagging full scans and missing indexes. The query is not executed unless 'analyze' is set (PostgreSQL only)",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"query": map[string]interface{}{
+					"type":        "string",
+					"description": "SQL query to explain (SELECT only)",
+				},
+				"analyze": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Run the query to collect actual row counts, in a read-only transaction (PostgreSQL only, default: false)",
+				},
+				"include_raw": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Include the plan as returned by the database (default: false)",
+				},
+			},
+			Required: []string{"query"},
+		},
+	}, s.handleExplainQuery
+}
+
+func (s *DbMCPServer) handleExplainQuery(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	args, ok := getArgs(request.Params.Arguments)
+	if !ok {
+		return mcp.NewToolResultError(ErrInvalidArguments.Error()), nil
+	}
+
+	query, ok := getStringArg(args, "query")
+	if !ok || query == "" {
+		return mcp.NewToolResultError(ErrQueryRequired.Error()), nil
+	}
+
+	validator := NewSQLValidator(query)
+	if err := validator.Validate(); err != nil {
+		log.Printf("Query blocked: %s\nReason: %v\n", query, err)
+		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrQueryNotAllowed, err).Error()), nil
+	}
+
+	analyze := getBoolArg(args, "analyze", false)
+	if analyze && !s.queryBuilder.IsPostgres() {
+		return mcp.NewToolResultError(ErrAnalyzeNotSupported.Error()), nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, DefaultQueryTimeout)
+	defer cancel()
+
+	if err := s.checkQueryPolicy(ctx, query); err != nil {
+		log.Printf("Query blocked: %s\nReason: %v\n", query, err)
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	auditStatement(ctx, query, nil)
+	plan, err := s.explainQuery(ctx, query, nil, analyze)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrExplainingQuery, err).Error()), nil
+	}
+
+	response := map[string]interface{}{
+		"driver":         s.queryBuilder.GetDriver(),
+		"analyze":        analyze,
+		"plan":           plan.Root,
+		"total_cost":     plan.TotalCost,
+		"estimated_rows": plan.EstimatedRows,
+		"full_scans":     plan.FullScans,
+		"warnings":       plan.Warnings,
+	}
+	if getBoolArg(args, "include_raw", false) {
+		response["raw"] = plan.Raw
+	}
+
+	jsonData, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// explainQuery returns the normalized execution plan of a validated query. params are
+// the values of its placeholders; Oracle plans leave bind variables unbound.
+func (s *DbMCPServer) explainQuery(ctx context.Context, query string, params []interface{}, analyze bool) (*ExecutionPlan, error) {
+	switch s.queryBuilder.GetDriver() {
+	case DriverPostgresSQL:
+		return s.explainPostgres(ctx, query, params, analyze)
+	case DriverMySQL:
+		return s.explainMySQL(ctx, query, params)
+	case DriverSQLServer:
+		return s.explainSQLServer(ctx, query, params)
+	case DriverOracle:
+		return s.explainOracle(ctx, query)
+	case DriverSQLite:
+		return s.explainSQLite(ctx, query, params)
+	default:
+		return nil, ErrFeatureNotSupported
+	}
+}
+
+func (s *DbMCPServer) explainPostgres(ctx context.Context, query string, params []interface{}, analyze bool) (*ExecutionPlan, error) {
+	explain := "EXPLAIN (FORMAT JSON) " + query
+	if analyze {
+		explain = "EXPLAIN (ANALYZE, FORMAT JSON) " + query
+	}
+
+	// ANALYZE runs the query: keep it in a read-only transaction that is always rolled back
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var raw []byte
+	if err := tx.QueryRowContext(ctx, explain, params...).Scan(&raw); err != nil {
+		return nil, err
+	}
+	return parsePostgresPlan(raw)
+}
+
+func (s *DbMCPServer) explainMySQL(ctx context.Context, query string, params []interface{}) (*ExecutionPlan, error) {
+	var raw []byte
+	if err := s.db.QueryRowContext(ctx, "EXPLAIN FORMAT=JSON "+query, params...).Scan(&raw); err != nil {
+		return nil, err
+	}
+	return parseMySQLPlan(raw)
+}
+
+func (s *DbMCPServer) explainSQLServer(ctx context.Context, query string, params []interface{}) (*ExecutionPlan, error) {
+	// SHOWPLAN_XML is a session setting: pin a connection for the whole exchange
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SET SHOWPLAN_XML ON"); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), "SET SHOWPLAN_XML OFF"); err != nil {
+			// Never return a connection with SHOWPLAN still on to the pool
+			_ = conn.Raw(func(interface{}) error { return driver.ErrBadConn })
+		}
+	}()
+
+	var raw string
+	if err := conn.QueryRowContext(ctx, query, params...).Scan(&raw); err != nil {
+		return nil, err
+	}
+	return parseSQLServerPlan(raw)
+}
+
+func (s *DbMCPServer) explainOracle(ctx context.Context, query string) (*ExecutionPlan, error) {
+	// PLAN_TABLE is a session-private temporary table: pin a connection
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	statementID := fmt.Sprintf("dbmcp_%d", time.Now().UnixNano())
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf("EXPLAIN PLAN SET STATEMENT_ID = '%s' FOR %s", statementID, query)); err != nil {
+		return nil, err
+	}
+	defer conn.ExecContext(context.Background(), "DELETE FROM PLAN_TABLE WHERE STATEMENT_ID = :1", statementID)
+
+	rows, err := conn.QueryContext(ctx, `
+		SELECT id, parent_id, operation, options, object_name, cardinality, cost,
+			NVL(filter_predicates, access_predicates)
+		FROM PLAN_TABLE
+		WHERE statement_id = :1
+		ORDER BY id`, statementID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var planRows []OraclePlanRow
+	for rows.Next() {
+		var r OraclePlanRow
+		var parentID sql.NullInt64
+		var options, object, filter sql.NullString
+		var cardinality, cost sql.NullFloat64
+		if err := rows.Scan(&r.ID, &parentID, &r.Operation, &options, &object, &cardinality, &cost, &filter); err != nil {
+			return nil, err
+		}
+		if parentID.Valid {
+			id := int(parentID.Int64)
+			r.ParentID = &id
+		}
+		r.Options, r.Object, r.Filter = options.String, object.String, filter.String
+		r.Rows, r.Cost = cardinality.Float64, cost.Float64
+		planRows = append(planRows, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Text rendering from DBMS_XPLAN, kept as the raw plan
+	var xplan string
+	xplanRows, err := conn.QueryContext(ctx, "SELECT plan_table_output FROM TABLE(DBMS_XPLAN.DISPLAY('PLAN_TABLE', :1, 'TYPICAL'))", statementID)
+	if err == nil {
+		defer xplanRows.Close()
+		for xplanRows.Next() {
+			var line sql.NullString
+			if err := xplanRows.Scan(&line); err == nil {
+				xplan += line.String + "\n"
+			}
+		}
+	}
+
+	return buildOraclePlan(planRows, xplan)
+}
+
+func (s *DbMCPServer) explainSQLite(ctx context.Context, query string, params []interface{}) (*ExecutionPlan, error) {
+	rows, err := s.db.QueryContext(ctx, "EXPLAIN QUERY PLAN "+query, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var planRows []SQLitePlanRow
+	for rows.Next() {
+		var r SQLitePlanRow
+		var notUsed int
+		if err := rows.Scan(&r.ID, &r.Parent, &notUsed, &r.Detail); err != nil {
+			return nil, err
+		}
+		planRows = append(planRows, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return buildSQLitePlan(planRows)
+}
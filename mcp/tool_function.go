@@ -2,7 +2,6 @@ package mcp
 
 import (
 	"context"
-	"database/sql"
 	"encoding/json"
 	"fmt"
 
@@ -13,7 +12,7 @@ import (
 func (s *DbMCPServer) toolListFunctions() (mcp.Tool, server.ToolHandlerFunc) {
 	return mcp.Tool{
 		Name:        "list_functions",
-		Description: "List database functions (scalar, table-valued) with pagination",
+		Description: "List database functions (scalar, table-valued) with pagination, one entry per overload with its signature, arguments (name, mode, type, default) and return type",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
 			Properties: map[string]interface{}{
@@ -84,33 +83,26 @@ func (s *DbMCPServer) handleListFunctions(ctx context.Context, request mcp.CallT
 	ctx, cancel := context.WithTimeout(ctx, ShortQueryTimeout)
 	defer cancel()
 
+	auditStatement(ctx, query, queryArgs)
 	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrListingFunctions, err).Error()), nil
 	}
 	defer rows.Close()
 
-	var functions []map[string]interface{}
+	var routines []routineRow
 	for rows.Next() {
-		var routineSchema, routineName, functionType string
-		var created, lastAltered sql.NullTime
-
-		if err = rows.Scan(&routineSchema, &routineName, &functionType, &created, &lastAltered); err != nil {
+		var r routineRow
+		if err = rows.Scan(&r.schema, &r.name, &r.functionType, &r.created, &r.lastAltered, &r.packageName, &r.specificName); err != nil {
 			continue
 		}
+		routines = append(routines, r)
+	}
+	rows.Close()
 
-		fn := map[string]interface{}{
-			"schema": routineSchema,
-			"name":   routineName,
-			"type":   functionType,
-		}
-		if created.Valid {
-			fn["created"] = created.Time.Format("2006-01-02 15:04:05")
-		}
-		if lastAltered.Valid {
-			fn["last_altered"] = lastAltered.Time.Format("2006-01-02 15:04:05")
-		}
-		functions = append(functions, fn)
+	functions, err := s.routineListEntries(ctx, s.functionQueries(), routines)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrListingFunctions, err).Error()), nil
 	}
 
 	response := map[string]interface{}{
@@ -138,7 +130,7 @@ func (s *DbMCPServer) handleListFunctions(ctx context.Context, request mcp.CallT
 func (s *DbMCPServer) toolGetFunctionCode() (mcp.Tool, server.ToolHandlerFunc) {
 	return mcp.Tool{
 		Name:        "get_function_code",
-		Description: "Returns the full source code of a function",
+		Description: "Returns the full source code of a function. When the function is overloaded, pass a signature or an overload number; otherwise its overloads are listed",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
 			Properties: map[string]interface{}{
@@ -150,6 +142,18 @@ func (s *DbMCPServer) toolGetFunctionCode() (mcp.Tool, server.ToolHandlerFunc) {
 					"type":        "string",
 					"description": "Schema name (optional)",
 				},
+				"package": map[string]interface{}{
+					"type":        "string",
+					"description": "Package the function belongs to (optional, Oracle)",
+				},
+				"signature": map[string]interface{}{
+					"type":        "string",
+					"description": "Signature of the overload as list_functions shows it, or its argument types, e.g. 'add(integer, integer)' (optional)",
+				},
+				"overload": map[string]interface{}{
+					"type":        "number",
+					"description": "Overload number as list_functions shows it (optional)",
+				},
 			},
 			Required: []string{"function_name"},
 		},
@@ -181,39 +185,19 @@ func (s *DbMCPServer) handleGetFunctionCode(ctx context.Context, request mcp.Cal
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	query, queryArgs := s.queryBuilder.GetFunctionCodeQuery(schema, functionName)
+	packageName, _ := getStringArg(args, "package")
+	if packageName != "" && !isValidIdentifier(packageName) {
+		return mcp.NewToolResultError(ErrInvalidPackageName.Error()), nil
+	}
+	signature, _ := getStringArg(args, "signature")
+	overload := getIntArg(args, "overload", 0)
 
 	ctx, cancel := context.WithTimeout(ctx, ShortQueryTimeout)
 	defer cancel()
 
-	// For Oracle, we need to collect all lines
-	if s.queryBuilder.IsOracle() {
-		return s.getOracleSourceCode(ctx, query, queryArgs, "function")
-	}
-
-	var definition sql.NullString
-	err = s.db.QueryRowContext(ctx, query, queryArgs...).Scan(&definition)
-	if err == sql.ErrNoRows {
+	if !s.objectVisible(ctx, schema, functionName) || (packageName != "" && !s.objectVisible(ctx, schema, packageName)) {
 		return mcp.NewToolResultError(ErrFunctionNotFound.Error()), nil
 	}
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrFetchingCode, err).Error()), nil
-	}
-
-	if !definition.Valid || definition.String == "" {
-		return mcp.NewToolResultError(ErrCodeNotAvailable.Error()), nil
-	}
-
-	response := map[string]interface{}{
-		"schema":     schema,
-		"name":       functionName,
-		"definition": definition.String,
-	}
 
-	jsonData, err := json.MarshalIndent(response, "", "  ")
-	if err != nil {
-		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
-	}
-
-	return mcp.NewToolResultText(string(jsonData)), nil
+	return s.routineCodeResult(ctx, s.functionQueries(), schema, packageName, functionName, signature, overload, ErrFunctionNotFound)
 }
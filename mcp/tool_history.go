@@ -0,0 +1,177 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Tool: List Query History
+func (s *DbMCPServer) toolListQueryHistory() (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.Tool{
+		Name:        "list_query_history",
+		Description: "Lists the latest execute_query, list_table_rows, aggregate_table and query_builder calls of the current session, newest first, with their SQL, datasource, duration and row count",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"limit": map[string]interface{}{
+					"type":        "number",
+					"description": "Maximum number of entries to return (default: 20)",
+				},
+			},
+		},
+	}, s.handleListQueryHistory
+}
+
+func (s *DbMCPServer) handleListQueryHistory(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.history == nil {
+		return mcp.NewToolResultError(ErrHistoryDisabled.Error()), nil
+	}
+
+	args, _ := getArgs(request.Params.Arguments)
+	limit := getIntArg(args, "limit", DefaultHistoryListLimit)
+	if limit <= 0 {
+		limit = DefaultHistoryListLimit
+	}
+
+	entries, err := s.history.List(sessionID(ctx), limit)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrOpeningHistory, err).Error()), nil
+	}
+
+	summaries := make([]map[string]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		summary := map[string]interface{}{
+			"id":          entry.ID,
+			"timestamp":   entry.Timestamp,
+			"datasource":  entry.Datasource,
+			"tool":        entry.Tool,
+			"duration_ms": entry.DurationMs,
+			"outcome":     entry.Outcome,
+		}
+		if len(entry.Statements) > 0 {
+			summary["sql"] = entry.Statements[len(entry.Statements)-1].SQL
+		}
+		if entry.RowCount != nil {
+			summary["row_count"] = *entry.RowCount
+		}
+		if entry.RerunOf != 0 {
+			summary["rerun_of"] = entry.RerunOf
+		}
+		summaries = append(summaries, summary)
+	}
+
+	response := map[string]interface{}{
+		"count":   len(summaries),
+		"entries": summaries,
+	}
+
+	jsonData, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// Tool: Get Query History Entry
+func (s *DbMCPServer) toolGetQueryHistoryEntry() (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.Tool{
+		Name:        "get_query_history_entry",
+		Description: "Returns a query history entry of the current session: the tool arguments, every SQL statement with its parameters, timings, row count and error",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"id": map[string]interface{}{
+					"type":        "number",
+					"description": "Entry ID, as returned by list_query_history",
+				},
+			},
+			Required: []string{"id"},
+		},
+	}, s.handleGetQueryHistoryEntry
+}
+
+func (s *DbMCPServer) handleGetQueryHistoryEntry(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	entry, err := s.historyEntry(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	jsonData, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// Tool: Rerun Query
+func (s *DbMCPServer) toolRerunQuery() (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.Tool{
+		Name:        "rerun_query",
+		Description: "Runs a query history entry of the current session again with the same arguments. The query is validated against the current policy, and must have been run on the active datasource",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"id": map[string]interface{}{
+					"type":        "number",
+					"description": "Entry ID, as returned by list_query_history",
+				},
+			},
+			Required: []string{"id"},
+		},
+	}, s.handleRerunQuery
+}
+
+func (s *DbMCPServer) handleRerunQuery(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	entry, err := s.historyEntry(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	if entry.Datasource != s.datasource {
+		return mcp.NewToolResultError(fmt.Sprintf("%v: '%s', active datasource is '%s'",
+			ErrHistoryDatasourceChanged, entry.Datasource, s.datasource)), nil
+	}
+
+	if entry.Redacted {
+		return mcp.NewToolResultError(fmt.Sprintf("%v: %d", ErrHistoryEntryRedacted, entry.ID)), nil
+	}
+
+	handler, ok := s.historyHandlers()[entry.Tool]
+	if !ok {
+		return mcp.NewToolResultError(fmt.Sprintf("%v: %d", ErrHistoryEntryNotFound, entry.ID)), nil
+	}
+
+	// The original tool handler checks the arguments, query and policy again
+	rerun := request
+	rerun.Params.Name = entry.Tool
+	rerun.Params.Arguments = entry.Arguments
+	return s.runWithHistory(ctx, rerun, handler, entry.ID)
+}
+
+// historyEntry returns the entry of the current session named by the id argument
+func (s *DbMCPServer) historyEntry(ctx context.Context, request mcp.CallToolRequest) (*HistoryEntry, error) {
+	if s.history == nil {
+		return nil, ErrHistoryDisabled
+	}
+
+	args, ok := getArgs(request.Params.Arguments)
+	if !ok {
+		return nil, ErrInvalidArguments
+	}
+	id := getIntArg(args, "id", 0)
+
+	entry, err := s.history.Get(sessionID(ctx), int64(id))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrOpeningHistory, err)
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("%w: %d", ErrHistoryEntryNotFound, id)
+	}
+	return entry, nil
+}
@@ -2,7 +2,6 @@ package mcp
 
 import (
 	"context"
-	"database/sql"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -14,7 +13,7 @@ import (
 func (s *DbMCPServer) toolListProcedures() (mcp.Tool, server.ToolHandlerFunc) {
 	return mcp.Tool{
 		Name:        "list_procedures",
-		Description: "List database stored procedures with pagination",
+		Description: "List database stored procedures with pagination, one entry per overload with its signature and arguments (name, mode, type, default)",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
 			Properties: map[string]interface{}{
@@ -69,32 +68,26 @@ func (s *DbMCPServer) handleListProcedures(ctx context.Context, request mcp.Call
 	ctx, cancel := context.WithTimeout(ctx, ShortQueryTimeout)
 	defer cancel()
 
+	auditStatement(ctx, query, queryArgs)
 	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrListingProcedures, err).Error()), nil
 	}
 	defer rows.Close()
 
-	var procedures []map[string]interface{}
+	var routines []routineRow
 	for rows.Next() {
-		var routineSchema, routineName string
-		var created, lastAltered sql.NullTime
-
-		if err = rows.Scan(&routineSchema, &routineName, &created, &lastAltered); err != nil {
+		var r routineRow
+		if err = rows.Scan(&r.schema, &r.name, &r.created, &r.lastAltered, &r.packageName, &r.specificName); err != nil {
 			continue
 		}
+		routines = append(routines, r)
+	}
+	rows.Close()
 
-		proc := map[string]interface{}{
-			"schema": routineSchema,
-			"name":   routineName,
-		}
-		if created.Valid {
-			proc["created"] = created.Time.Format("2006-01-02 15:04:05")
-		}
-		if lastAltered.Valid {
-			proc["last_altered"] = lastAltered.Time.Format("2006-01-02 15:04:05")
-		}
-		procedures = append(procedures, proc)
+	procedures, err := s.routineListEntries(ctx, s.procedureQueries(), routines)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrListingProcedures, err).Error()), nil
 	}
 
 	response := map[string]interface{}{
@@ -133,6 +126,18 @@ func (s *DbMCPServer) toolGetProcedureCode() (mcp.Tool, server.ToolHandlerFunc)
 					"type":        "string",
 					"description": "Schema name (optional)",
 				},
+				"package": map[string]interface{}{
+					"type":        "string",
+					"description": "Package the procedure belongs to (optional, Oracle)",
+				},
+				"signature": map[string]interface{}{
+					"type":        "string",
+					"description": "Signature of the overload as list_procedures shows it, or its argument types (optional)",
+				},
+				"overload": map[string]interface{}{
+					"type":        "number",
+					"description": "Overload number as list_procedures shows it (optional)",
+				},
 			},
 			Required: []string{"procedure_name"},
 		},
@@ -164,41 +169,21 @@ func (s *DbMCPServer) handleGetProcedureCode(ctx context.Context, request mcp.Ca
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	query, queryArgs := s.queryBuilder.GetProcedureCodeQuery(schema, procedureName)
+	packageName, _ := getStringArg(args, "package")
+	if packageName != "" && !isValidIdentifier(packageName) {
+		return mcp.NewToolResultError(ErrInvalidPackageName.Error()), nil
+	}
+	signature, _ := getStringArg(args, "signature")
+	overload := getIntArg(args, "overload", 0)
 
 	ctx, cancel := context.WithTimeout(ctx, ShortQueryTimeout)
 	defer cancel()
 
-	// For Oracle, we need to collect all lines
-	if s.queryBuilder.IsOracle() {
-		return s.getOracleSourceCode(ctx, query, queryArgs, "procedure")
-	}
-
-	var definition sql.NullString
-	err = s.db.QueryRowContext(ctx, query, queryArgs...).Scan(&definition)
-	if err == sql.ErrNoRows {
+	if !s.objectVisible(ctx, schema, procedureName) || (packageName != "" && !s.objectVisible(ctx, schema, packageName)) {
 		return mcp.NewToolResultError(ErrProcedureNotFound.Error()), nil
 	}
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrFetchingCode, err).Error()), nil
-	}
-
-	if !definition.Valid || definition.String == "" {
-		return mcp.NewToolResultError(ErrCodeNotAvailable.Error()), nil
-	}
-
-	response := map[string]interface{}{
-		"schema":     schema,
-		"name":       procedureName,
-		"definition": definition.String,
-	}
 
-	jsonData, err := json.MarshalIndent(response, "", "  ")
-	if err != nil {
-		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
-	}
-
-	return mcp.NewToolResultText(string(jsonData)), nil
+	return s.routineCodeResult(ctx, s.procedureQueries(), schema, packageName, procedureName, signature, overload, ErrProcedureNotFound)
 }
 
 func (s *DbMCPServer) toolExecuteProcedure() (mcp.Tool, server.ToolHandlerFunc) {
@@ -207,7 +192,7 @@ func (s *DbMCPServer) toolExecuteProcedure() (mcp.Tool, server.ToolHandlerFunc)
 		Description: "Execute a stored procedure with parameters",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
-			Properties: map[string]interface{}{
+			Properties: resultBudgetProperties(map[string]interface{}{
 				"procedure_name": map[string]interface{}{
 					"type":        "string",
 					"description": "Stored procedure name",
@@ -220,7 +205,7 @@ func (s *DbMCPServer) toolExecuteProcedure() (mcp.Tool, server.ToolHandlerFunc)
 					"type":        "string",
 					"description": "Schema name (optional)",
 				},
-			},
+			}),
 			Required: []string{"procedure_name"},
 		},
 	}, s.handleExecuteProcedure
@@ -259,6 +244,10 @@ func (s *DbMCPServer) handleExecuteProcedure(ctx context.Context, request mcp.Ca
 	ctx, cancel := context.WithTimeout(ctx, DefaultQueryTimeout)
 	defer cancel()
 
+	if !s.objectVisible(ctx, schema, procedureName) {
+		return mcp.NewToolResultError(ErrProcedureNotFound.Error()), nil
+	}
+
 	// Build and execute the procedure call based on driver
 	var execSQL string
 	var paramValues []interface{}
@@ -278,6 +267,7 @@ func (s *DbMCPServer) handleExecuteProcedure(ctx context.Context, request mcp.Ca
 		return mcp.NewToolResultError(ErrFeatureNotSupported.Error()), nil
 	}
 
+	auditStatement(ctx, execSQL, paramValues)
 	resultRows, err := s.db.QueryContext(ctx, execSQL, paramValues...)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrExecutingProcedure, err).Error()), nil
@@ -289,7 +279,10 @@ func (s *DbMCPServer) handleExecuteProcedure(ctx context.Context, request mcp.Ca
 		return mcp.NewToolResultText("Procedure executed successfully (no results)"), nil
 	}
 
+	// The source tables of procedure results are unknown: column rules match by name only
+	mask := s.rowMask(ctx, columns, nil)
 	var results []map[string]interface{}
+	budget := newResultBudget(args)
 	for resultRows.Next() {
 		values := make([]interface{}, len(columns))
 		valuePtrs := make([]interface{}, len(columns))
@@ -305,9 +298,16 @@ func (s *DbMCPServer) handleExecuteProcedure(ctx context.Context, request mcp.Ca
 		for i, col := range columns {
 			row[col] = formatValue(values[i])
 		}
+		mask.Mask(row)
+		if !budget.Add(columns, row, len(results)) {
+			break
+		}
 		results = append(results, row)
 	}
 
+	auditRowCount(ctx, len(results))
+	masked := mask.Columns()
+
 	response := map[string]interface{}{
 		"status":    "success",
 		"procedure": procedureName,
@@ -315,6 +315,10 @@ func (s *DbMCPServer) handleExecuteProcedure(ctx context.Context, request mcp.Ca
 		"results":   results,
 		"row_count": len(results),
 	}
+	budget.Describe(response)
+	if len(masked) > 0 {
+		response["masked_columns"] = masked
+	}
 
 	jsonData, err := json.MarshalIndent(response, "", "  ")
 	if err != nil {
@@ -385,36 +389,3 @@ func (s *DbMCPServer) buildOracleProcedureCall(qualifiedName string, params map[
 	execSQL := fmt.Sprintf("BEGIN %s(%s); END;", qualifiedName, strings.Join(placeholders, ", "))
 	return execSQL, paramValues
 }
-
-// getOracleSourceCode retrieves source code that spans multiple lines (Oracle specific)
-func (s *DbMCPServer) getOracleSourceCode(ctx context.Context, query string, args []interface{}, objectType string) (*mcp.CallToolResult, error) {
-	rows, err := s.db.QueryContext(ctx, query, args...)
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrFetchingCode, err).Error()), nil
-	}
-	defer rows.Close()
-
-	var lines []string
-	for rows.Next() {
-		var line string
-		if err := rows.Scan(&line); err != nil {
-			continue
-		}
-		lines = append(lines, line)
-	}
-
-	if len(lines) == 0 {
-		return mcp.NewToolResultError(ErrCodeNotAvailable.Error()), nil
-	}
-
-	response := map[string]interface{}{
-		"definition": strings.Join(lines, ""),
-	}
-
-	jsonData, err := json.MarshalIndent(response, "", "  ")
-	if err != nil {
-		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
-	}
-
-	return mcp.NewToolResultText(string(jsonData)), nil
-}
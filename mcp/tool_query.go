@@ -2,6 +2,7 @@ package mcp
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -18,7 +19,7 @@ func (s *DbMCPServer) toolExecuteQuery() (mcp.Tool, server.ToolHandlerFunc) {
 		Description: "Executes a SELECT query and returns the results. Only read-only queries are allowed.",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
-			Properties: map[string]interface{}{
+			Properties: resultBudgetProperties(map[string]interface{}{
 				"query": map[string]interface{}{
 					"type":        "string",
 					"description": "SQL query to be executed (SELECT only)",
@@ -27,7 +28,7 @@ func (s *DbMCPServer) toolExecuteQuery() (mcp.Tool, server.ToolHandlerFunc) {
 					"type":        "number",
 					"description": "Maximum number of rows to be returned (default: 100, max: 10000)",
 				},
-			},
+			}),
 			Required: []string{"query"},
 		},
 	}, s.handleExecuteQuery
@@ -48,13 +49,6 @@ func (s *DbMCPServer) handleExecuteQuery(ctx context.Context, request mcp.CallTo
 		return mcp.NewToolResultError(ErrQueryRequired.Error()), nil
 	}
 
-	// Complete validation
-	validator := NewSQLValidator(query)
-	if err := validator.Validate(); err != nil {
-		log.Printf("Query blocked: %s\nReason: %v\n", query, err)
-		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrQueryNotAllowed, err).Error()), nil
-	}
-
 	maxRows := getIntArg(args, "max_rows", 100)
 	if maxRows <= 0 {
 		maxRows = 100
@@ -63,21 +57,59 @@ func (s *DbMCPServer) handleExecuteQuery(ctx context.Context, request mcp.CallTo
 		maxRows = 10000
 	}
 
+	response, err := s.runReadQuery(ctx, query, nil, maxRows, newResultBudget(args))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	jsonData, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// runReadQuery validates a read-only query against the SQL rules, the policy and the
+// query guard, runs it with its bound parameters and returns the execute_query response
+func (s *DbMCPServer) runReadQuery(ctx context.Context, query string, params []interface{}, maxRows int, budget *ResultBudget) (map[string]interface{}, error) {
+	// Complete validation
+	validator := NewSQLValidator(query)
+	if err := validator.Validate(); err != nil {
+		log.Printf("Query blocked: %s\nReason: %v\n", query, err)
+		return nil, fmt.Errorf("%w: %v", ErrQueryNotAllowed, err)
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, DefaultQueryTimeout)
 	defer cancel()
 
-	rows, err := s.db.QueryContext(ctx, query)
+	// Reject queries reading objects hidden by the policy
+	if err := s.checkQueryPolicy(ctx, query); err != nil {
+		log.Printf("Query blocked: %s\nReason: %v\n", query, err)
+		return nil, err
+	}
+
+	// Reject expensive queries before running them
+	if err := s.checkQueryCost(ctx, query, params); err != nil {
+		log.Printf("Query blocked: %s\nReason: %v\n", query, err)
+		return nil, err
+	}
+
+	auditStatement(ctx, query, params)
+	rows, err := s.db.QueryContext(ctx, query, params...)
 	if err != nil {
 		log.Printf("Error in query: %v\nQuery: %s\n", err, query)
-		return mcp.NewToolResultError(ErrQuerySyntax.Error()), nil
+		return nil, ErrQuerySyntax
 	}
 	defer rows.Close()
 
 	columns, err := rows.Columns()
 	if err != nil {
-		return mcp.NewToolResultError(ErrRetrievingColumns.Error()), nil
+		return nil, ErrRetrievingColumns
 	}
 
+	// Mask each row before the budget cuts its cells
+	mask := s.rowMask(ctx, columns, queryMaskSources(query, columns))
 	var results []map[string]interface{}
 	count := 0
 
@@ -89,36 +121,73 @@ func (s *DbMCPServer) handleExecuteQuery(ctx context.Context, request mcp.CallTo
 		}
 
 		if err = rows.Scan(valuePtrs...); err != nil {
-			return mcp.NewToolResultError(ErrReadingRow.Error()), nil
+			return nil, ErrReadingRow
 		}
 
 		row := make(map[string]interface{})
 		for i, col := range columns {
 			row[col] = formatValue(values[i])
 		}
+		mask.Mask(row)
+		if !budget.Add(columns, row, count) {
+			break
+		}
 		results = append(results, row)
 		count++
 	}
 
 	if err = rows.Err(); err != nil {
 		log.Printf("Error during iteration: %v\n", err)
-		return mcp.NewToolResultError(ErrReadingResults.Error()), nil
+		return nil, ErrReadingResults
 	}
 
+	auditRowCount(ctx, len(results))
+	masked := mask.Columns()
+
 	response := map[string]interface{}{
 		"rows":      results,
 		"row_count": len(results),
 		"columns":   columns,
-		"truncated": count >= maxRows,
+		"truncated": count >= maxRows || budget.Reason() != "",
 		"max_rows":  maxRows,
 	}
+	if count >= maxRows {
+		response["truncated_reason"] = "max_rows"
+	}
+	budget.Describe(response)
+	if len(masked) > 0 {
+		response["masked_columns"] = masked
+	}
+	return response, nil
+}
 
-	jsonData, err := json.MarshalIndent(response, "", "  ")
+// scanRows reads all rows into maps keyed by column name, formatting values for JSON
+func scanRows(rows *sql.Rows) ([]string, []map[string]interface{}, error) {
+	columns, err := rows.Columns()
 	if err != nil {
-		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
+		return nil, nil, err
 	}
 
-	return mcp.NewToolResultText(string(jsonData)), nil
+	var results []map[string]interface{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		valuePtrs := make([]interface{}, len(columns))
+		for i := range values {
+			valuePtrs[i] = &values[i]
+		}
+
+		if err = rows.Scan(valuePtrs...); err != nil {
+			return nil, nil, err
+		}
+
+		row := make(map[string]interface{})
+		for i, col := range columns {
+			row[col] = formatValue(values[i])
+		}
+		results = append(results, row)
+	}
+
+	return columns, results, rows.Err()
 }
 
 // formatValue converts database values to JSON-safe formats
@@ -0,0 +1,528 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// queryTable is a table taking part in a query_builder query
+type queryTable struct {
+	schema      string
+	table       string
+	alias       string
+	columns     []TableColumn
+	foreignKeys []ForeignKey
+}
+
+// joinCandidate is a foreign key that can join a new table to one already in the query
+type joinCandidate struct {
+	foreignKey  ForeignKey
+	fromAlias   string
+	fromColumns []string
+	columns     []string
+	toParent    bool // the new table is the referenced (parent) side
+}
+
+func (s *DbMCPServer) toolQueryBuilder() (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.Tool{
+		Name: "query_builder",
+		Description: "Builds and runs a read-only query over a table and tables joined along foreign keys, from a JSON spec. " +
+			"Returns the generated SQL together with the results",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"table": map[string]interface{}{
+					"type":        "string",
+					"description": "Base table name",
+				},
+				"schema": map[string]interface{}{
+					"type":        "string",
+					"description": "Schema of the base table (optional)",
+				},
+				"alias": map[string]interface{}{
+					"type":        "string",
+					"description": "Alias of the base table (default: table name)",
+				},
+				"joins": map[string]interface{}{
+					"type": "array",
+					"description": "Tables to join. Each item is {\"table\": \"customers\", \"schema\": \"...\", \"alias\": \"c\", " +
+						"\"type\": \"inner|left\", \"foreign_key\": \"fk_orders_customer\", \"to\": \"o\"}. " +
+						"The join condition comes from a foreign key between the new table and a table already in the query; " +
+						"'foreign_key' and 'to' (alias of the table to join to) are only needed when it is ambiguous",
+				},
+				"columns": map[string]interface{}{
+					"type": "array",
+					"description": "Columns to return: \"alias.column\" or \"column\" (if unambiguous), or {\"column\": \"c.name\", \"alias\": \"customer\"}. " +
+						"Default: all columns of all tables. Cannot be combined with group_by/aggregates",
+				},
+				"filters": filterInputSchema(),
+				"group_by": map[string]interface{}{
+					"type":        "array",
+					"description": "Columns to group by, as in aggregate_table, with \"alias.column\" references",
+				},
+				"aggregates": map[string]interface{}{
+					"type":        "array",
+					"description": "Aggregates to compute, as in aggregate_table, with \"alias.column\" references",
+				},
+				"order_by": map[string]interface{}{
+					"description": "Sorting by output column. A name, or an array of names or " +
+						"{\"column\": \"customer\", \"direction\": \"asc|desc\", \"nulls\": \"first|last\"} objects",
+				},
+				"order_direction": map[string]interface{}{
+					"type":        "string",
+					"description": "Default sorting direction: ASC or DESC (default: ASC)",
+				},
+				"limit": map[string]interface{}{
+					"type":        "number",
+					"description": "Maximum number of rows (default: 100, maximum: 1000)",
+				},
+				"offset": map[string]interface{}{
+					"type":        "number",
+					"description": "Rows to skip (default: 0)",
+				},
+				"render_only": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Only return the generated SQL and parameters without running it (default: false)",
+				},
+			},
+			Required: []string{"table"},
+		},
+	}, s.handleQueryBuilder
+}
+
+func (s *DbMCPServer) handleQueryBuilder(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	args, ok := getArgs(request.Params.Arguments)
+	if !ok {
+		return mcp.NewToolResultError(ErrInvalidArguments.Error()), nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, DefaultQueryTimeout)
+	defer cancel()
+
+	// Base table
+	base, err := s.loadQueryTable(ctx, args, "table")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	tables := []*queryTable{base}
+
+	// Joins
+	joins, err := s.parseJoins(ctx, args["joins"], &tables)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	lookup := s.queryColumnLookup(tables)
+
+	// Projection, grouping and aggregates
+	var projection []SelectTerm
+	var groupBy []GroupByTerm
+	var aggregates []AggregateTerm
+
+	if args["group_by"] != nil || args["aggregates"] != nil {
+		if args["columns"] != nil {
+			return mcp.NewToolResultError(ErrColumnsWithAggregates.Error()), nil
+		}
+		if groupBy, err = s.parseGroupBy(args["group_by"], lookup); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if args["aggregates"] != nil {
+			if aggregates, err = s.parseAggregates(args["aggregates"], lookup, len(groupBy) == 0); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+	} else if projection, err = s.parseProjection(args["columns"], tables, lookup); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	orderBy, err := s.parseOutputOrderBy(args, projection, groupBy, aggregates)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// Filters
+	filter, err := ParseFilter(args["filters"])
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	fb := NewFilterBuilder(s.queryBuilder, func(name string) (string, error) {
+		qualifier, column, err := lookup(name)
+		if err != nil {
+			return "", err
+		}
+		return s.queryBuilder.ColumnRef(qualifier, column), nil
+	}, 1)
+	expr, err := fb.Build(filter)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	whereClause := ""
+	if expr != "" {
+		whereClause = "WHERE " + expr
+	}
+	queryParams := fb.Params()
+
+	limit := getIntArg(args, "limit", DefaultPageSize)
+	if limit <= 0 {
+		limit = DefaultPageSize
+	}
+	if limit > MaxRowsPageSize {
+		limit = MaxRowsPageSize
+	}
+	offset := getIntArg(args, "offset", 0)
+	if offset < 0 {
+		offset = 0
+	}
+
+	query := s.queryBuilder.BuildJoinQuery(JoinQueryParams{
+		Schema:      base.schema,
+		Table:       base.table,
+		Alias:       base.alias,
+		Joins:       joins,
+		Columns:     projection,
+		GroupBy:     groupBy,
+		Aggregates:  aggregates,
+		WhereClause: whereClause,
+		OrderBy:     orderBy,
+		Limit:       limit,
+		Offset:      offset,
+	})
+
+	var joinInfo []map[string]interface{}
+	for _, j := range joins {
+		joinInfo = append(joinInfo, map[string]interface{}{
+			"table": j.Table,
+			"alias": j.Alias,
+			"type":  j.Type,
+			"from":  j.FromAlias,
+		})
+	}
+
+	response := map[string]interface{}{
+		"query":  query,
+		"params": queryParams,
+		"joins":  joinInfo,
+	}
+
+	if getBoolArg(args, "render_only", false) {
+		jsonData, err := json.MarshalIndent(response, "", "  ")
+		if err != nil {
+			return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
+		}
+		return mcp.NewToolResultText(string(jsonData)), nil
+	}
+
+	auditStatement(ctx, query, queryParams)
+	dbRows, err := s.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrExecutingQuery, err).Error()), nil
+	}
+	defer dbRows.Close()
+
+	resultColumns, rows, err := scanRows(dbRows)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrExecutingQuery, err).Error()), nil
+	}
+
+	auditRowCount(ctx, len(rows))
+	sources := termMaskSources(func(qualifier string) (string, string) {
+		for _, t := range tables {
+			if t.alias == qualifier {
+				return t.schema, t.table
+			}
+		}
+		return "", ""
+	}, projection, groupBy, aggregates)
+	if masked := s.maskRows(ctx, resultColumns, sources, rows); len(masked) > 0 {
+		response["masked_columns"] = masked
+	}
+
+	response["columns"] = resultColumns
+	response["rows"] = rows
+	response["row_count"] = len(rows)
+	response["limit"] = limit
+	response["offset"] = offset
+
+	jsonData, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// loadQueryTable validates a table reference (name key, "schema" and "alias")
+// and loads its columns and foreign keys
+func (s *DbMCPServer) loadQueryTable(ctx context.Context, spec map[string]interface{}, nameKey string) (*queryTable, error) {
+	tableName, ok := getStringArg(spec, nameKey)
+	if !ok || !isValidIdentifier(tableName) {
+		return nil, ErrInvalidTableName
+	}
+
+	schema, err := getValidSchema(spec, getDefaultSchema(s.queryBuilder.GetDriver()))
+	if err != nil {
+		return nil, err
+	}
+
+	alias, _ := getStringArg(spec, "alias")
+	if alias == "" {
+		alias = tableName
+	}
+	if !isValidIdentifier(alias) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAlias, alias)
+	}
+
+	columns, err := s.getTableColumns(ctx, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRetrievingColumns, err)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("%w: %s.%s", ErrTableNotFound, schema, tableName)
+	}
+
+	foreignKeys, err := s.getForeignKeys(ctx, schema, tableName)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRetrievingForeignKeys, err)
+	}
+
+	return &queryTable{
+		schema:      schema,
+		table:       tableName,
+		alias:       alias,
+		columns:     columns,
+		foreignKeys: foreignKeys,
+	}, nil
+}
+
+// parseJoins parses the "joins" argument, adding each joined table to tables
+func (s *DbMCPServer) parseJoins(ctx context.Context, raw interface{}, tables *[]*queryTable) ([]JoinClause, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	items, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: expected an array", ErrInvalidJoin)
+	}
+	if len(items) > MaxJoins {
+		return nil, fmt.Errorf("%w (maximum %d)", ErrTooManyJoins, MaxJoins)
+	}
+
+	var joins []JoinClause
+	for _, item := range items {
+		spec, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%w: expected an object", ErrInvalidJoin)
+		}
+
+		joinType := "INNER"
+		if t, ok := getStringArg(spec, "type"); ok && t != "" {
+			joinType = strings.ToUpper(t)
+			if joinType != "INNER" && joinType != "LEFT" {
+				return nil, fmt.Errorf("%w: type must be inner or left", ErrInvalidJoin)
+			}
+		}
+
+		table, err := s.loadQueryTable(ctx, spec, "table")
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range *tables {
+			if strings.EqualFold(t.alias, table.alias) {
+				return nil, fmt.Errorf("%w: %s", ErrDuplicateTableAlias, table.alias)
+			}
+		}
+
+		fkName, _ := getStringArg(spec, "foreign_key")
+		to, _ := getStringArg(spec, "to")
+
+		candidate, err := chooseJoinCandidate(*tables, table, fkName, to)
+		if err != nil {
+			return nil, err
+		}
+
+		joins = append(joins, JoinClause{
+			Type:        joinType,
+			Schema:      table.schema,
+			Table:       table.table,
+			Alias:       table.alias,
+			FromAlias:   candidate.fromAlias,
+			FromColumns: candidate.fromColumns,
+			Columns:     candidate.columns,
+		})
+		*tables = append(*tables, table)
+	}
+	return joins, nil
+}
+
+// chooseJoinCandidate finds the foreign key joining table to one of the tables already in the query
+func chooseJoinCandidate(tables []*queryTable, table *queryTable, fkName, to string) (*joinCandidate, error) {
+	var candidates []joinCandidate
+	for _, t := range tables {
+		if to != "" && !strings.EqualFold(t.alias, to) {
+			continue
+		}
+		// t references the new table
+		for _, fk := range t.foreignKeys {
+			if fkReferences(fk, table) {
+				candidates = append(candidates, joinCandidate{
+					foreignKey:  fk,
+					fromAlias:   t.alias,
+					fromColumns: fk.Columns,
+					columns:     fk.RefColumns,
+					toParent:    true,
+				})
+			}
+		}
+		// the new table references t
+		for _, fk := range table.foreignKeys {
+			if fkReferences(fk, t) {
+				candidates = append(candidates, joinCandidate{
+					foreignKey:  fk,
+					fromAlias:   t.alias,
+					fromColumns: fk.RefColumns,
+					columns:     fk.Columns,
+				})
+			}
+		}
+	}
+
+	if fkName != "" {
+		var named []joinCandidate
+		for _, c := range candidates {
+			if strings.EqualFold(c.foreignKey.Name, fkName) {
+				named = append(named, c)
+			}
+		}
+		candidates = named
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, fmt.Errorf("%w: %s", ErrNoForeignKeyFound, table.alias)
+	case 1:
+		return &candidates[0], nil
+	}
+
+	// A self-referencing key matches in both directions: join to the parent row
+	if len(candidates) == 2 && candidates[0].fromAlias == candidates[1].fromAlias &&
+		candidates[0].foreignKey.Name == candidates[1].foreignKey.Name {
+		for i := range candidates {
+			if candidates[i].toParent {
+				return &candidates[i], nil
+			}
+		}
+	}
+
+	var names []string
+	for _, c := range candidates {
+		names = append(names, fmt.Sprintf("%s (to %s)", c.foreignKey.Name, c.fromAlias))
+	}
+	return nil, fmt.Errorf("%w for %s: %s - set 'foreign_key' and/or 'to'", ErrAmbiguousForeignKey, table.alias, strings.Join(names, ", "))
+}
+
+// fkReferences reports whether a foreign key references the given table
+func fkReferences(fk ForeignKey, t *queryTable) bool {
+	if !strings.EqualFold(fk.RefTable, t.table) {
+		return false
+	}
+	return fk.RefSchema == "" || strings.EqualFold(fk.RefSchema, t.schema)
+}
+
+// queryColumnLookup returns a columnLookup over several tables.
+// References are "alias.column", or "column" when only one table has it.
+func (s *DbMCPServer) queryColumnLookup(tables []*queryTable) columnLookup {
+	return func(name string) (string, string, error) {
+		if alias, column, ok := strings.Cut(name, "."); ok {
+			for _, t := range tables {
+				if strings.EqualFold(t.alias, alias) {
+					col, ok := s.resolveColumn(t.columns, column)
+					if !ok {
+						return "", "", fmt.Errorf("%w: %s", ErrColumnNotExists, name)
+					}
+					return t.alias, col, nil
+				}
+			}
+			return "", "", fmt.Errorf("%w: unknown table alias %s", ErrColumnNotExists, alias)
+		}
+
+		var qualifier, found string
+		for _, t := range tables {
+			if col, ok := s.resolveColumn(t.columns, name); ok {
+				if found != "" {
+					return "", "", fmt.Errorf("%w: %s - use alias.column", ErrAmbiguousColumn, name)
+				}
+				qualifier, found = t.alias, col
+			}
+		}
+		if found == "" {
+			return "", "", fmt.Errorf("%w: %s", ErrColumnNotExists, name)
+		}
+		return qualifier, found, nil
+	}
+}
+
+// parseProjection parses the "columns" argument. Without it, every column of every
+// table is returned; column names present in several tables are prefixed with the alias.
+func (s *DbMCPServer) parseProjection(raw interface{}, tables []*queryTable, lookup columnLookup) ([]SelectTerm, error) {
+	if raw == nil {
+		counts := make(map[string]int)
+		for _, t := range tables {
+			for _, col := range t.columns {
+				counts[strings.ToLower(col.Name)]++
+			}
+		}
+
+		var terms []SelectTerm
+		for _, t := range tables {
+			for _, col := range t.columns {
+				alias := col.Name
+				if counts[strings.ToLower(col.Name)] > 1 {
+					alias = t.alias + "_" + col.Name
+				}
+				terms = append(terms, SelectTerm{Qualifier: t.alias, Column: col.Name, Alias: alias})
+			}
+		}
+		return terms, nil
+	}
+
+	items, ok := raw.([]interface{})
+	if !ok || len(items) == 0 {
+		return nil, ErrNoColumnsSelected
+	}
+
+	var terms []SelectTerm
+	for _, item := range items {
+		var name, alias string
+		switch v := item.(type) {
+		case string:
+			name = v
+		case map[string]interface{}:
+			name, _ = v["column"].(string)
+			alias, _ = v["alias"].(string)
+		default:
+			return nil, fmt.Errorf("%w: expected a column name or an object", ErrInvalidColumnName)
+		}
+
+		qualifier, column, err := lookup(name)
+		if err != nil {
+			return nil, err
+		}
+		if alias == "" {
+			alias = column
+		}
+		if !isValidIdentifier(alias) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidAlias, alias)
+		}
+		terms = append(terms, SelectTerm{Qualifier: qualifier, Column: column, Alias: alias})
+	}
+	return terms, nil
+}
@@ -0,0 +1,368 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// routineQueries are the query builders of one kind of routine, functions or procedures
+type routineQueries struct {
+	arguments     func(schema, packageName, name string) (string, []interface{})
+	listArguments func(schema string) (string, []interface{})
+	code          func(schema, name string) (string, []interface{})
+	overloadCode  func(schema, name, specificName string) (string, []interface{})
+	packageCode   func(schema, packageName string) (string, []interface{})
+}
+
+func (s *DbMCPServer) functionQueries() routineQueries {
+	return routineQueries{
+		arguments:     s.queryBuilder.GetFunctionArgumentsQuery,
+		listArguments: s.queryBuilder.ListFunctionArgumentsQuery,
+		code:          s.queryBuilder.GetFunctionCodeQuery,
+		overloadCode:  s.queryBuilder.GetFunctionOverloadCodeQuery,
+		packageCode:   s.queryBuilder.GetFunctionPackageCodeQuery,
+	}
+}
+
+func (s *DbMCPServer) procedureQueries() routineQueries {
+	return routineQueries{
+		arguments:     s.queryBuilder.GetProcedureArgumentsQuery,
+		listArguments: s.queryBuilder.ListProcedureArgumentsQuery,
+		code:          s.queryBuilder.GetProcedureCodeQuery,
+		overloadCode:  s.queryBuilder.GetProcedureOverloadCodeQuery,
+		packageCode:   s.queryBuilder.GetProcedurePackageCodeQuery,
+	}
+}
+
+// routineRow is a row of a function or procedure listing, one per overload
+type routineRow struct {
+	schema       string
+	name         string
+	functionType string
+	created      sql.NullTime
+	lastAltered  sql.NullTime
+	packageName  sql.NullString
+	specificName sql.NullString
+}
+
+// routineOverload is one overload of a function or procedure with its arguments
+type routineOverload struct {
+	specificName string
+	arguments    []map[string]interface{}
+	returnType   string
+}
+
+// fetchRoutineOverloads returns the overloads of a routine in the order the database
+// lists them, nil when the routine does not exist
+func (s *DbMCPServer) fetchRoutineOverloads(ctx context.Context, queries routineQueries, schema, packageName, name string) ([]routineOverload, error) {
+	query, args := queries.arguments(schema, packageName, name)
+	if query == "" {
+		return nil, nil
+	}
+
+	overloads, err := s.queryRoutineOverloads(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	// The query selects the one routine, whose stored name may differ in case from name
+	for _, routineOverloads := range overloads {
+		return routineOverloads, nil
+	}
+	return nil, nil
+}
+
+// routineKey identifies a routine of a schema by package ("" when standalone) and name
+func routineKey(packageName, name string) string {
+	return packageName + "." + name
+}
+
+// queryRoutineOverloads runs a GetArguments or ListArguments query and returns the
+// overloads of each routine by routineKey, in the order the database lists them
+func (s *DbMCPServer) queryRoutineOverloads(ctx context.Context, query string, args []interface{}) (map[string][]routineOverload, error) {
+	auditStatement(ctx, query, args)
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	overloads := make(map[string][]routineOverload)
+	// Overloads are grouped by specific name whatever the row order
+	indexes := make(map[[2]string]int)
+	for rows.Next() {
+		var routineName, specificName, hasDefault string
+		var position sql.NullInt64
+		var packageName, argumentName, mode, dataType, defaultValue, returnType sql.NullString
+		if err := rows.Scan(&routineName, &packageName, &specificName, &position, &argumentName, &mode, &dataType, &defaultValue, &hasDefault, &returnType); err != nil {
+			return nil, err
+		}
+
+		key := routineKey(packageName.String, routineName)
+		index, ok := indexes[[2]string{key, specificName}]
+		if !ok {
+			index = len(overloads[key])
+			indexes[[2]string{key, specificName}] = index
+			overloads[key] = append(overloads[key], routineOverload{specificName: specificName, arguments: []map[string]interface{}{}})
+		}
+		overload := &overloads[key][index]
+		if returnType.Valid {
+			overload.returnType = returnType.String
+		}
+
+		switch {
+		case !dataType.Valid:
+			// The single row of a routine without arguments
+		case position.Valid && position.Int64 == 0:
+			overload.returnType = dataType.String
+		default:
+			argument := map[string]interface{}{
+				"name": argumentName.String,
+				"mode": "IN",
+				"type": dataType.String,
+			}
+			if mode.Valid && mode.String != "" {
+				argument["mode"] = mode.String
+			}
+			if defaultValue.Valid {
+				argument["default"] = defaultValue.String
+			}
+			if hasDefault == "YES" {
+				argument["has_default"] = true
+			}
+			overload.arguments = append(overload.arguments, argument)
+		}
+	}
+	return overloads, rows.Err()
+}
+
+// routineSignature returns the signature of an overload, such as
+// add(a integer, OUT total integer), with the mode of the arguments other than IN
+func routineSignature(name string, overload routineOverload) string {
+	parts := make([]string, 0, len(overload.arguments))
+	for _, argument := range overload.arguments {
+		var part []string
+		if mode, _ := argument["mode"].(string); mode != "IN" {
+			part = append(part, mode)
+		}
+		if argumentName, _ := argument["name"].(string); argumentName != "" {
+			part = append(part, argumentName)
+		}
+		dataType, _ := argument["type"].(string)
+		parts = append(parts, strings.Join(append(part, dataType), " "))
+	}
+	return name + "(" + strings.Join(parts, ", ") + ")"
+}
+
+// routineOverloadEntry describes an overload in list and get_*_code responses; index is
+// only reported when the routine has several overloads
+func routineOverloadEntry(name string, overload routineOverload, index, count int) map[string]interface{} {
+	entry := map[string]interface{}{
+		"signature": routineSignature(name, overload),
+		"arguments": overload.arguments,
+	}
+	if overload.returnType != "" {
+		entry["return_type"] = overload.returnType
+	}
+	if count > 1 {
+		entry["overload"] = index
+	}
+	return entry
+}
+
+// matchesSignature reports whether a signature given by the caller names an overload.
+// The name and parentheses are optional, and the argument list can be either the full
+// one, as list_functions shows it, or only the argument types.
+func matchesSignature(name string, overload routineOverload, signature string) bool {
+	want := normalizeSignature(name, signature)
+
+	var allTypes, inputTypes []string
+	for _, argument := range overload.arguments {
+		dataType, _ := argument["type"].(string)
+		allTypes = append(allTypes, dataType)
+		if mode, _ := argument["mode"].(string); mode != "OUT" {
+			inputTypes = append(inputTypes, dataType)
+		}
+	}
+	for _, candidate := range []string{
+		routineSignature(name, overload),
+		name + "(" + strings.Join(allTypes, ",") + ")",
+		name + "(" + strings.Join(inputTypes, ",") + ")",
+	} {
+		if normalizeSignature(name, candidate) == want {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeSignature lower-cases a signature, drops whitespace and quotes and replaces
+// the (possibly qualified) routine name with name
+func normalizeSignature(name, signature string) string {
+	signature = strings.TrimSpace(signature)
+	arguments := signature
+	if open := strings.Index(signature, "("); open >= 0 {
+		arguments = strings.TrimSuffix(signature[open+1:], ")")
+	}
+
+	normalized := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '"' || r == '`' || r == '[' || r == ']' {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, arguments)
+	return strings.ToLower(name) + "(" + normalized + ")"
+}
+
+// selectRoutineOverload picks the overload named by a 1-based index or a signature. It
+// returns -1 when the routine is overloaded and neither is given.
+func selectRoutineOverload(name string, overloads []routineOverload, signature string, index int) (int, error) {
+	switch {
+	case index > 0:
+		if index > len(overloads) {
+			return 0, fmt.Errorf("%w: %s has %d overloads", ErrOverloadNotFound, name, len(overloads))
+		}
+		return index - 1, nil
+	case signature != "":
+		for i, overload := range overloads {
+			if matchesSignature(name, overload, signature) {
+				return i, nil
+			}
+		}
+		return 0, fmt.Errorf("%w: %s", ErrOverloadNotFound, signature)
+	case len(overloads) == 1:
+		return 0, nil
+	default:
+		return -1, nil
+	}
+}
+
+// routineCodeResult returns the source code of a function or procedure overload, or the
+// list of overloads when the name is ambiguous. Package members return the body of
+// their package.
+func (s *DbMCPServer) routineCodeResult(ctx context.Context, queries routineQueries, schema, packageName, name, signature string, index int, notFound error) (*mcp.CallToolResult, error) {
+	overloads, err := s.fetchRoutineOverloads(ctx, queries, schema, packageName, name)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrFetchingCode, err).Error()), nil
+	}
+	if len(overloads) == 0 {
+		return mcp.NewToolResultError(notFound.Error()), nil
+	}
+
+	selected, err := selectRoutineOverload(name, overloads, signature, index)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	response := map[string]interface{}{
+		"schema": schema,
+		"name":   name,
+	}
+	if packageName != "" {
+		response["package"] = packageName
+	}
+
+	if selected < 0 {
+		var entries []map[string]interface{}
+		for i, overload := range overloads {
+			entries = append(entries, routineOverloadEntry(name, overload, i+1, len(overloads)))
+		}
+		response["overloads"] = entries
+		response["message"] = fmt.Sprintf("%s has %d overloads: pass a signature or an overload number", name, len(overloads))
+	} else {
+		overload := overloads[selected]
+		var query string
+		var queryArgs []interface{}
+		switch {
+		case packageName != "":
+			query, queryArgs = queries.packageCode(schema, packageName)
+		default:
+			query, queryArgs = queries.overloadCode(schema, name, overload.specificName)
+			if query == "" {
+				query, queryArgs = queries.code(schema, name)
+			}
+		}
+
+		definition, err := s.readSourceCode(ctx, query, queryArgs)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrFetchingCode, err).Error()), nil
+		}
+		if definition == "" {
+			return mcp.NewToolResultError(ErrCodeNotAvailable.Error()), nil
+		}
+
+		for key, value := range routineOverloadEntry(name, overload, selected+1, len(overloads)) {
+			response[key] = value
+		}
+		response["definition"] = definition
+	}
+
+	jsonData, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// routineListEntries describes the rows of a function or procedure listing with the
+// signature of each overload, leaving out the routines hidden by the policy. The
+// arguments are fetched with one query per schema listed.
+func (s *DbMCPServer) routineListEntries(ctx context.Context, queries routineQueries, routines []routineRow) ([]map[string]interface{}, error) {
+	policy := s.policy()
+	overloadsBySchema := make(map[string]map[string][]routineOverload)
+
+	var entries []map[string]interface{}
+	for _, routine := range routines {
+		if !policy.TableAllowed(routine.schema, routine.name) {
+			continue
+		}
+		if routine.packageName.Valid && !policy.TableAllowed(routine.schema, routine.packageName.String) {
+			continue
+		}
+
+		entry := map[string]interface{}{
+			"schema": routine.schema,
+			"name":   routine.name,
+		}
+		if routine.functionType != "" {
+			entry["type"] = routine.functionType
+		}
+		if routine.packageName.Valid {
+			entry["package"] = routine.packageName.String
+		}
+		if routine.created.Valid {
+			entry["created"] = routine.created.Time.Format("2006-01-02 15:04:05")
+		}
+		if routine.lastAltered.Valid {
+			entry["last_altered"] = routine.lastAltered.Time.Format("2006-01-02 15:04:05")
+		}
+
+		schemaOverloads, ok := overloadsBySchema[routine.schema]
+		if !ok {
+			if query, args := queries.listArguments(routine.schema); query != "" {
+				var err error
+				if schemaOverloads, err = s.queryRoutineOverloads(ctx, query, args); err != nil {
+					return nil, err
+				}
+			}
+			overloadsBySchema[routine.schema] = schemaOverloads
+		}
+		overloads := schemaOverloads[routineKey(routine.packageName.String, routine.name)]
+		for i, overload := range overloads {
+			if overload.specificName == routine.specificName.String {
+				for k, v := range routineOverloadEntry(routine.name, overload, i+1, len(overloads)) {
+					entry[k] = v
+				}
+				break
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
@@ -0,0 +1,157 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Tool: Diff Schema
+func (s *DbMCPServer) toolDiffSchema() (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.Tool{
+		Name: "diff_schema",
+		Description: "Compares the schema of two datasources or snapshots: tables (columns, types, nullability, defaults, primary key, indexes, foreign keys), " +
+			"views, procedures and functions. Reports the objects added, removed and changed from 'from' to 'to', with a unified diff of changed sources, " +
+			"and optionally the DDL migrating 'from' to 'to' when both are the same database type. Each side is a declared datasource (default: the active one) or a snapshot",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"from": map[string]interface{}{
+					"type":        "string",
+					"description": "Datasource compared from, such as production (default: the active datasource)",
+				},
+				"from_snapshot": map[string]interface{}{
+					"type":        "string",
+					"description": "Snapshot compared from, instead of a datasource",
+				},
+				"to": map[string]interface{}{
+					"type":        "string",
+					"description": "Datasource compared to, such as staging (default: the active datasource)",
+				},
+				"to_snapshot": map[string]interface{}{
+					"type":        "string",
+					"description": "Snapshot compared to, instead of a datasource",
+				},
+				"schema": map[string]interface{}{
+					"type":        "string",
+					"description": "Schema compared (default: the default schema of each datasource)",
+				},
+				"to_schema": map[string]interface{}{
+					"type":        "string",
+					"description": "Schema of the 'to' side, when it differs from schema",
+				},
+				"include_migration": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Return the DDL migrating 'from' to 'to' (default: false)",
+				},
+			},
+		},
+	}, s.handleDiffSchema
+}
+
+func (s *DbMCPServer) handleDiffSchema(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, ok := getArgs(request.Params.Arguments)
+	if !ok {
+		return mcp.NewToolResultError(ErrInvalidArguments.Error()), nil
+	}
+
+	schema, err := getValidSchema(args, "")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	toSchema := schema
+	if name, _ := getStringArg(args, "to_schema"); name != "" {
+		if !isValidIdentifier(name) {
+			return mcp.NewToolResultError(fmt.Errorf("%w: %s", ErrInvalidIdentifier, name).Error()), nil
+		}
+		toSchema = name
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, DefaultQueryTimeout)
+	defer cancel()
+
+	from, fromLabel, err := s.diffSide(ctx, args, "from", schema)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	to, toLabel, err := s.diffSide(ctx, args, "to", toSchema)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	diff := DiffSchemas(from, to)
+	response := map[string]interface{}{
+		"from":      map[string]interface{}{"source": fromLabel, "driver": from.Driver, "schema": from.Schema},
+		"to":        map[string]interface{}{"source": toLabel, "driver": to.Driver, "schema": to.Schema},
+		"identical": diff.IsEmpty(),
+		"summary": map[string]int{
+			"added":   len(diff.Added),
+			"removed": len(diff.Removed),
+			"changed": len(diff.Changed),
+		},
+		"added":   diff.Added,
+		"removed": diff.Removed,
+		"changed": diff.Changed,
+	}
+
+	if getBoolArg(args, "include_migration", false) {
+		if from.Driver != to.Driver {
+			response["migration_warnings"] = []string{
+				fmt.Sprintf("no migration between %s and %s: both sides must be the same database type", from.Driver, to.Driver),
+			}
+		} else {
+			statements, warnings := MigrationDDL(from, to, diff)
+			response["migration"] = statements
+			if len(warnings) > 0 {
+				response["migration_warnings"] = warnings
+			}
+		}
+	}
+
+	jsonData, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// diffSide loads one side of a comparison, named by the side's datasource or
+// snapshot argument, and returns it with a label for the response
+func (s *DbMCPServer) diffSide(ctx context.Context, args map[string]interface{}, side, schema string) (*SchemaCatalog, string, error) {
+	datasource, _ := getStringArg(args, side)
+	snapshot, _ := getStringArg(args, side+"_snapshot")
+	if datasource != "" && snapshot != "" {
+		return nil, "", fmt.Errorf("%w: give %s or %s_snapshot, not both", ErrInvalidArguments, side, side)
+	}
+
+	if snapshot != "" {
+		catalog, err := s.readSnapshot(snapshot)
+		if err != nil {
+			return nil, "", err
+		}
+		if schema != "" && !strings.EqualFold(schema, catalog.Schema) {
+			return nil, "", fmt.Errorf("%w: snapshot %s holds schema '%s'", ErrInvalidArguments, snapshot, catalog.Schema)
+		}
+		return catalog, "snapshot:" + snapshot, nil
+	}
+
+	reader, closeReader, err := s.catalogReader(ctx, datasource)
+	if err != nil {
+		return nil, "", err
+	}
+	defer closeReader()
+
+	catalog, err := reader.loadCatalog(ctx, schema)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", reader.datasource, err)
+	}
+	if reader.datasource == "" {
+		return catalog, "connection", nil
+	}
+	return catalog, "datasource:" + reader.datasource, nil
+}
@@ -0,0 +1,134 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func (s *DbMCPServer) toolListSequences() (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.Tool{
+		Name:        "list_sequences",
+		Description: "List sequences with their data type, start, bounds, increment, cycle option, cache size and last value (PostgreSQL, Oracle, SQL Server)",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"schema": map[string]interface{}{
+					"type":        "string",
+					"description": "Schema name (optional)",
+				},
+				"name_filter": map[string]interface{}{
+					"type":        "string",
+					"description": "Filter by sequence name (optional)",
+				},
+				"page": map[string]interface{}{
+					"type":        "number",
+					"description": "Page number (default: 1)",
+				},
+				"page_size": map[string]interface{}{
+					"type":        "number",
+					"description": "Items per page (default: 100, maximum: 500)",
+				},
+			},
+		},
+	}, s.handleListSequences
+}
+
+func (s *DbMCPServer) handleListSequences(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	args, ok := getArgs(request.Params.Arguments)
+	if !ok {
+		return mcp.NewToolResultError(ErrInvalidArguments.Error()), nil
+	}
+
+	schema, err := getValidSchema(args, "")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	nameFilter, _ := getStringArg(args, "name_filter")
+	pagination := GetPaginationParams(args, DefaultPageSize, MaxPageSize)
+
+	query, queryArgs := s.queryBuilder.ListSequencesQuery(schema, nameFilter, pagination.PageSize, pagination.Offset)
+	if query == "" {
+		return mcp.NewToolResultError(ErrSequencesNotSupported.Error()), nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ShortQueryTimeout)
+	defer cancel()
+
+	auditStatement(ctx, query, queryArgs)
+	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrListingSequences, err).Error()), nil
+	}
+	defer rows.Close()
+
+	policy := s.policy()
+	var sequences []map[string]interface{}
+	for rows.Next() {
+		var sequenceSchema, sequenceName, isCycle string
+		var dataType, startValue, minValue, maxValue, increment, cacheSize, lastValue sql.NullString
+
+		if err = rows.Scan(&sequenceSchema, &sequenceName, &dataType, &startValue, &minValue, &maxValue,
+			&increment, &isCycle, &cacheSize, &lastValue); err != nil {
+			continue
+		}
+		if !policy.TableAllowed(sequenceSchema, sequenceName) {
+			continue
+		}
+
+		sequences = append(sequences, map[string]interface{}{
+			"schema":      sequenceSchema,
+			"name":        sequenceName,
+			"data_type":   dataType.String,
+			"start_value": numericValue(startValue),
+			"min_value":   numericValue(minValue),
+			"max_value":   numericValue(maxValue),
+			"increment":   numericValue(increment),
+			"cycle":       isCycle == "YES",
+			"cache_size":  numericValue(cacheSize),
+			"last_value":  numericValue(lastValue),
+		})
+	}
+
+	response := map[string]interface{}{
+		"sequences": sequences,
+		"pagination": map[string]interface{}{
+			"page":      pagination.Page,
+			"page_size": pagination.PageSize,
+			"count":     len(sequences),
+		},
+		"filter": map[string]interface{}{
+			"schema":      schema,
+			"name_filter": nameFilter,
+		},
+	}
+
+	jsonData, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// numericValue returns a catalog number read as text as an integer, or as text when
+// it does not fit in one (Oracle NUMBER bounds), and nil when it is NULL
+func numericValue(value sql.NullString) interface{} {
+	if !value.Valid {
+		return nil
+	}
+	if n, err := strconv.ParseInt(value.String, 10, 64); err == nil {
+		return n
+	}
+	return value.String
+}
@@ -0,0 +1,108 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Tool: Snapshot Schema
+func (s *DbMCPServer) toolSnapshotSchema() (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.Tool{
+		Name: "snapshot_schema",
+		Description: "Saves the schema of a datasource (tables, columns, keys, indexes, views, procedures and functions) as a versioned JSON snapshot " +
+			"in the snapshots directory, with a content hash. Snapshots are sorted and normalized so they diff cleanly, and can be compared with diff_schema. " +
+			"An unchanged schema leaves its snapshot as it is",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"datasource": map[string]interface{}{
+					"type":        "string",
+					"description": "Declared datasource to snapshot (default: the active datasource)",
+				},
+				"schema": map[string]interface{}{
+					"type":        "string",
+					"description": "Schema name (default: the default schema of the datasource)",
+				},
+				"name": map[string]interface{}{
+					"type":        "string",
+					"description": "Snapshot name (default: <datasource>_<schema>)",
+				},
+				"timestamp": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Append the UTC time to the name, keeping every snapshot instead of replacing the last one (default: false)",
+				},
+			},
+		},
+	}, s.handleSnapshotSchema
+}
+
+func (s *DbMCPServer) handleSnapshotSchema(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args, ok := getArgs(request.Params.Arguments)
+	if !ok {
+		return mcp.NewToolResultError(ErrInvalidArguments.Error()), nil
+	}
+
+	schema, err := getValidSchema(args, "")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	datasource, _ := getStringArg(args, "datasource")
+	name, _ := getStringArg(args, "name")
+
+	ctx, cancel := context.WithTimeout(ctx, DefaultQueryTimeout)
+	defer cancel()
+
+	info, written, err := s.takeSnapshot(ctx, datasource, schema, name, getBoolArg(args, "timestamp", false))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	response := map[string]interface{}{
+		"snapshot": info,
+		"written":  written,
+	}
+	if !written {
+		response["message"] = "schema unchanged since the snapshot taken at " + info.TakenAt
+	}
+
+	jsonData, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// Tool: List Snapshots
+func (s *DbMCPServer) toolListSnapshots() (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.Tool{
+		Name:        "list_snapshots",
+		Description: "Lists the schema snapshots of the snapshots directory, with their datasource, schema, time, content hash and object counts",
+		InputSchema: mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: map[string]interface{}{},
+		},
+	}, s.handleListSnapshots
+}
+
+func (s *DbMCPServer) handleListSnapshots(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	snapshots, err := s.listSnapshots()
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	response := map[string]interface{}{
+		"snapshots": snapshots,
+		"count":     len(snapshots),
+	}
+
+	jsonData, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
@@ -14,7 +14,7 @@ import (
 func (s *DbMCPServer) toolListTables() (mcp.Tool, server.ToolHandlerFunc) {
 	return mcp.Tool{
 		Name:        "list_tables",
-		Description: "List database tables with pagination",
+		Description: "List database tables with pagination, with their descriptions (comments)",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
 			Properties: map[string]interface{}{
@@ -62,23 +62,33 @@ func (s *DbMCPServer) handleListTables(ctx context.Context, request mcp.CallTool
 	ctx, cancel := context.WithTimeout(ctx, ShortQueryTimeout)
 	defer cancel()
 
+	auditStatement(ctx, query, queryArgs)
 	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrListingTables, err).Error()), nil
 	}
 	defer rows.Close()
 
+	policy := s.policy()
 	var tables []map[string]interface{}
 	for rows.Next() {
 		var tableSchema, tableName, tableType string
-		if err = rows.Scan(&tableSchema, &tableName, &tableType); err != nil {
+		var description sql.NullString
+		if err = rows.Scan(&tableSchema, &tableName, &tableType, &description); err != nil {
 			continue
 		}
-		tables = append(tables, map[string]interface{}{
+		if !policy.TableAllowed(tableSchema, tableName) {
+			continue
+		}
+		table := map[string]interface{}{
 			"schema": tableSchema,
 			"name":   tableName,
 			"type":   tableType,
-		})
+		}
+		if description.String != "" {
+			table["description"] = description.String
+		}
+		tables = append(tables, table)
 	}
 
 	response := map[string]interface{}{
@@ -105,7 +115,7 @@ func (s *DbMCPServer) handleListTables(ctx context.Context, request mcp.CallTool
 func (s *DbMCPServer) toolDescribeTable() (mcp.Tool, server.ToolHandlerFunc) {
 	return mcp.Tool{
 		Name:        "describe_table",
-		Description: "Returns the structure of a table (columns, types, constraints)",
+		Description: "Returns the structure of a table (columns, types, constraints, table and column descriptions)",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
 			Properties: map[string]interface{}{
@@ -149,6 +159,11 @@ func (s *DbMCPServer) handleDescribeTable(ctx context.Context, request mcp.CallT
 	ctx, cancel := context.WithTimeout(ctx, ShortQueryTimeout)
 	defer cancel()
 
+	if !s.objectVisible(ctx, schema, tableName) {
+		return mcp.NewToolResultError(ErrTableNotFound.Error()), nil
+	}
+
+	auditStatement(ctx, query, queryArgs)
 	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrDescribingTable, err).Error()), nil
@@ -163,6 +178,7 @@ func (s *DbMCPServer) handleDescribeTable(ctx context.Context, request mcp.CallT
 	} else {
 		columns = s.parseStandardDescribeTable(rows)
 	}
+	columns = s.visibleColumnMaps(ctx, schema, tableName, columns, "name")
 
 	if len(columns) == 0 {
 		return mcp.NewToolResultError(ErrTableNotFound.Error()), nil
@@ -173,6 +189,9 @@ func (s *DbMCPServer) handleDescribeTable(ctx context.Context, request mcp.CallT
 		"table":   tableName,
 		"columns": columns,
 	}
+	if description := s.tableDescription(ctx, schema, tableName); description != "" {
+		response["description"] = description
+	}
 
 	jsonData, err := json.MarshalIndent(response, "", "  ")
 	if err != nil {
@@ -186,10 +205,10 @@ func (s *DbMCPServer) parseStandardDescribeTable(rows *sql.Rows) []map[string]in
 	var columns []map[string]interface{}
 	for rows.Next() {
 		var colName, dataType, isNullable string
-		var colDefault sql.NullString
+		var colDefault, description sql.NullString
 		var maxLength sql.NullInt64
 
-		if err := rows.Scan(&colName, &dataType, &isNullable, &colDefault, &maxLength); err != nil {
+		if err := rows.Scan(&colName, &dataType, &isNullable, &colDefault, &maxLength, &description); err != nil {
 			continue
 		}
 
@@ -204,6 +223,9 @@ func (s *DbMCPServer) parseStandardDescribeTable(rows *sql.Rows) []map[string]in
 		if colDefault.Valid {
 			col["default"] = colDefault.String
 		}
+		if description.String != "" {
+			col["description"] = description.String
+		}
 		columns = append(columns, col)
 	}
 	return columns
@@ -241,7 +263,7 @@ func (s *DbMCPServer) toolListTableRows() (mcp.Tool, server.ToolHandlerFunc) {
 		Description: "List the rows of a database table with pagination and advanced filters",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
-			Properties: map[string]interface{}{
+			Properties: resultBudgetProperties(map[string]interface{}{
 				"table_name": map[string]interface{}{
 					"type":        "string",
 					"description": "Table name",
@@ -250,28 +272,7 @@ func (s *DbMCPServer) toolListTableRows() (mcp.Tool, server.ToolHandlerFunc) {
 					"type":        "string",
 					"description": "Schema name (optional)",
 				},
-				"filters": map[string]interface{}{
-					"type":        "array",
-					"description": "Filters (e.g.: [{\"column\": \"name\", \"operator\": \"contains\", \"value\": \"john\"}])",
-					"items": map[string]interface{}{
-						"type": "object",
-						"properties": map[string]interface{}{
-							"column": map[string]interface{}{
-								"type":        "string",
-								"description": "Column name",
-							},
-							"operator": map[string]interface{}{
-								"type":        "string",
-								"description": "Operator: eq, neq, gt, gte, lt, lte, contains, starts_with, ends_with, is_null, is_not_null",
-								"enum":        []string{"eq", "neq", "gt", "gte", "lt", "lte", "contains", "starts_with", "ends_with", "is_null", "is_not_null"},
-							},
-							"value": map[string]interface{}{
-								"description": "Value to compare (not required for is_null/is_not_null)",
-							},
-						},
-						"required": []string{"column", "operator"},
-					},
-				},
+				"filters": filterInputSchema(),
 				"page": map[string]interface{}{
 					"type":        "number",
 					"description": "Page number (default: 1)",
@@ -280,15 +281,33 @@ func (s *DbMCPServer) toolListTableRows() (mcp.Tool, server.ToolHandlerFunc) {
 					"type":        "number",
 					"description": "Items per page (default: 50, maximum: 1000)",
 				},
+				"offset": map[string]interface{}{
+					"type":        "number",
+					"description": "Rows to skip, instead of 'page' (optional). Use the 'next_offset' of a page cut short by the size budget",
+				},
+				"columns": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "Columns to return (optional, default: all columns)",
+				},
+				"exclude_columns": map[string]interface{}{
+					"type":        "array",
+					"items":       map[string]interface{}{"type": "string"},
+					"description": "Columns to leave out of the result (optional)",
+				},
+				"skip_lob_columns": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Skip large object columns (BLOB, CLOB, TEXT, ...) unless listed in 'columns' (default: false)",
+				},
 				"order_by": map[string]interface{}{
-					"type":        "string",
-					"description": "Column for sorting (optional)",
+					"description": "Sorting (optional). A column name, or an array of column names or " +
+						"{\"column\": \"name\", \"direction\": \"asc|desc\", \"nulls\": \"first|last\"} objects",
 				},
 				"order_direction": map[string]interface{}{
 					"type":        "string",
-					"description": "Sorting direction: ASC or DESC (default: ASC)",
+					"description": "Default sorting direction: ASC or DESC (default: ASC)",
 				},
-			},
+			}),
 			Required: []string{"table_name"},
 		},
 	}, s.handleListTableRows
@@ -334,37 +353,29 @@ func (s *DbMCPServer) handleListTableRows(ctx context.Context, request mcp.CallT
 		return mcp.NewToolResultError(ErrNoColumnsFound.Error()), nil
 	}
 
-	// Pagination
+	// Pagination, from an explicit offset when given
 	pagination := GetPaginationParams(args, 50, MaxRowsPageSize)
-
-	// Sorting
-	orderBy, _ := getStringArg(args, "order_by")
-	orderDirection := "ASC"
-	if dir, ok := getStringArg(args, "order_direction"); ok {
-		dir = strings.ToUpper(dir)
-		if dir == "DESC" || dir == "ASC" {
-			orderDirection = dir
-		}
+	if offset, ok := args["offset"].(float64); ok && offset >= 0 {
+		pagination.Offset = int(offset)
+		pagination.Page = pagination.Offset/pagination.PageSize + 1
 	}
 
-	// Validate orderBy column
-	if orderBy != "" {
-		if !s.columnExists(columns, orderBy) {
-			return mcp.NewToolResultError(fmt.Errorf("%w: %s", ErrColumnNotExists, orderBy).Error()), nil
-		}
-	} else if len(columns) > 0 {
-		orderBy = columns[0]
+	// Projection
+	selected, skipped, err := s.selectColumns(args, columns)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	// Build WHERE clause from filters
-	whereClauses, queryParams, err := s.buildWhereClause(args, columns)
+	// Sorting
+	orderBy, err := s.parseOrderBy(args, columns)
 	if err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
-	whereClause := ""
-	if len(whereClauses) > 0 {
-		whereClause = "WHERE " + strings.Join(whereClauses, " AND ")
+	// Build WHERE clause from filters
+	whereClause, queryParams, err := s.buildWhereClause(args, columns)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
 	}
 
 	// Count total rows
@@ -374,7 +385,9 @@ func (s *DbMCPServer) handleListTableRows(ctx context.Context, request mcp.CallT
 	}
 
 	// Fetch rows
-	rows, err := s.fetchRows(ctx, schema, tableName, columns, whereClause, orderBy, orderDirection, pagination, queryParams)
+	budget := newResultBudget(args)
+	mask := s.rowMask(ctx, selected, tableMaskSources(schema, tableName, selected))
+	rows, err := s.fetchRows(ctx, schema, tableName, selected, whereClause, orderBy, pagination, queryParams, mask, budget)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrFetchingRows, err).Error()), nil
 	}
@@ -384,24 +397,54 @@ func (s *DbMCPServer) handleListTableRows(ctx context.Context, request mcp.CallT
 		totalPages = 0
 	}
 
+	var orderInfo []map[string]interface{}
+	for _, ob := range orderBy {
+		direction := "ASC"
+		if ob.Descending {
+			direction = "DESC"
+		}
+		term := map[string]interface{}{
+			"column":    ob.Column,
+			"direction": direction,
+		}
+		if ob.Nulls != "" {
+			term["nulls"] = ob.Nulls
+		}
+		orderInfo = append(orderInfo, term)
+	}
+
+	// The rows following the returned ones, which a page cut short by the budget leaves out
+	nextOffset := pagination.Offset + len(rows)
+	paginationInfo := map[string]interface{}{
+		"page":         pagination.Page,
+		"page_size":    pagination.PageSize,
+		"offset":       pagination.Offset,
+		"total_count":  totalCount,
+		"total_pages":  totalPages,
+		"has_next":     nextOffset < totalCount,
+		"has_previous": pagination.Offset > 0,
+	}
+	if nextOffset < totalCount {
+		paginationInfo["next_offset"] = nextOffset
+	}
+
 	response := map[string]interface{}{
-		"rows":    rows,
-		"columns": columns,
-		"pagination": map[string]interface{}{
-			"page":         pagination.Page,
-			"page_size":    pagination.PageSize,
-			"total_count":  totalCount,
-			"total_pages":  totalPages,
-			"has_next":     pagination.Page < totalPages,
-			"has_previous": pagination.Page > 1,
-		},
+		"rows":       rows,
+		"columns":    selected,
+		"pagination": paginationInfo,
 		"table": map[string]interface{}{
-			"schema":          schema,
-			"name":            tableName,
-			"order_by":        orderBy,
-			"order_direction": orderDirection,
+			"schema":   schema,
+			"name":     tableName,
+			"order_by": orderInfo,
 		},
 	}
+	if len(skipped) > 0 {
+		response["skipped_lob_columns"] = skipped
+	}
+	budget.Describe(response)
+	if masked := mask.Columns(); len(masked) > 0 {
+		response["masked_columns"] = masked
+	}
 
 	jsonData, err := json.MarshalIndent(response, "", "  ")
 	if err != nil {
@@ -412,13 +455,42 @@ func (s *DbMCPServer) handleListTableRows(ctx context.Context, request mcp.CallT
 }
 
 func (s *DbMCPServer) tableExists(ctx context.Context, schema, tableName string) (bool, error) {
+	if !s.objectVisible(ctx, schema, tableName) {
+		return false, nil
+	}
 	query, args := s.queryBuilder.TableExistsQuery(schema, tableName)
 	var count int
 	err := s.db.QueryRowContext(ctx, query, args...).Scan(&count)
 	return count > 0, err
 }
 
-func (s *DbMCPServer) getTableColumns(ctx context.Context, schema, tableName string) ([]string, error) {
+// getTableColumns returns the columns of a table visible under the policy,
+// none when the table itself is hidden
+func (s *DbMCPServer) getTableColumns(ctx context.Context, schema, tableName string) ([]TableColumn, error) {
+	columns, err := s.fetchTableColumns(ctx, schema, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, policySchema, ok := s.policyScope(ctx, schema)
+	if policy.IsEmpty() {
+		return columns, nil
+	}
+	if !ok || !policy.TableAllowed(policySchema, tableName) {
+		return nil, nil
+	}
+
+	var visible []TableColumn
+	for _, col := range columns {
+		if policy.ColumnAllowed(policySchema, tableName, col.Name) {
+			visible = append(visible, col)
+		}
+	}
+	return visible, nil
+}
+
+// fetchTableColumns returns all the columns of a table
+func (s *DbMCPServer) fetchTableColumns(ctx context.Context, schema, tableName string) ([]TableColumn, error) {
 	query, args := s.queryBuilder.GetTableColumnsQuery(schema, tableName)
 	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -426,7 +498,7 @@ func (s *DbMCPServer) getTableColumns(ctx context.Context, schema, tableName str
 	}
 	defer rows.Close()
 
-	var columns []string
+	var columns []TableColumn
 	if s.queryBuilder.IsSQLite() {
 		for rows.Next() {
 			var cid int
@@ -436,7 +508,7 @@ func (s *DbMCPServer) getTableColumns(ctx context.Context, schema, tableName str
 			if err := rows.Scan(&cid, &name, &dataType, &notNull, &dfltValue, &pk); err != nil {
 				continue
 			}
-			columns = append(columns, name)
+			columns = append(columns, TableColumn{Name: name, DataType: dataType})
 		}
 	} else {
 		for rows.Next() {
@@ -447,135 +519,188 @@ func (s *DbMCPServer) getTableColumns(ctx context.Context, schema, tableName str
 			if err := rows.Scan(&columnName, &dataType, &maxLength, &isNullable, &colDefault); err != nil {
 				continue
 			}
-			columns = append(columns, columnName)
+			columns = append(columns, TableColumn{Name: columnName, DataType: dataType, MaxLength: maxLength.Int64})
 		}
 	}
-	return columns, nil
+	return columns, rows.Err()
 }
 
-func (s *DbMCPServer) columnExists(columns []string, name string) bool {
+// resolveColumn returns the column name as stored in the table, matching case-insensitively
+func (s *DbMCPServer) resolveColumn(columns []TableColumn, name string) (string, bool) {
 	for _, col := range columns {
-		if strings.EqualFold(col, name) {
-			return true
+		if strings.EqualFold(col.Name, name) {
+			return col.Name, true
 		}
 	}
-	return false
+	return "", false
 }
 
-func (s *DbMCPServer) buildWhereClause(args map[string]interface{}, columns []string) ([]string, []interface{}, error) {
-	var whereClauses []string
-	var queryParams []interface{}
-	paramIndex := 1
-
-	filters, ok := args["filters"].([]interface{})
-	if !ok {
-		return whereClauses, queryParams, nil
+// selectColumns applies the "columns", "exclude_columns" and "skip_lob_columns" arguments.
+// Returns the selected columns in table order and the LOB columns that were skipped.
+func (s *DbMCPServer) selectColumns(args map[string]interface{}, columns []TableColumn) ([]string, []string, error) {
+	included := make(map[string]bool)
+	requested, _ := getStringSliceArg(args, "columns")
+	for _, name := range requested {
+		col, ok := s.resolveColumn(columns, name)
+		if !ok {
+			return nil, nil, fmt.Errorf("%w: %s", ErrColumnNotExists, name)
+		}
+		included[col] = true
 	}
 
-	for _, filterInterface := range filters {
-		filter, ok := filterInterface.(map[string]interface{})
+	excluded := make(map[string]bool)
+	excludeList, _ := getStringSliceArg(args, "exclude_columns")
+	for _, name := range excludeList {
+		col, ok := s.resolveColumn(columns, name)
 		if !ok {
-			continue
+			return nil, nil, fmt.Errorf("%w: %s", ErrColumnNotExists, name)
 		}
+		excluded[col] = true
+	}
 
-		column, _ := filter["column"].(string)
-		operator, _ := filter["operator"].(string)
+	skipLOB := getBoolArg(args, "skip_lob_columns", false)
 
-		if column == "" || operator == "" {
+	var selected, skipped []string
+	for _, col := range columns {
+		if len(requested) > 0 && !included[col.Name] {
+			continue
+		}
+		if excluded[col.Name] {
 			continue
 		}
+		if skipLOB && !included[col.Name] && s.queryBuilder.IsLargeObjectType(col.DataType, col.MaxLength) {
+			skipped = append(skipped, col.Name)
+			continue
+		}
+		selected = append(selected, col.Name)
+	}
 
-		// Validate column exists
-		if !s.columnExists(columns, column) {
-			return nil, nil, fmt.Errorf("%w: %s", ErrColumnNotExists, column)
-		}
-
-		// Quote column name based on driver
-		quotedColumn := s.queryBuilder.QuoteIdentifier(column)
-		placeholder := s.queryBuilder.Placeholder(paramIndex)
-		likeOp := s.queryBuilder.LikeOperator(false)
-
-		switch operator {
-		case "eq":
-			whereClauses = append(whereClauses, fmt.Sprintf("%s = %s", quotedColumn, placeholder))
-			queryParams = append(queryParams, filter["value"])
-			paramIndex++
-		case "neq":
-			whereClauses = append(whereClauses, fmt.Sprintf("%s != %s", quotedColumn, placeholder))
-			queryParams = append(queryParams, filter["value"])
-			paramIndex++
-		case "gt":
-			whereClauses = append(whereClauses, fmt.Sprintf("%s > %s", quotedColumn, placeholder))
-			queryParams = append(queryParams, filter["value"])
-			paramIndex++
-		case "gte":
-			whereClauses = append(whereClauses, fmt.Sprintf("%s >= %s", quotedColumn, placeholder))
-			queryParams = append(queryParams, filter["value"])
-			paramIndex++
-		case "lt":
-			whereClauses = append(whereClauses, fmt.Sprintf("%s < %s", quotedColumn, placeholder))
-			queryParams = append(queryParams, filter["value"])
-			paramIndex++
-		case "lte":
-			whereClauses = append(whereClauses, fmt.Sprintf("%s <= %s", quotedColumn, placeholder))
-			queryParams = append(queryParams, filter["value"])
-			paramIndex++
-		case "contains":
-			value, ok := filter["value"].(string)
-			if !ok {
-				return nil, nil, ErrContainsRequiresString
-			}
-			whereClauses = append(whereClauses, fmt.Sprintf("%s %s %s", quotedColumn, likeOp, placeholder))
-			queryParams = append(queryParams, "%"+value+"%")
-			paramIndex++
-		case "starts_with":
-			value, ok := filter["value"].(string)
-			if !ok {
-				return nil, nil, ErrStartsWithRequiresString
+	if len(selected) == 0 {
+		return nil, nil, ErrNoColumnsSelected
+	}
+	return selected, skipped, nil
+}
+
+// parseOrderBy parses the "order_by" argument (a column name or an array of
+// column names / {column, direction, nulls} objects). Defaults to the first column.
+func (s *DbMCPServer) parseOrderBy(args map[string]interface{}, columns []TableColumn) ([]OrderByColumn, error) {
+	defaultDescending := false
+	if dir, ok := getStringArg(args, "order_direction"); ok {
+		defaultDescending = strings.EqualFold(dir, "DESC")
+	}
+
+	var items []interface{}
+	switch v := args["order_by"].(type) {
+	case nil:
+	case string:
+		if v != "" {
+			items = []interface{}{v}
+		}
+	case []interface{}:
+		items = v
+	case map[string]interface{}:
+		items = []interface{}{v}
+	default:
+		return nil, fmt.Errorf("%w: expected a column name or an array", ErrInvalidOrderBy)
+	}
+
+	var orderBy []OrderByColumn
+	for _, item := range items {
+		term := OrderByColumn{Descending: defaultDescending}
+		var name string
+
+		switch v := item.(type) {
+		case string:
+			name = v
+		case map[string]interface{}:
+			name, _ = v["column"].(string)
+			if dir, ok := v["direction"].(string); ok {
+				switch strings.ToUpper(dir) {
+				case "ASC":
+					term.Descending = false
+				case "DESC":
+					term.Descending = true
+				default:
+					return nil, fmt.Errorf("%w: direction must be asc or desc", ErrInvalidOrderBy)
+				}
 			}
-			whereClauses = append(whereClauses, fmt.Sprintf("%s %s %s", quotedColumn, likeOp, placeholder))
-			queryParams = append(queryParams, value+"%")
-			paramIndex++
-		case "ends_with":
-			value, ok := filter["value"].(string)
-			if !ok {
-				return nil, nil, ErrEndsWithRequiresString
+			if nulls, ok := v["nulls"].(string); ok {
+				term.Nulls = strings.ToLower(nulls)
+				if term.Nulls != "first" && term.Nulls != "last" {
+					return nil, fmt.Errorf("%w: nulls must be first or last", ErrInvalidOrderBy)
+				}
 			}
-			whereClauses = append(whereClauses, fmt.Sprintf("%s %s %s", quotedColumn, likeOp, placeholder))
-			queryParams = append(queryParams, "%"+value)
-			paramIndex++
-		case "is_null":
-			whereClauses = append(whereClauses, fmt.Sprintf("%s IS NULL", quotedColumn))
-		case "is_not_null":
-			whereClauses = append(whereClauses, fmt.Sprintf("%s IS NOT NULL", quotedColumn))
 		default:
-			return nil, nil, fmt.Errorf("%w: %s", ErrInvalidOperator, operator)
+			return nil, fmt.Errorf("%w: expected a column name or an object", ErrInvalidOrderBy)
+		}
+
+		col, ok := s.resolveColumn(columns, name)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrColumnNotExists, name)
+		}
+		term.Column = col
+		orderBy = append(orderBy, term)
+	}
+
+	if len(orderBy) == 0 && len(columns) > 0 {
+		orderBy = append(orderBy, OrderByColumn{Column: columns[0].Name, Descending: defaultDescending})
+	}
+	return orderBy, nil
+}
+
+// tableColumnResolver returns a ColumnResolver that only accepts columns of the table
+func (s *DbMCPServer) tableColumnResolver(columns []TableColumn) ColumnResolver {
+	return func(name string) (string, error) {
+		col, ok := s.resolveColumn(columns, name)
+		if !ok {
+			return "", fmt.Errorf("%w: %s", ErrColumnNotExists, name)
 		}
+		return s.queryBuilder.QuoteIdentifier(col), nil
 	}
+}
 
-	return whereClauses, queryParams, nil
+// buildWhereClause builds the WHERE clause from the "filters" argument
+func (s *DbMCPServer) buildWhereClause(args map[string]interface{}, columns []TableColumn) (string, []interface{}, error) {
+	filter, err := ParseFilter(args["filters"])
+	if err != nil {
+		return "", nil, err
+	}
+
+	fb := NewFilterBuilder(s.queryBuilder, s.tableColumnResolver(columns), 1)
+	expr, err := fb.Build(filter)
+	if err != nil {
+		return "", nil, err
+	}
+	if expr == "" {
+		return "", nil, nil
+	}
+
+	return "WHERE " + expr, fb.Params(), nil
 }
 
 func (s *DbMCPServer) countRows(ctx context.Context, schema, tableName, whereClause string, params []interface{}) (int, error) {
 	query := s.queryBuilder.BuildCountQuery(schema, tableName, whereClause)
 
+	auditStatement(ctx, query, params)
 	var count int
 	err := s.db.QueryRowContext(ctx, query, params...).Scan(&count)
 	return count, err
 }
 
-func (s *DbMCPServer) fetchRows(ctx context.Context, schema, tableName string, columns []string, whereClause, orderBy, orderDirection string, pagination PaginationParams, params []interface{}) ([]map[string]interface{}, error) {
+// fetchRows reads a page of rows, masking each one before it is added to the budget,
+// and stops early once the budget is exhausted
+func (s *DbMCPServer) fetchRows(ctx context.Context, schema, tableName string, columns []string, whereClause string, orderBy []OrderByColumn, pagination PaginationParams, params []interface{}, mask *RowMask, budget *ResultBudget) ([]map[string]interface{}, error) {
 	query := s.queryBuilder.BuildSelectQuery(SelectQueryParams{
-		Schema:         schema,
-		Table:          tableName,
-		Columns:        columns,
-		WhereClause:    whereClause,
-		OrderBy:        orderBy,
-		OrderDirection: orderDirection,
-		Limit:          pagination.PageSize,
-		Offset:         pagination.Offset,
+		Schema:      schema,
+		Table:       tableName,
+		Columns:     columns,
+		WhereClause: whereClause,
+		OrderBy:     orderBy,
+		Limit:       pagination.PageSize,
+		Offset:      pagination.Offset,
 	})
 
+	auditStatement(ctx, query, params)
 	dbRows, err := s.db.QueryContext(ctx, query, params...)
 	if err != nil {
 		return nil, err
@@ -598,9 +723,14 @@ func (s *DbMCPServer) fetchRows(ctx context.Context, schema, tableName string, c
 		for i, col := range columns {
 			row[col] = formatValue(values[i])
 		}
+		mask.Mask(row)
+		if !budget.Add(columns, row, len(rows)) {
+			break
+		}
 		rows = append(rows, row)
 	}
 
+	auditRowCount(ctx, len(rows))
 	return rows, nil
 }
 
@@ -649,36 +779,61 @@ func (s *DbMCPServer) handleGetTableSchemaFull(ctx context.Context, request mcp.
 	ctx, cancel := context.WithTimeout(ctx, DefaultQueryTimeout)
 	defer cancel()
 
+	if !s.objectVisible(ctx, schema, tableName) {
+		return mcp.NewToolResultError(fmt.Errorf("%w: %s.%s", ErrTableNotFound, schema, tableName).Error()), nil
+	}
+
 	// Get columns
 	columnsQuery, columnsArgs := s.queryBuilder.GetTableSchemaFullQuery(schema, tableName)
 	columns, err := s.fetchSchemaColumns(ctx, columnsQuery, columnsArgs)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrRetrievingColumns, err).Error()), nil
 	}
+	allColumns := columns
+	columns = s.visibleColumnMaps(ctx, schema, tableName, columns, "name")
 
 	if len(columns) == 0 {
 		return mcp.NewToolResultError(fmt.Errorf("%w: %s.%s", ErrTableNotFound, schema, tableName).Error()), nil
 	}
 
-	// Get indexes
-	indexesQuery, indexesArgs := s.queryBuilder.GetIndexesQuery(schema, tableName)
-	indexes, _ := s.fetchIndexes(ctx, indexesQuery, indexesArgs)
-
-	// Get foreign keys
-	fkQuery, fkArgs := s.queryBuilder.GetForeignKeysQuery(schema, tableName)
-	foreignKeys, _ := s.fetchForeignKeys(ctx, fkQuery, fkArgs)
+	// Get indexes and foreign keys, one entry each with their columns in key order
+	indexes, _ := s.getIndexes(ctx, schema, tableName)
+	foreignKeys, _ := s.getForeignKeys(ctx, schema, tableName)
 
 	// Get primary key
 	pkQuery, pkArgs := s.queryBuilder.GetPrimaryKeyQuery(schema, tableName)
 	primaryKey, _ := s.fetchPrimaryKey(ctx, pkQuery, pkArgs)
 
+	// Leave out what would reveal hidden columns or tables
+	indexes = s.visibleIndexes(ctx, schema, tableName, indexes)
+	foreignKeys = s.visibleForeignKeys(ctx, schema, tableName, foreignKeys)
+	primaryKey = s.visibleColumnNames(ctx, schema, tableName, primaryKey)
+
+	// Get check, unique, default and exclusion constraints, leaving out those that
+	// name a hidden column
+	checks, uniques, defaults, exclusions, err := s.getTableConstraints(ctx, schema, tableName, hiddenColumns(allColumns, columns))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrRetrievingConstraints, err).Error()), nil
+	}
+
 	response := map[string]interface{}{
-		"schema":       schema,
-		"table":        tableName,
-		"columns":      columns,
-		"primary_key":  primaryKey,
-		"indexes":      indexes,
-		"foreign_keys": foreignKeys,
+		"schema":             schema,
+		"table":              tableName,
+		"columns":            columns,
+		"primary_key":        primaryKey,
+		"indexes":            indexes,
+		"foreign_keys":       foreignKeys,
+		"check_constraints":  checks,
+		"unique_constraints": uniques,
+	}
+	if description := s.tableDescription(ctx, schema, tableName); description != "" {
+		response["description"] = description
+	}
+	if len(defaults) > 0 {
+		response["default_constraints"] = defaults
+	}
+	if len(exclusions) > 0 {
+		response["exclusion_constraints"] = exclusions
 	}
 
 	jsonData, err := json.MarshalIndent(response, "", "  ")
@@ -689,6 +844,21 @@ func (s *DbMCPServer) handleGetTableSchemaFull(ctx context.Context, request mcp.
 	return mcp.NewToolResultText(string(jsonData)), nil
 }
 
+// tableDescription returns the comment of a table, or "" when it has none or the
+// database keeps no table comments
+func (s *DbMCPServer) tableDescription(ctx context.Context, schema, tableName string) string {
+	query, args := s.queryBuilder.GetTableCommentQuery(schema, tableName)
+	if query == "" {
+		return ""
+	}
+	var description sql.NullString
+	auditStatement(ctx, query, args)
+	if err := s.db.QueryRowContext(ctx, query, args...).Scan(&description); err != nil {
+		return ""
+	}
+	return description.String
+}
+
 func (s *DbMCPServer) fetchSchemaColumns(ctx context.Context, query string, args []interface{}) ([]map[string]interface{}, error) {
 	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -725,9 +895,9 @@ func (s *DbMCPServer) fetchSchemaColumns(ctx context.Context, query string, args
 			var columnName, dataType string
 			var maxLength, precision, scale sql.NullInt64
 			var isNullable, isPrimaryKey string
-			var defaultValue sql.NullString
+			var defaultValue, description sql.NullString
 
-			if err := rows.Scan(&columnName, &dataType, &maxLength, &precision, &scale, &isNullable, &defaultValue, &isPrimaryKey); err != nil {
+			if err := rows.Scan(&columnName, &dataType, &maxLength, &precision, &scale, &isNullable, &defaultValue, &isPrimaryKey, &description); err != nil {
 				continue
 			}
 
@@ -749,6 +919,9 @@ func (s *DbMCPServer) fetchSchemaColumns(ctx context.Context, query string, args
 			if defaultValue.Valid {
 				col["default_value"] = defaultValue.String
 			}
+			if description.String != "" {
+				col["description"] = description.String
+			}
 			columns = append(columns, col)
 		}
 	}
@@ -756,44 +929,49 @@ func (s *DbMCPServer) fetchSchemaColumns(ctx context.Context, query string, args
 	return columns, nil
 }
 
-func (s *DbMCPServer) fetchIndexes(ctx context.Context, query string, args []interface{}) ([]map[string]interface{}, error) {
-	if s.queryBuilder.IsSQLite() {
-		return s.fetchSQLiteIndexes(ctx, query)
-	}
-
+// fetchIndexes returns the indexes of a table as one entry per index, grouping
+// the rows of the index query, which come one per key column in key order
+func (s *DbMCPServer) fetchIndexes(ctx context.Context, query string, args []interface{}) ([]TableIndex, error) {
 	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var indexes []map[string]interface{}
+	var indexes []TableIndex
+	position := make(map[string]int)
 	for rows.Next() {
-		var indexName, indexType, columnName string
+		var indexName, indexType string
+		var columnName sql.NullString
 		var isUnique bool
 
 		if err := rows.Scan(&indexName, &indexType, &isUnique, &columnName); err != nil {
 			continue
 		}
 
-		indexes = append(indexes, map[string]interface{}{
-			"name":      indexName,
-			"type":      indexType,
-			"is_unique": isUnique,
-			"column":    columnName,
-		})
+		i, ok := position[indexName]
+		if !ok {
+			i = len(indexes)
+			position[indexName] = i
+			indexes = append(indexes, TableIndex{Name: indexName, Type: indexType, Unique: isUnique})
+		}
+		if columnName.Valid {
+			indexes[i].Columns = append(indexes[i].Columns, columnName.String)
+		}
 	}
-	return indexes, nil
+	return indexes, rows.Err()
 }
 
-func (s *DbMCPServer) fetchSQLiteIndexes(ctx context.Context, tableName string) ([]map[string]interface{}, error) {
-	rows, err := s.db.QueryContext(ctx, fmt.Sprintf("PRAGMA index_list(%s)", tableName))
+// fetchSQLiteIndexes returns the indexes of a SQLite table with their key columns
+func (s *DbMCPServer) fetchSQLiteIndexes(ctx context.Context, tableName string) ([]TableIndex, error) {
+	query, _ := s.queryBuilder.GetIndexesQuery("", tableName)
+	rows, err := s.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var indexes []map[string]interface{}
+	var indexes []TableIndex
 	for rows.Next() {
 		var seq int
 		var name, unique, origin, partial string
@@ -801,59 +979,108 @@ func (s *DbMCPServer) fetchSQLiteIndexes(ctx context.Context, tableName string)
 		if err := rows.Scan(&seq, &name, &unique, &origin, &partial); err != nil {
 			continue
 		}
+		indexes = append(indexes, TableIndex{Name: name, Unique: unique == "1"})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
 
-		indexes = append(indexes, map[string]interface{}{
-			"name":      name,
-			"is_unique": unique == "1",
-			"origin":    origin,
-		})
+	for i := range indexes {
+		rows, err := s.db.QueryContext(ctx, fmt.Sprintf("PRAGMA index_info(%s)", s.queryBuilder.QuoteIdentifier(indexes[i].Name)))
+		if err != nil {
+			return nil, err
+		}
+		for rows.Next() {
+			var seqno, cid int
+			var column sql.NullString
+			if err := rows.Scan(&seqno, &cid, &column); err == nil && column.Valid {
+				indexes[i].Columns = append(indexes[i].Columns, column.String)
+			}
+		}
+		rows.Close()
 	}
 	return indexes, nil
 }
 
-func (s *DbMCPServer) fetchForeignKeys(ctx context.Context, query string, args []interface{}) ([]map[string]interface{}, error) {
+// getForeignKeys returns the foreign keys of a table as one entry per constraint, with
+// the columns of composite keys paired with the referenced columns in key order
+func (s *DbMCPServer) getForeignKeys(ctx context.Context, schema, tableName string) ([]ForeignKey, error) {
+	query, args := s.queryBuilder.GetForeignKeysQuery(schema, tableName)
 	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var foreignKeys []map[string]interface{}
+	var foreignKeys []ForeignKey
+	position := make(map[string]int)
+	add := func(fk ForeignKey, column, refColumn string) {
+		i, ok := position[fk.Name]
+		if !ok {
+			i = len(foreignKeys)
+			position[fk.Name] = i
+			fk.Schema = schema
+			fk.Table = tableName
+			foreignKeys = append(foreignKeys, fk)
+		}
+		foreignKeys[i].Columns = append(foreignKeys[i].Columns, column)
+		foreignKeys[i].RefColumns = append(foreignKeys[i].RefColumns, refColumn)
+	}
 
 	if s.queryBuilder.IsSQLite() {
+		// A foreign key declared without referenced columns references the primary key
+		var implicit []string
 		for rows.Next() {
 			var id, seq int
-			var table, from, to, onUpdate, onDelete, match string
+			var table, from, onUpdate, onDelete, match string
+			var to sql.NullString
 
 			if err := rows.Scan(&id, &seq, &table, &from, &to, &onUpdate, &onDelete, &match); err != nil {
 				continue
 			}
-
-			foreignKeys = append(foreignKeys, map[string]interface{}{
-				"column":            from,
-				"referenced_table":  table,
-				"referenced_column": to,
-			})
+			name := fmt.Sprintf("fk_%s_%d", tableName, id)
+			add(ForeignKey{Name: name, RefTable: table, OnDelete: onDelete, OnUpdate: onUpdate, Match: match}, from, to.String)
+			if !to.Valid {
+				implicit = append(implicit, name)
+			}
 		}
-	} else {
-		for rows.Next() {
-			var constraintName, columnName, refSchema, refTable, refColumn string
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		rows.Close()
 
-			if err := rows.Scan(&constraintName, &columnName, &refSchema, &refTable, &refColumn); err != nil {
-				continue
+		for _, name := range implicit {
+			fk := &foreignKeys[position[name]]
+			pkQuery, pkArgs := s.queryBuilder.GetPrimaryKeyQuery("", fk.RefTable)
+			if primaryKey, err := s.fetchPrimaryKey(ctx, pkQuery, pkArgs); err == nil && len(primaryKey) == len(fk.Columns) {
+				fk.RefColumns = primaryKey
 			}
+		}
+		return foreignKeys, nil
+	}
 
-			foreignKeys = append(foreignKeys, map[string]interface{}{
-				"name":              constraintName,
-				"column":            columnName,
-				"referenced_schema": refSchema,
-				"referenced_table":  refTable,
-				"referenced_column": refColumn,
-			})
+	for rows.Next() {
+		var constraintName, columnName, refSchema, refTable, refColumn string
+		var onDelete, onUpdate, match, deferrable, deferred sql.NullString
+
+		if err := rows.Scan(&constraintName, &columnName, &refSchema, &refTable, &refColumn,
+			&onDelete, &onUpdate, &match, &deferrable, &deferred); err != nil {
+			continue
 		}
+		add(ForeignKey{
+			Name:              constraintName,
+			RefSchema:         refSchema,
+			RefTable:          refTable,
+			OnDelete:          onDelete.String,
+			OnUpdate:          onUpdate.String,
+			Match:             match.String,
+			Deferrable:        strings.EqualFold(deferrable.String, "YES"),
+			InitiallyDeferred: strings.EqualFold(deferred.String, "YES"),
+		}, columnName, refColumn)
 	}
 
-	return foreignKeys, nil
+	return foreignKeys, rows.Err()
 }
 
 func (s *DbMCPServer) fetchPrimaryKey(ctx context.Context, query string, args []interface{}) ([]string, error) {
@@ -891,3 +1118,213 @@ func (s *DbMCPServer) fetchPrimaryKey(ctx context.Context, query string, args []
 
 	return pkColumns, nil
 }
+
+// listSchemaTables returns the names of the tables of a schema allowed by the policy
+func (s *DbMCPServer) listSchemaTables(ctx context.Context, schema string) ([]string, error) {
+	query, queryArgs := s.queryBuilder.ListTablesQuery(schema, "", MaxSchemaObjects, 0)
+
+	auditStatement(ctx, query, queryArgs)
+	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	policy := s.policy()
+	var tables []string
+	for rows.Next() {
+		var tableSchema, tableName, tableType string
+		var description sql.NullString
+		if err = rows.Scan(&tableSchema, &tableName, &tableType, &description); err != nil {
+			return nil, err
+		}
+		if policy.TableAllowed(tableSchema, tableName) {
+			tables = append(tables, tableName)
+		}
+	}
+	return tables, rows.Err()
+}
+
+// getIndexes returns the indexes of a table, grouping the columns of composite indexes
+func (s *DbMCPServer) getIndexes(ctx context.Context, schema, tableName string) ([]TableIndex, error) {
+	if s.queryBuilder.IsSQLite() {
+		return s.fetchSQLiteIndexes(ctx, tableName)
+	}
+
+	query, args := s.queryBuilder.GetIndexesQuery(schema, tableName)
+	return s.fetchIndexes(ctx, query, args)
+}
+
+// getUniqueKeys returns the column sets of the unique indexes and constraints of a table
+func (s *DbMCPServer) getUniqueKeys(ctx context.Context, schema, tableName string) ([][]string, error) {
+	indexes, err := s.getIndexes(ctx, schema, tableName)
+	if err != nil {
+		return nil, err
+	}
+	var keys [][]string
+	for _, index := range indexes {
+		if index.Unique {
+			keys = append(keys, index.Columns)
+		}
+	}
+	return keys, nil
+}
+
+// getTableConstraints returns the check, unique, named default and exclusion
+// constraints of a table, without those naming one of the hidden columns. A
+// constraint kind the database has no query for is returned empty.
+func (s *DbMCPServer) getTableConstraints(ctx context.Context, schema, tableName string, hidden []string) (
+	[]CheckConstraint, []UniqueConstraint, []DefaultConstraint, []ExclusionConstraint, error) {
+	checks := []CheckConstraint{}
+	uniques := []UniqueConstraint{}
+	var defaults []DefaultConstraint
+	var exclusions []ExclusionConstraint
+
+	if s.queryBuilder.IsSQLite() {
+		query, args := s.queryBuilder.GetCheckConstraintsQuery(schema, tableName)
+		var createSQL sql.NullString
+		err := s.db.QueryRowContext(ctx, query, args...).Scan(&createSQL)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, nil, nil, nil, err
+		}
+		parsedChecks, parsedUniques := parseSQLiteConstraints(createSQL.String)
+		for _, check := range parsedChecks {
+			if !containsFold(hidden, check.Column) && !mentionsColumn(check.Definition, hidden) {
+				checks = append(checks, check)
+			}
+		}
+		for _, unique := range parsedUniques {
+			if !mentionsAnyColumn(unique.Columns, hidden) {
+				uniques = append(uniques, unique)
+			}
+		}
+		return checks, uniques, defaults, exclusions, nil
+	}
+
+	if query, args := s.queryBuilder.GetCheckConstraintsQuery(schema, tableName); query != "" {
+		err := s.scanConstraintRows(ctx, query, args, func(values []sql.NullString) {
+			check := CheckConstraint{
+				Name:       values[0].String,
+				Column:     values[1].String,
+				Definition: strings.TrimSpace(values[2].String),
+				Domain:     values[3].String,
+			}
+			if !containsFold(hidden, check.Column) && !mentionsColumn(check.Definition, hidden) {
+				checks = append(checks, check)
+			}
+		}, 4)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+	}
+
+	if query, args := s.queryBuilder.GetUniqueConstraintsQuery(schema, tableName); query != "" {
+		err := s.scanConstraintRows(ctx, query, args, func(values []sql.NullString) {
+			if n := len(uniques); n > 0 && uniques[n-1].Name == values[0].String {
+				uniques[n-1].Columns = append(uniques[n-1].Columns, values[1].String)
+				return
+			}
+			uniques = append(uniques, UniqueConstraint{Name: values[0].String, Columns: []string{values[1].String}})
+		}, 2)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+	}
+	visibleUniques := []UniqueConstraint{}
+	for _, unique := range uniques {
+		if !mentionsAnyColumn(unique.Columns, hidden) {
+			visibleUniques = append(visibleUniques, unique)
+		}
+	}
+	uniques = visibleUniques
+
+	if query, args := s.queryBuilder.GetDefaultConstraintsQuery(schema, tableName); query != "" {
+		err := s.scanConstraintRows(ctx, query, args, func(values []sql.NullString) {
+			if !containsFold(hidden, values[1].String) {
+				defaults = append(defaults, DefaultConstraint{Name: values[0].String, Column: values[1].String, Definition: values[2].String})
+			}
+		}, 3)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+	}
+	if query, args := s.queryBuilder.GetExclusionConstraintsQuery(schema, tableName); query != "" {
+		err := s.scanConstraintRows(ctx, query, args, func(values []sql.NullString) {
+			if !mentionsColumn(values[1].String, hidden) {
+				exclusions = append(exclusions, ExclusionConstraint{Name: values[0].String, Definition: values[1].String})
+			}
+		}, 2)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+	}
+	return checks, uniques, defaults, exclusions, nil
+}
+
+// scanConstraintRows calls add with the text columns of each row of a constraint query
+func (s *DbMCPServer) scanConstraintRows(ctx context.Context, query string, args []interface{}, add func([]sql.NullString), columns int) error {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	values := make([]sql.NullString, columns)
+	dest := make([]interface{}, columns)
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+		add(values)
+	}
+	return rows.Err()
+}
+
+// hiddenColumns returns the names of the columns left out of visible
+func hiddenColumns(all, visible []map[string]interface{}) []string {
+	var hidden []string
+	for _, col := range all {
+		name, _ := col["name"].(string)
+		found := false
+		for _, v := range visible {
+			if visibleName, _ := v["name"].(string); visibleName == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			hidden = append(hidden, name)
+		}
+	}
+	return hidden
+}
+
+// mentionsColumn reports whether an expression names one of the columns
+func mentionsColumn(expression string, columns []string) bool {
+	if len(columns) == 0 {
+		return false
+	}
+	tokens, err := tokenizeSQL(expression)
+	if err != nil {
+		return true
+	}
+	for _, token := range tokens {
+		if token.isIdentifier() && containsFold(columns, token.text) {
+			return true
+		}
+	}
+	return false
+}
+
+// mentionsAnyColumn reports whether names holds one of the columns
+func mentionsAnyColumn(names, columns []string) bool {
+	for _, name := range names {
+		if containsFold(columns, name) {
+			return true
+		}
+	}
+	return false
+}
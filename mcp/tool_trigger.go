@@ -71,12 +71,14 @@ func (s *DbMCPServer) handleListTriggers(ctx context.Context, request mcp.CallTo
 	ctx, cancel := context.WithTimeout(ctx, ShortQueryTimeout)
 	defer cancel()
 
+	auditStatement(ctx, query, queryArgs)
 	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrListingTriggers, err).Error()), nil
 	}
 	defer rows.Close()
 
+	policy := s.policy()
 	var triggers []map[string]interface{}
 	for rows.Next() {
 		var schemaName, triggerName, table string
@@ -86,6 +88,9 @@ func (s *DbMCPServer) handleListTriggers(ctx context.Context, request mcp.CallTo
 		if err = rows.Scan(&schemaName, &triggerName, &table, &isDisabled, &createDate, &modifyDate); err != nil {
 			continue
 		}
+		if !policy.TableAllowed(schemaName, triggerName) || !policy.TableAllowed(schemaName, table) {
+			continue
+		}
 
 		trigger := map[string]interface{}{
 			"schema":      schemaName,
@@ -172,7 +177,12 @@ func (s *DbMCPServer) handleGetTriggerCode(ctx context.Context, request mcp.Call
 	ctx, cancel := context.WithTimeout(ctx, ShortQueryTimeout)
 	defer cancel()
 
+	if !s.objectVisible(ctx, schema, triggerName) {
+		return mcp.NewToolResultError(ErrTriggerNotFound.Error()), nil
+	}
+
 	var definition sql.NullString
+	auditStatement(ctx, query, queryArgs)
 	err = s.db.QueryRowContext(ctx, query, queryArgs...).Scan(&definition)
 
 	if err == sql.ErrNoRows {
@@ -0,0 +1,261 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func (s *DbMCPServer) toolListTypes() (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.Tool{
+		Name: "list_types",
+		Description: "List user-defined types: enums with their values, domains and ranges with their base type and composite types " +
+			"with their attributes (PostgreSQL), object and collection types (Oracle), alias and table types (SQL Server). " +
+			"On MySQL, lists the ENUM and SET columns with their values",
+		InputSchema: mcp.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"schema": map[string]interface{}{
+					"type":        "string",
+					"description": "Schema name (optional)",
+				},
+				"name_filter": map[string]interface{}{
+					"type":        "string",
+					"description": "Filter by type name, or column name on MySQL (optional)",
+				},
+				"page": map[string]interface{}{
+					"type":        "number",
+					"description": "Page number (default: 1)",
+				},
+				"page_size": map[string]interface{}{
+					"type":        "number",
+					"description": "Items per page (default: 100, maximum: 500)",
+				},
+			},
+		},
+	}, s.handleListTypes
+}
+
+// userType is a row of the type listing
+type userType struct {
+	schema      string
+	name        string
+	kind        string
+	baseType    sql.NullString
+	description sql.NullString
+	table       sql.NullString
+}
+
+func (s *DbMCPServer) handleListTypes(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if err := s.requireConnection(); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	args, ok := getArgs(request.Params.Arguments)
+	if !ok {
+		return mcp.NewToolResultError(ErrInvalidArguments.Error()), nil
+	}
+
+	schema, err := getValidSchema(args, "")
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	nameFilter, _ := getStringArg(args, "name_filter")
+	pagination := GetPaginationParams(args, DefaultPageSize, MaxPageSize)
+
+	query, queryArgs := s.queryBuilder.ListTypesQuery(schema, nameFilter, pagination.PageSize, pagination.Offset)
+	if query == "" {
+		return mcp.NewToolResultError(ErrTypesNotSupported.Error()), nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ShortQueryTimeout)
+	defer cancel()
+
+	userTypes, err := s.fetchUserTypes(ctx, query, queryArgs)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrListingTypes, err).Error()), nil
+	}
+
+	var types []map[string]interface{}
+	for _, t := range userTypes {
+		entry := map[string]interface{}{
+			"schema": t.schema,
+			"name":   t.name,
+			"kind":   t.kind,
+		}
+		if t.description.Valid {
+			entry["description"] = t.description.String
+		}
+
+		switch {
+		case t.table.Valid:
+			// A MySQL ENUM or SET column: the values are part of the column type
+			entry["table"] = t.table.String
+			entry["values"] = parseMySQLEnumValues(t.baseType.String)
+		case t.kind == "enum":
+			values, err := s.fetchTypeValues(ctx, t.schema, t.name)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Errorf("%w: %s: %v", ErrListingTypes, t.name, err).Error()), nil
+			}
+			entry["values"] = values
+		default:
+			if t.baseType.Valid {
+				entry["base_type"] = t.baseType.String
+			}
+			if t.kind == "composite" || t.kind == "object" || t.kind == "table type" {
+				attributes, err := s.fetchTypeAttributes(ctx, t.schema, t.name)
+				if err != nil {
+					return mcp.NewToolResultError(fmt.Errorf("%w: %s: %v", ErrListingTypes, t.name, err).Error()), nil
+				}
+				entry["attributes"] = attributes
+			}
+		}
+		types = append(types, entry)
+	}
+
+	response := map[string]interface{}{
+		"types": types,
+		"pagination": map[string]interface{}{
+			"page":      pagination.Page,
+			"page_size": pagination.PageSize,
+			"count":     len(types),
+		},
+		"filter": map[string]interface{}{
+			"schema":      schema,
+			"name_filter": nameFilter,
+		},
+	}
+
+	jsonData, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
+
+// fetchUserTypes runs the type listing, leaving out the types hidden by the policy.
+// The rows are read in full before the values and attributes are queried.
+func (s *DbMCPServer) fetchUserTypes(ctx context.Context, query string, args []interface{}) ([]userType, error) {
+	auditStatement(ctx, query, args)
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	policy := s.policy()
+	var types []userType
+	for rows.Next() {
+		var t userType
+		if err := rows.Scan(&t.schema, &t.name, &t.kind, &t.baseType, &t.description, &t.table); err != nil {
+			return nil, err
+		}
+		if t.table.Valid {
+			if !policy.TableAllowed(t.schema, t.table.String) || !policy.ColumnAllowed(t.schema, t.table.String, t.name) {
+				continue
+			}
+		} else if !policy.TableAllowed(t.schema, t.name) {
+			continue
+		}
+		types = append(types, t)
+	}
+	return types, rows.Err()
+}
+
+// fetchTypeValues returns the labels of an enum type, in order
+func (s *DbMCPServer) fetchTypeValues(ctx context.Context, schema, typeName string) ([]string, error) {
+	query, args := s.queryBuilder.GetTypeValuesQuery(schema, typeName)
+	if query == "" {
+		return nil, nil
+	}
+
+	auditStatement(ctx, query, args)
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := []string{}
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
+
+// fetchTypeAttributes returns the name and type of the attributes of a composite type
+func (s *DbMCPServer) fetchTypeAttributes(ctx context.Context, schema, typeName string) ([]map[string]interface{}, error) {
+	query, args := s.queryBuilder.GetTypeAttributesQuery(schema, typeName)
+	if query == "" {
+		return nil, nil
+	}
+
+	auditStatement(ctx, query, args)
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attributes := []map[string]interface{}{}
+	for rows.Next() {
+		var name string
+		var dataType sql.NullString
+		if err := rows.Scan(&name, &dataType); err != nil {
+			return nil, err
+		}
+		attributes = append(attributes, map[string]interface{}{
+			"name": name,
+			"type": dataType.String,
+		})
+	}
+	return attributes, rows.Err()
+}
+
+// parseMySQLEnumValues returns the values of a MySQL ENUM or SET column type,
+// such as enum('small','large') or set('a','b')
+func parseMySQLEnumValues(columnType string) []string {
+	start := strings.Index(columnType, "(")
+	end := strings.LastIndex(columnType, ")")
+	if start < 0 || end <= start {
+		return nil
+	}
+	list := columnType[start+1 : end]
+
+	values := []string{}
+	var value strings.Builder
+	inQuote := false
+	for i := 0; i < len(list); i++ {
+		c := list[i]
+		switch {
+		case !inQuote:
+			if c == '\'' {
+				inQuote = true
+				value.Reset()
+			}
+		case c == '\\' && i+1 < len(list):
+			i++
+			value.WriteByte(list[i])
+		case c == '\'' && i+1 < len(list) && list[i+1] == '\'':
+			i++
+			value.WriteByte('\'')
+		case c == '\'':
+			inQuote = false
+			values = append(values, value.String())
+		default:
+			value.WriteByte(c)
+		}
+	}
+	return values
+}
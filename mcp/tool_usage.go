@@ -0,0 +1,36 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Tool: Get Usage
+func (s *DbMCPServer) toolGetUsage() (mcp.Tool, server.ToolHandlerFunc) {
+	return mcp.Tool{
+		Name:        "get_usage",
+		Description: "Returns the rate limits, concurrency limit and daily quotas of the active datasource, with today's usage (calls, rejected calls, rows returned, query time) for the datasource and the current session",
+		InputSchema: mcp.ToolInputSchema{
+			Type:       "object",
+			Properties: map[string]interface{}{},
+		},
+	}, s.handleGetUsage
+}
+
+func (s *DbMCPServer) handleGetUsage(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if s.datasource == "" {
+		return mcp.NewToolResultError(ErrNoConnection.Error()), nil
+	}
+
+	response := s.limiter.Usage(sessionID(ctx), s.profile, s.config.Limits(s.profile))
+
+	jsonData, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return mcp.NewToolResultError(ErrSerializingJSON.Error()), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonData)), nil
+}
@@ -13,7 +13,7 @@ import (
 func (s *DbMCPServer) toolListViews() (mcp.Tool, server.ToolHandlerFunc) {
 	return mcp.Tool{
 		Name:        "list_views",
-		Description: "List database views with pagination",
+		Description: "List database views with pagination, including materialized views (PostgreSQL, Oracle) and indexed views (SQL Server)",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
 			Properties: map[string]interface{}{
@@ -61,24 +61,30 @@ func (s *DbMCPServer) handleListViews(ctx context.Context, request mcp.CallToolR
 	ctx, cancel := context.WithTimeout(ctx, ShortQueryTimeout)
 	defer cancel()
 
+	auditStatement(ctx, query, queryArgs)
 	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrListingViews, err).Error()), nil
 	}
 	defer rows.Close()
 
+	policy := s.policy()
 	var views []map[string]interface{}
 	for rows.Next() {
-		var viewSchema, viewName string
-		var created, lastAltered sql.NullTime
+		var viewSchema, viewName, isMaterialized string
+		var created, lastAltered, lastRefresh sql.NullTime
 
-		if err = rows.Scan(&viewSchema, &viewName, &created, &lastAltered); err != nil {
+		if err = rows.Scan(&viewSchema, &viewName, &created, &lastAltered, &isMaterialized, &lastRefresh); err != nil {
+			continue
+		}
+		if !policy.TableAllowed(viewSchema, viewName) {
 			continue
 		}
 
 		view := map[string]interface{}{
-			"schema": viewSchema,
-			"name":   viewName,
+			"schema":       viewSchema,
+			"name":         viewName,
+			"materialized": isMaterialized == "YES",
 		}
 		if created.Valid {
 			view["created"] = created.Time.Format("2006-01-02 15:04:05")
@@ -86,6 +92,9 @@ func (s *DbMCPServer) handleListViews(ctx context.Context, request mcp.CallToolR
 		if lastAltered.Valid {
 			view["last_altered"] = lastAltered.Time.Format("2006-01-02 15:04:05")
 		}
+		if lastRefresh.Valid {
+			view["last_refresh"] = lastRefresh.Time.Format("2006-01-02 15:04:05")
+		}
 		views = append(views, view)
 	}
 
@@ -113,7 +122,7 @@ func (s *DbMCPServer) handleListViews(ctx context.Context, request mcp.CallToolR
 func (s *DbMCPServer) toolGetViewDefinition() (mcp.Tool, server.ToolHandlerFunc) {
 	return mcp.Tool{
 		Name:        "get_view_definition",
-		Description: "Returns the SQL definition of a view",
+		Description: "Returns the SQL definition of a view or materialized view, with the last refresh time where the database records it",
 		InputSchema: mcp.ToolInputSchema{
 			Type: "object",
 			Properties: map[string]interface{}{
@@ -157,24 +166,39 @@ func (s *DbMCPServer) handleGetViewDefinition(ctx context.Context, request mcp.C
 	ctx, cancel := context.WithTimeout(ctx, ShortQueryTimeout)
 	defer cancel()
 
-	var definition sql.NullString
-	err = s.db.QueryRowContext(ctx, query, queryArgs...).Scan(&definition)
-
-	if err == sql.ErrNoRows {
+	if !s.objectVisible(ctx, schema, viewName) {
 		return mcp.NewToolResultError(ErrViewNotFound.Error()), nil
 	}
+
+	definition, lastRefresh, materialized, err := s.materializedView(ctx, schema, viewName)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrRetrievingView, err).Error()), nil
 	}
 
+	if !materialized {
+		auditStatement(ctx, query, queryArgs)
+		err = s.db.QueryRowContext(ctx, query, queryArgs...).Scan(&definition)
+
+		if err == sql.ErrNoRows {
+			return mcp.NewToolResultError(ErrViewNotFound.Error()), nil
+		}
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Errorf("%w: %v", ErrRetrievingView, err).Error()), nil
+		}
+	}
+
 	if !definition.Valid || definition.String == "" {
 		return mcp.NewToolResultError(ErrDefinitionNotAvailable.Error()), nil
 	}
 
 	response := map[string]interface{}{
-		"schema":     schema,
-		"name":       viewName,
-		"definition": definition.String,
+		"schema":       schema,
+		"name":         viewName,
+		"definition":   definition.String,
+		"materialized": materialized,
+	}
+	if lastRefresh.Valid {
+		response["last_refresh"] = lastRefresh.Time.Format("2006-01-02 15:04:05")
 	}
 
 	jsonData, err := json.MarshalIndent(response, "", "  ")
@@ -184,3 +208,48 @@ func (s *DbMCPServer) handleGetViewDefinition(ctx context.Context, request mcp.C
 
 	return mcp.NewToolResultText(string(jsonData)), nil
 }
+
+// listSchemaViews returns the names of the views of a schema allowed by the policy
+func (s *DbMCPServer) listSchemaViews(ctx context.Context, schema string) ([]string, error) {
+	query, queryArgs := s.queryBuilder.ListViewsQuery(schema, "", MaxSchemaObjects, 0)
+
+	auditStatement(ctx, query, queryArgs)
+	rows, err := s.db.QueryContext(ctx, query, queryArgs...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	policy := s.policy()
+	var views []string
+	for rows.Next() {
+		var viewSchema, viewName, isMaterialized string
+		var created, lastAltered, lastRefresh sql.NullTime
+		if err = rows.Scan(&viewSchema, &viewName, &created, &lastAltered, &isMaterialized, &lastRefresh); err != nil {
+			return nil, err
+		}
+		if policy.TableAllowed(viewSchema, viewName) {
+			views = append(views, viewName)
+		}
+	}
+	return views, rows.Err()
+}
+
+// materializedView returns the definition and last refresh of a materialized view.
+// found is false when the view is not materialized or the database has none.
+func (s *DbMCPServer) materializedView(ctx context.Context, schema, viewName string) (definition sql.NullString, lastRefresh sql.NullTime, found bool, err error) {
+	query, queryArgs := s.queryBuilder.GetMaterializedViewQuery(schema, viewName)
+	if query == "" {
+		return definition, lastRefresh, false, nil
+	}
+
+	auditStatement(ctx, query, queryArgs)
+	err = s.db.QueryRowContext(ctx, query, queryArgs...).Scan(&definition, &lastRefresh)
+	if err == sql.ErrNoRows {
+		return definition, lastRefresh, false, nil
+	}
+	if err != nil {
+		return definition, lastRefresh, false, err
+	}
+	return definition, lastRefresh, true, nil
+}
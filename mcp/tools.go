@@ -17,10 +17,28 @@ func (s *DbMCPServer) registerTools() {
 	// List Supported Drivers
 	s.server.AddTool(s.toolListDrivers())
 
+	// Get Usage (rate limits and quotas)
+	s.server.AddTool(s.toolGetUsage())
+
 	// ===== Query Execution =====
 	// Execute Query
 	s.server.AddTool(s.toolExecuteQuery())
 
+	// Query Builder
+	s.server.AddTool(s.toolQueryBuilder())
+
+	// Explain Query
+	s.server.AddTool(s.toolExplainQuery())
+
+	// List Query History
+	s.server.AddTool(s.toolListQueryHistory())
+
+	// Get Query History Entry
+	s.server.AddTool(s.toolGetQueryHistoryEntry())
+
+	// Rerun Query
+	s.server.AddTool(s.toolRerunQuery())
+
 	// ===== Tables =====
 	// List Tables
 	s.server.AddTool(s.toolListTables())
@@ -34,6 +52,21 @@ func (s *DbMCPServer) registerTools() {
 	// Get Full Table Schema
 	s.server.AddTool(s.toolGetTableSchemaFull())
 
+	// Aggregate Table Rows
+	s.server.AddTool(s.toolAggregateTable())
+
+	// Generate DDL
+	s.server.AddTool(s.toolGenerateDDL())
+
+	// Export ER Diagram
+	s.server.AddTool(s.toolExportERDiagram())
+
+	// Get Dependencies
+	s.server.AddTool(s.toolGetDependencies())
+
+	// Get Dependents
+	s.server.AddTool(s.toolGetDependents())
+
 	// ===== Stored Procedures =====
 	// List Stored Procedures
 	s.server.AddTool(s.toolListProcedures())
@@ -65,10 +98,26 @@ func (s *DbMCPServer) registerTools() {
 	// Get Trigger Source Code
 	s.server.AddTool(s.toolGetTriggerCode())
 
+	// ===== Sequences and Types =====
+	// List Sequences
+	s.server.AddTool(s.toolListSequences())
+
+	// List Types
+	s.server.AddTool(s.toolListTypes())
+
 	// ===== Database Info =====
 	// Search Object
 	s.server.AddTool(s.toolSearchObjects())
 
 	// Get Database Information
 	s.server.AddTool(s.toolGetDatabaseInfo())
+
+	// Diff Schema
+	s.server.AddTool(s.toolDiffSchema())
+
+	// Snapshot Schema
+	s.server.AddTool(s.toolSnapshotSchema())
+
+	// List Snapshots
+	s.server.AddTool(s.toolListSnapshots())
 }
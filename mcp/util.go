@@ -73,6 +73,21 @@ func getBoolArg(args map[string]interface{}, key string, defaultVal bool) bool {
 	return defaultVal
 }
 
+// getStringSliceArg safely extracts an array of strings argument, ignoring non-string items
+func getStringSliceArg(args map[string]interface{}, key string) ([]string, bool) {
+	raw, ok := args[key].([]interface{})
+	if !ok {
+		return nil, false
+	}
+	var values []string
+	for _, item := range raw {
+		if str, ok := item.(string); ok {
+			values = append(values, str)
+		}
+	}
+	return values, true
+}
+
 // getArgs safely extracts arguments map from request
 func getArgs(arguments interface{}) (map[string]interface{}, bool) {
 	args, ok := arguments.(map[string]interface{})